@@ -0,0 +1,525 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A request made by a method of the client package, found by parsing its source.
+type packageEndpoint struct {
+	Method string
+	// path with its variable parts replaced by "{}"
+	Path string
+	// method making the request, e.g. Camera.GetAlerts
+	Func string
+	// types of the query params, request body, and response, or nil if there are none
+	Query    ast.Expr
+	Body     ast.Expr
+	Response ast.Expr
+}
+
+// Request functions of Client, with the argument positions of their method, params, body, and target (-1 if absent).
+var requestFuncs = map[string]struct{ method, params, body, target int }{
+	"MakeVerkadaRequestContext":           {1, 3, 4, 5},
+	"MakeVerkadaRequestWithFileContext":   {1, 3, -1, 6},
+	"MakeVerkadaRequestWithReaderContext": {1, 3, -1, 5},
+	"MakeVerkadaRequestForFileContext":    {1, 3, -1, -1},
+	"MakeVerkadaRequestToWriterContext":   {1, 3, -1, -1},
+	"downloadBytes":                       {-1, 2, -1, -1},
+}
+
+// The declarations of the client package needed to compare it with a document.
+type packageIndex struct {
+	types     map[string]ast.Expr
+	endpoints []*packageEndpoint
+}
+
+// Parses the non-test Go files of dir and finds the requests made by the methods of its product clients.
+func parsePackage(dir string) (*packageIndex, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	idx := &packageIndex{types: map[string]ast.Expr{}}
+	var funcs []*ast.FuncDecl
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						idx.types[ts.Name.Name] = ts.Type
+					}
+				}
+			case *ast.FuncDecl:
+				if productReceiver(decl) != "" && decl.Body != nil {
+					funcs = append(funcs, decl)
+				}
+			}
+		}
+	}
+	if len(funcs) == 0 {
+		return nil, fmt.Errorf("no product client methods found in %s", dir)
+	}
+
+	// unexported helpers such as alertsPages are reported as the exported method calling them
+	callers := map[string]string{}
+	for _, fn := range funcs {
+		if !fn.Name.IsExported() {
+			continue
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if sel, ok := call.Fun.(*ast.SelectorExpr); ok && isIdent(sel.X, "c") && !sel.Sel.IsExported() {
+					if _, ok := callers[sel.Sel.Name]; !ok {
+						callers[sel.Sel.Name] = strings.TrimSuffix(fn.Name.Name, "Context")
+					}
+				}
+			}
+			return true
+		})
+	}
+
+	for _, fn := range funcs {
+		name := strings.TrimSuffix(fn.Name.Name, "Context")
+		if caller, ok := callers[fn.Name.Name]; ok {
+			name = caller
+		}
+		name = productReceiver(fn) + "." + name
+		path := ""
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if len(n.Lhs) == 1 && len(n.Rhs) == 1 && isIdent(n.Lhs[0], "url") {
+					if p, ok := urlPath(n.Rhs[0]); ok {
+						path = p
+					}
+				}
+			case *ast.CallExpr:
+				sel, ok := n.Fun.(*ast.SelectorExpr)
+				if !ok || !isClientField(sel.X) {
+					return true
+				}
+				args, ok := requestFuncs[sel.Sel.Name]
+				if !ok || path == "" {
+					return true
+				}
+				ep := &packageEndpoint{Method: "GET", Path: path, Func: name}
+				if args.method >= 0 {
+					lit, ok := n.Args[args.method].(*ast.BasicLit)
+					if !ok {
+						return true
+					}
+					ep.Method, _ = strconv.Unquote(lit.Value)
+				}
+				ep.Query = argType(fn, n.Args[args.params])
+				if args.body >= 0 {
+					ep.Body = argType(fn, n.Args[args.body])
+				}
+				if args.target >= 0 {
+					ep.Response = argType(fn, n.Args[args.target])
+				}
+				idx.endpoints = append(idx.endpoints, ep)
+			}
+			return true
+		})
+	}
+	return idx, nil
+}
+
+// Returns the product of a method of a product client, e.g. Camera for *CameraClient, or "" for other functions.
+func productReceiver(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return ""
+	}
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return ""
+	}
+	id, ok := star.X.(*ast.Ident)
+	if !ok || id.Name == "Client" || !strings.HasSuffix(id.Name, "Client") {
+		return ""
+	}
+	return strings.TrimSuffix(id.Name, "Client")
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+// Reports whether e is c.client.
+func isClientField(e ast.Expr) bool {
+	sel, ok := e.(*ast.SelectorExpr)
+	return ok && isIdent(sel.X, "c") && sel.Sel.Name == "client"
+}
+
+// Returns the normalized path of a URL built as c.client.baseURL + "/literal" + variable + ..., with variables as "{}".
+func urlPath(e ast.Expr) (string, bool) {
+	var parts []ast.Expr
+	var flatten func(e ast.Expr)
+	flatten = func(e ast.Expr) {
+		if bin, ok := e.(*ast.BinaryExpr); ok && bin.Op == token.ADD {
+			flatten(bin.X)
+			flatten(bin.Y)
+			return
+		}
+		parts = append(parts, e)
+	}
+	flatten(e)
+	base, ok := parts[0].(*ast.SelectorExpr)
+	if !ok || !isClientField(base.X) || !strings.HasSuffix(base.Sel.Name, "URL") {
+		return "", false
+	}
+	var path strings.Builder
+	for _, part := range parts[1:] {
+		if lit, ok := part.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			s, _ := strconv.Unquote(lit.Value)
+			path.WriteString(s)
+		} else {
+			path.WriteString("{}")
+		}
+	}
+	return normalizePath(path.String()), true
+}
+
+// Returns the declared type of an argument such as *options, body, or &ret, or nil for nil or an unknown type.
+func argType(fn *ast.FuncDecl, e ast.Expr) ast.Expr {
+	switch v := e.(type) {
+	case *ast.StarExpr:
+		return argType(fn, v.X)
+	case *ast.UnaryExpr:
+		return argType(fn, v.X)
+	case *ast.CompositeLit:
+		return v.Type
+	case *ast.Ident:
+		if v.Name == "nil" {
+			return nil
+		}
+		return identType(fn, v.Name)
+	}
+	return nil
+}
+
+// Returns the type of a parameter or local variable of fn.
+func identType(fn *ast.FuncDecl, name string) ast.Expr {
+	for _, field := range fn.Type.Params.List {
+		for _, n := range field.Names {
+			if n.Name == name {
+				return field.Type
+			}
+		}
+	}
+	var typ ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if typ != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for _, n2 := range n.Names {
+				if n2.Name == name && n.Type != nil {
+					typ = n.Type
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if isIdent(lhs, name) && len(n.Rhs) == len(n.Lhs) {
+					typ = argType(fn, n.Rhs[i])
+				}
+			}
+		case *ast.FuncLit:
+			for _, field := range n.Type.Params.List {
+				for _, n2 := range field.Names {
+					if n2.Name == name {
+						typ = field.Type
+					}
+				}
+			}
+		}
+		return true
+	})
+	return typ
+}
+
+// Returns the struct type of a type expression, following pointers and named types.
+func (idx *packageIndex) structOf(e ast.Expr) *ast.StructType {
+	for depth := 0; depth < 16; depth++ {
+		switch t := e.(type) {
+		case *ast.StarExpr:
+			e = t.X
+		case *ast.Ident:
+			e = idx.types[t.Name]
+		case *ast.StructType:
+			return t
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// Returns the element type of a slice or array type, following pointers and named types.
+func (idx *packageIndex) elemOf(e ast.Expr) ast.Expr {
+	for depth := 0; depth < 16; depth++ {
+		switch t := e.(type) {
+		case *ast.StarExpr:
+			e = t.X
+		case *ast.Ident:
+			e = idx.types[t.Name]
+		case *ast.ArrayType:
+			return t.Elt
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// Returns the kind of a Go type in the terms of a schema: string, integer, number, boolean, array, object, or "" if
+// it cannot be compared (e.g. any or types from other packages).
+func (idx *packageIndex) kind(e ast.Expr) string {
+	for depth := 0; depth < 16; depth++ {
+		switch t := e.(type) {
+		case *ast.StarExpr:
+			e = t.X
+		case *ast.ArrayType:
+			return "array"
+		case *ast.MapType, *ast.StructType:
+			return "object"
+		case *ast.Ident:
+			switch t.Name {
+			case "string":
+				return "string"
+			case "bool":
+				return "boolean"
+			case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+				return "integer"
+			case "float32", "float64":
+				return "number"
+			}
+			next, ok := idx.types[t.Name]
+			if !ok {
+				return ""
+			}
+			e = next
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// A field of a struct by the name it has in requests or responses.
+type structField struct {
+	name string
+	typ  ast.Expr
+}
+
+// Returns the fields of a struct by their tag name (json or name), flattening embedded and untagged structs as the
+// encoders of the client package do.
+func (idx *packageIndex) fields(st *ast.StructType, key string) []structField {
+	var fields []structField
+	for _, f := range st.Fields.List {
+		tag := ""
+		if f.Tag != nil {
+			s, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflect.StructTag(s).Get(key)
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if tag == "" {
+			if nested := idx.structOf(f.Type); nested != nil && (len(f.Names) == 0 || key == "name") {
+				fields = append(fields, idx.fields(nested, key)...)
+				continue
+			}
+			if key == "name" {
+				continue
+			}
+		}
+		for _, n := range f.Names {
+			if name == "" && !n.IsExported() {
+				continue
+			}
+			fields = append(fields, structField{cmp.Or(name, n.Name), f.Type})
+		}
+		if len(f.Names) == 0 && name != "" {
+			fields = append(fields, structField{name, f.Type})
+		}
+	}
+	return fields
+}
+
+// Differences between a document and the client package, as sorted lines.
+type differ struct {
+	doc   *Document
+	idx   *packageIndex
+	lines map[string]bool
+}
+
+// Reports every difference between the endpoints of doc and the requests made by the client package in dir.
+func diff(doc *Document, dir string) ([]string, error) {
+	idx, err := parsePackage(dir)
+	if err != nil {
+		return nil, err
+	}
+	d := &differ{doc: doc, idx: idx, lines: map[string]bool{}}
+	spec := map[string]*endpoint{}
+	for _, ep := range doc.endpoints() {
+		spec[ep.Method+" "+normalizePath(ep.Path)] = ep
+	}
+	implemented := map[string]bool{}
+	for _, pe := range idx.endpoints {
+		key := pe.Method + " " + pe.Path
+		implemented[key] = true
+		ep, ok := spec[key]
+		if !ok {
+			d.report(pe.Method, pe.Path, pe.Func, "not in the spec")
+			continue
+		}
+		d.compare(ep, pe)
+	}
+	for key, ep := range spec {
+		if !implemented[key] {
+			d.report(ep.Method, ep.Path, "", fmt.Sprintf("not implemented (operation %s %q)", ep.OperationID, ep.Summary))
+		}
+	}
+	lines := make([]string, 0, len(d.lines))
+	for line := range d.lines {
+		lines = append(lines, line)
+	}
+	slices.SortFunc(lines, func(a, b string) int {
+		// by path, then method
+		_, pa, _ := strings.Cut(a, " ")
+		_, pb, _ := strings.Cut(b, " ")
+		return cmp.Or(strings.Compare(pa, pb), strings.Compare(a, b))
+	})
+	return lines, nil
+}
+
+func (d *differ) report(method string, path string, fn string, msg string) {
+	where := method + " " + path
+	if fn != "" {
+		where += " (" + fn + ")"
+	}
+	d.lines[where+": "+msg] = true
+}
+
+func (d *differ) compare(ep *endpoint, pe *packageEndpoint) {
+	report := func(format string, args ...any) {
+		d.report(pe.Method, ep.Path, pe.Func, fmt.Sprintf(format, args...))
+	}
+
+	// query parameters
+	var goQuery []structField
+	if st := d.idx.structOf(pe.Query); st != nil {
+		goQuery = d.idx.fields(st, "name")
+	}
+	specQuery := map[string]*Parameter{}
+	for _, p := range ep.Query {
+		specQuery[p.Name] = p
+	}
+	seen := map[string]bool{}
+	for _, f := range goQuery {
+		seen[f.name] = true
+		p, ok := specQuery[f.name]
+		if !ok {
+			report("query parameter %s (%s) is not in the spec", f.name, types.ExprString(f.typ))
+			continue
+		}
+		if want, got := d.doc.kind(p.Schema), d.idx.kind(f.typ); !compatible(want, got) {
+			report("query parameter %s is %s (%s) but %s in the spec", f.name, got, types.ExprString(f.typ), want)
+		}
+	}
+	for _, p := range ep.Query {
+		if !seen[p.Name] {
+			required := ""
+			if p.Required {
+				required = "required "
+			}
+			report("missing %squery parameter %s (%s)", required, p.Name, d.doc.kind(p.Schema))
+		}
+	}
+
+	// request body and response
+	switch {
+	case ep.Body != nil && pe.Body == nil && !ep.Upload:
+		report("sends no request body but the spec has one")
+	case ep.Body != nil && pe.Body != nil:
+		d.compareSchema(report, "request body", ep.Body, pe.Body, 0)
+	}
+	if ep.Response != nil && pe.Response != nil {
+		d.compareSchema(report, "response", ep.Response, pe.Response, 0)
+	}
+}
+
+// Compares the properties of an object schema with the json fields of a Go type, recursing into nested objects and arrays.
+func (d *differ) compareSchema(report func(string, ...any), where string, s *Schema, typ ast.Expr, depth int) {
+	s = d.doc.resolve(s)
+	if s == nil || depth > 8 {
+		return
+	}
+	switch d.doc.kind(s) {
+	case "array":
+		if elem := d.idx.elemOf(typ); elem != nil {
+			d.compareSchema(report, where+"[]", s.Items, elem, depth+1)
+		}
+		return
+	case "object":
+	default:
+		return
+	}
+	st := d.idx.structOf(typ)
+	if st == nil || s.Properties == nil {
+		return
+	}
+	seen := map[string]bool{}
+	for _, f := range d.idx.fields(st, "json") {
+		seen[f.name] = true
+		prop, ok := s.Properties[f.name]
+		if !ok {
+			report("%s field %s (%s) is not in the spec", where, f.name, types.ExprString(f.typ))
+			continue
+		}
+		want, got := d.doc.kind(prop), d.idx.kind(f.typ)
+		if !compatible(want, got) {
+			report("%s field %s is %s (%s) but %s in the spec", where, f.name, got, types.ExprString(f.typ), want)
+			continue
+		}
+		d.compareSchema(report, where+"."+f.name, prop, f.typ, depth+1)
+	}
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		if !seen[prop] {
+			props = append(props, prop)
+		}
+	}
+	slices.Sort(props)
+	for _, prop := range props {
+		report("%s is missing field %s (%s)", where, prop, d.doc.kind(s.Properties[prop]))
+	}
+}
+
+// Reports whether a Go kind can hold a value of a schema kind; unknown kinds are compatible with anything.
+func compatible(spec string, goKind string) bool {
+	return spec == "" || goKind == "" || spec == goKind || (spec == "integer" && goKind == "number")
+}
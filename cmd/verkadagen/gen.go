@@ -0,0 +1,437 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Generated files of one product, named like the files of the client package.
+type productFiles struct {
+	params    strings.Builder
+	responses strings.Builder
+	methods   strings.Builder
+}
+
+type generator struct {
+	doc *Document
+	pkg string
+	// type names already declared, so that nested and component types are declared once
+	types map[string]bool
+	// method names already declared, kept unique across products since their types share a package
+	names map[string]bool
+	files map[string]*productFiles
+}
+
+// Generates the option, body, and response structs and the methods of every operation of doc into dir,
+// as <product>_params.go, <product>_responses.go, and <product>_methods.go files of package pkg.
+func generate(doc *Document, dir string, pkg string) ([]string, error) {
+	g := &generator{doc: doc, pkg: pkg, types: map[string]bool{}, names: map[string]bool{}, files: map[string]*productFiles{}}
+	for _, ep := range doc.endpoints() {
+		g.endpoint(ep)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	products := make([]string, 0, len(g.files))
+	for product := range g.files {
+		products = append(products, product)
+	}
+	slices.Sort(products)
+	var written []string
+	for _, product := range products {
+		f := g.files[product]
+		prefix := filepath.Join(dir, strings.ToLower(product))
+		for _, file := range []struct {
+			suffix  string
+			imports string
+			body    string
+		}{
+			{"_params.go", "", f.params.String()},
+			{"_responses.go", "", f.responses.String()},
+			{"_methods.go", "import \"context\"\n\n", f.methods.String()},
+		} {
+			if file.body == "" {
+				continue
+			}
+			src := "// Code generated by verkadagen from an OpenAPI document. DO NOT EDIT.\n\npackage " + pkg + "\n\n" + file.imports + file.body
+			out, err := format.Source([]byte(src))
+			if err != nil {
+				return written, fmt.Errorf("formatting %s%s: %w", prefix, file.suffix, err)
+			}
+			if err := os.WriteFile(prefix+file.suffix, out, 0o644); err != nil {
+				return written, err
+			}
+			written = append(written, prefix+file.suffix)
+		}
+	}
+	return written, nil
+}
+
+func (g *generator) product(name string) *productFiles {
+	f, ok := g.files[name]
+	if !ok {
+		f = &productFiles{}
+		g.files[name] = f
+	}
+	return f
+}
+
+// An argument of a generated method.
+type argument struct {
+	name string
+	typ  string
+}
+
+func (g *generator) endpoint(ep *endpoint) {
+	f := g.product(ep.Product)
+	ep.Name = g.methodName(ep)
+
+	var args []argument
+	for _, p := range ep.PathParams {
+		args = append(args, argument{identifier(p.Name), "string"})
+	}
+
+	// query parameters: required ones are method arguments stored in unexported fields, optional ones are exported
+	var required []argument
+	optional := false
+	if len(ep.Query) > 0 {
+		fmt.Fprintf(&f.params, "type %sOptions struct {\n", ep.Name)
+		for _, p := range ep.Query {
+			if p.Required {
+				arg := argument{identifier(p.Name), g.queryType(p.Schema, false)}
+				required = append(required, arg)
+				fmt.Fprintf(&f.params, "\t%s %s `name:%q`\n", arg.name, arg.typ, p.Name)
+			} else {
+				optional = true
+				fmt.Fprintf(&f.params, "\t%s %s `name:%q`\n", fieldName(p.Name), g.queryType(p.Schema, true), p.Name)
+			}
+		}
+		f.params.WriteString("}\n\n")
+	}
+	args = append(args, required...)
+	if optional {
+		args = append(args, argument{"options", "*" + ep.Name + "Options"})
+	}
+
+	if ep.Body != nil {
+		typ := g.bodyType(ep, f)
+		args = append(args, argument{"body", typ})
+	}
+	if ep.Upload {
+		args = append(args, argument{"uploadFilename", "string"})
+	}
+	ret := g.responseType(ep, f)
+
+	// method and its Context variant, following the client package
+	var params, names []string
+	for _, arg := range args {
+		params = append(params, arg.name+" "+arg.typ)
+		names = append(names, arg.name)
+	}
+	client := ep.Product + "Client"
+	w := &f.methods
+	writeComment(w, ep.Description, ep.Summary)
+	if ep.OperationID != "" {
+		link := "[Verkada API Docs - " + ep.Summary + "]"
+		if ep.Summary == "" {
+			link = "[Verkada API Docs - " + ep.Name + "]"
+		}
+		fmt.Fprintf(w, "//\n// %s\n//\n// %s: https://apidocs.verkada.com/reference/%s\n", link, link, strings.ToLower(ep.OperationID))
+	}
+	fmt.Fprintf(w, "func (c *%s) %s(%s) (*%s, error) {\n", client, ep.Name, strings.Join(params, ", "), ret)
+	fmt.Fprintf(w, "\treturn c.%sContext(%s)\n}\n\n", ep.Name, strings.Join(append([]string{"context.Background()"}, names...), ", "))
+	fmt.Fprintf(w, "// Same as %s, with ctx controlling cancellation and deadlines of the underlying requests.\n", ep.Name)
+	fmt.Fprintf(w, "func (c *%s) %sContext(%s) (*%s, error) {\n", client, ep.Name, strings.Join(append([]string{"ctx context.Context"}, params...), ", "), ret)
+	fmt.Fprintf(w, "\tctx, span := c.client.startSpan(ctx, %q)\n\tdefer span.End()\n", ep.Product+"."+ep.Name)
+	queryArg := "nil"
+	if len(ep.Query) > 0 {
+		queryArg = "*options"
+		var fields, values []string
+		for _, arg := range required {
+			fields = append(fields, "options."+arg.name)
+			values = append(values, arg.name)
+		}
+		switch {
+		case optional:
+			fmt.Fprintf(w, "\tif options == nil {\n\t\toptions = &%sOptions{}\n\t}\n", ep.Name)
+			if len(required) > 0 {
+				fmt.Fprintf(w, "\t%s = %s\n", strings.Join(fields, ", "), strings.Join(values, ", "))
+			}
+		default:
+			var inits []string
+			for _, arg := range required {
+				inits = append(inits, arg.name+": "+arg.name)
+			}
+			fmt.Fprintf(w, "\toptions := &%sOptions{%s}\n", ep.Name, strings.Join(inits, ", "))
+		}
+	}
+	fmt.Fprintf(w, "\tvar ret %s\n", ret)
+	fmt.Fprintf(w, "\turl := %s\n", urlExpr(ep.Path))
+	switch {
+	case ep.Upload:
+		fmt.Fprintf(w, "\terr := c.client.MakeVerkadaRequestWithFileContext(ctx, %q, url, %s, uploadFilename, \"application/octet-stream\", &ret, 0)\n", ep.Method, queryArg)
+	case ep.Body != nil:
+		fmt.Fprintf(w, "\terr := c.client.MakeVerkadaRequestContext(ctx, %q, url, %s, body, &ret, 0)\n", ep.Method, queryArg)
+	default:
+		fmt.Fprintf(w, "\terr := c.client.MakeVerkadaRequestContext(ctx, %q, url, %s, nil, &ret, 0)\n", ep.Method, queryArg)
+	}
+	w.WriteString("\treturn &ret, err\n}\n\n")
+}
+
+// Returns a unique method name for an endpoint, from its summary or else its operation ID.
+func (g *generator) methodName(ep *endpoint) string {
+	name := typeName(ep.Summary)
+	if name == "" {
+		name = typeName(ep.OperationID)
+	}
+	if name == "" {
+		name = typeName(ep.Method + " " + ep.Path)
+	}
+	if strings.HasPrefix(ep.Path, "/v2/") && g.names[name] {
+		name += "V2"
+	}
+	unique := name
+	for i := 2; g.names[unique] || g.types[unique+"Options"] || g.types[unique+"Body"] || g.types[unique+"Response"]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+// Declares the request body type of an endpoint and returns the type of the body argument.
+func (g *generator) bodyType(ep *endpoint, f *productFiles) string {
+	s := g.doc.resolve(ep.Body)
+	if s == nil || s.Properties == nil {
+		return g.goType(ep.Body, ep.Name+"Body", &f.params, false)
+	}
+	name := ep.Name + "Body"
+	if ep.Body.Ref != "" {
+		name = typeName(refName(ep.Body.Ref))
+	}
+	g.declareStruct(name, s, &f.params, true)
+	return "*" + name
+}
+
+// Declares the response type of an endpoint and returns its name.
+func (g *generator) responseType(ep *endpoint, f *productFiles) string {
+	name := ep.Name + "Response"
+	s := g.doc.resolve(ep.Response)
+	switch {
+	case s == nil || (s.Properties == nil && g.doc.kind(s) == "object"):
+		g.types[name] = true
+		fmt.Fprintf(&f.responses, "type %s struct {\n}\n\n", name)
+	case s.Properties != nil:
+		if ep.Response.Ref != "" {
+			name = typeName(refName(ep.Response.Ref))
+		}
+		g.declareStruct(name, s, &f.responses, false)
+	default:
+		g.types[name] = true
+		fmt.Fprintf(&f.responses, "type %s %s\n\n", name, g.goType(ep.Response, ep.Name+"Item", &f.responses, false))
+	}
+	return name
+}
+
+// Declares a struct type for an object schema, unless a type of that name was already declared.
+// Body structs omit optional fields that are unset and use pointers for optional numbers and bools so that zero can be sent.
+func (g *generator) declareStruct(name string, s *Schema, w *strings.Builder, body bool) {
+	if g.types[name] {
+		return
+	}
+	g.types[name] = true
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	slices.Sort(props)
+	// nested types are declared after this one
+	var fields strings.Builder
+	fmt.Fprintf(&fields, "type %s struct {\n", name)
+	var nested strings.Builder
+	for _, prop := range props {
+		required := slices.Contains(s.Required, prop)
+		typ := g.goType(s.Properties[prop], name+fieldName(prop), &nested, body)
+		tag := prop
+		if body && !required {
+			tag += ",omitempty"
+			switch typ {
+			case "int", "int64", "float64", "bool":
+				typ = "*" + typ
+			}
+		}
+		fmt.Fprintf(&fields, "\t%s %s `json:%q`\n", fieldName(prop), typ, tag)
+	}
+	fields.WriteString("}\n\n")
+	w.WriteString(fields.String())
+	w.WriteString(nested.String())
+}
+
+// Returns the Go type of a schema, declaring named structs for objects with properties into w.
+// Objects referenced from components are named after the component, inline ones after where they appear (name).
+func (g *generator) goType(s *Schema, name string, w *strings.Builder, body bool) string {
+	if s == nil {
+		return "any"
+	}
+	if s.Ref != "" {
+		name = typeName(refName(s.Ref))
+	}
+	r := g.doc.resolve(s)
+	if r == nil {
+		return "any"
+	}
+	switch g.doc.kind(r) {
+	case "string":
+		return "string"
+	case "integer":
+		if r.Format == "int64" {
+			return "int64"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(r.Items, singular(name), w, body)
+	case "object":
+		if r.Properties == nil {
+			return "map[string]any"
+		}
+		g.declareStruct(name, r, w, body)
+		return name
+	}
+	return "any"
+}
+
+// Returns the Go type of a query parameter. Optional numbers and bools are pointers so that zero can be sent.
+func (g *generator) queryType(s *Schema, optional bool) string {
+	var typ string
+	switch g.doc.kind(s) {
+	case "integer":
+		typ = "int"
+		if r := g.doc.resolve(s); r.Format == "int64" {
+			typ = "int64"
+		}
+	case "number":
+		typ = "float64"
+	case "boolean":
+		typ = "bool"
+	case "array":
+		return "[]" + g.queryType(g.doc.resolve(s).Items, false)
+	default:
+		return "string"
+	}
+	if optional {
+		return "*" + typ
+	}
+	return typ
+}
+
+// Returns the expression building the URL of a path, with path parameters as the method arguments of the same name.
+func urlExpr(path string) string {
+	base := "c.client.baseURL"
+	if strings.HasPrefix(path, "/stream/") {
+		base = "c.client.streamingURL"
+	}
+	parts := []string{base}
+	for path != "" {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if start < 0 || end < start {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:start]))
+		}
+		parts = append(parts, identifier(path[start+1:end]))
+		path = path[end+1:]
+	}
+	return strings.Join(parts, " + ")
+}
+
+// Writes a doc comment from an operation's description, or its summary if it has none.
+func writeComment(w *strings.Builder, description string, summary string) {
+	text := description
+	if text == "" {
+		text = summary
+	}
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			w.WriteString("//\n")
+		} else {
+			w.WriteString("// " + line + "\n")
+		}
+	}
+}
+
+// Returns the field name of a JSON property or query parameter, following the client package: the name with its
+// first letter capitalized (e.g. next_page_token becomes Next_page_token).
+func fieldName(name string) string {
+	id := []rune(identifier(name))
+	if id[0] == '_' {
+		return "X" + string(id)
+	}
+	id[0] = unicode.ToUpper(id[0])
+	return string(id)
+}
+
+// Returns name with characters that cannot appear in a Go identifier replaced by underscores.
+func identifier(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_' || (i > 0 && unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case i == 0 && unicode.IsDigit(r):
+			b.WriteString("_")
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	id := b.String()
+	if id == "" {
+		return "_"
+	}
+	if token.IsKeyword(id) {
+		id += "_"
+	}
+	return id
+}
+
+// Returns an exported CamelCase name from words such as "Get All Access Groups" or "get-access-groups".
+// Words that are already capitalized, such as LPOI, are kept as they are.
+func typeName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		r := []rune(word)
+		if b.Len() == 0 && unicode.IsDigit(r[0]) {
+			b.WriteString("N")
+		}
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// Returns the singular of a plural field name, for the element types of arrays (e.g. Cameras becomes Camera).
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
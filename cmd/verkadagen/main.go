@@ -0,0 +1,71 @@
+// Verkadagen generates client code from a local copy of Verkada's OpenAPI document, and reports where the
+// hand-maintained client package disagrees with it.
+//
+// Usage:
+//
+//	verkadagen -spec openapi.json [-out generated] [-package client]
+//	verkadagen -spec openapi.json -diff [-pkg pkg/client]
+//
+// The first form writes <product>_params.go, <product>_responses.go, and <product>_methods.go files to the out
+// directory, with an options struct (query parameters), body struct, response struct, and method for every operation,
+// following the conventions of the client package. The files are scaffolding to compare with or copy into the package,
+// not a replacement for it: pagination, validation, and method names chosen by hand are not generated.
+//
+// With -diff, the Go files of the client package are parsed instead, and every difference is printed on its own line:
+// operations with no method, methods calling endpoints that are not in the document, and query parameters, request
+// body fields, and response fields that are missing, extra, or of a different type. The exit status is 1 if there are
+// any differences.
+//
+// Only JSON documents are supported; convert a YAML document first, e.g. with yq -o json.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("verkadagen: ")
+	spec := flag.String("spec", "", "path of the OpenAPI document (JSON)")
+	out := flag.String("out", "generated", "directory to write generated files to")
+	pkg := flag.String("package", "client", "package name of generated files")
+	diffMode := flag.Bool("diff", false, "report differences between the document and the client package instead of generating files")
+	pkgDir := flag.String("pkg", "pkg/client", "directory of the client package, for -diff")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage:\n\tverkadagen -spec openapi.json [-out dir] [-package name]\n\tverkadagen -spec openapi.json -diff [-pkg dir]\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *spec == "" || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	doc, err := loadDocument(*spec)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *diffMode {
+		lines, err := diff(doc, *pkgDir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		if len(lines) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	files, err := generate(doc, *out, *pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, file := range files {
+		fmt.Println(file)
+	}
+}
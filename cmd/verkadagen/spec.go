@@ -0,0 +1,345 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// The subset of an OpenAPI 3 document used by verkadagen.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components struct {
+		Schemas       map[string]*Schema      `json:"schemas"`
+		Parameters    map[string]*Parameter   `json:"parameters"`
+		RequestBodies map[string]*RequestBody `json:"requestBodies"`
+		Responses     map[string]*Response    `json:"responses"`
+	} `json:"components"`
+}
+
+type PathItem struct {
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Put        *Operation   `json:"put"`
+	Post       *Operation   `json:"post"`
+	Patch      *Operation   `json:"patch"`
+	Delete     *Operation   `json:"delete"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Ref      string               `json:"$ref"`
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref"`
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Type        schemaType         `json:"type"`
+	Format      string             `json:"format"`
+	Description string             `json:"description"`
+	Properties  map[string]*Schema `json:"properties"`
+	Items       *Schema            `json:"items"`
+	Required    []string           `json:"required"`
+	AllOf       []*Schema          `json:"allOf"`
+	OneOf       []*Schema          `json:"oneOf"`
+	AnyOf       []*Schema          `json:"anyOf"`
+	// only whether it is present matters
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+}
+
+// The type of a schema, which OpenAPI 3.1 allows to be a list such as ["string", "null"].
+type schemaType string
+
+func (t *schemaType) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*t = schemaType(s)
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return err
+	}
+	for _, s := range list {
+		if s != "null" {
+			*t = schemaType(s)
+			break
+		}
+	}
+	return nil
+}
+
+// Loads an OpenAPI document in JSON form.
+func loadDocument(path string) (*Document, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc Document
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("cannot parse %s (only JSON documents are supported): %w", path, err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("%s is not an OpenAPI 3 document", path)
+	}
+	return &doc, nil
+}
+
+// Returns the component a local reference such as "#/components/schemas/Camera" points to, by name.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// Follows $ref and merges allOf, returning a schema with its own properties. Returns nil for a nil schema.
+func (d *Document) resolve(s *Schema) *Schema {
+	for depth := 0; s != nil && s.Ref != "" && depth < 32; depth++ {
+		s = d.Components.Schemas[refName(s.Ref)]
+	}
+	if s == nil || len(s.AllOf) == 0 {
+		return s
+	}
+	merged := &Schema{Type: s.Type, Description: s.Description, Properties: map[string]*Schema{}, Required: slices.Clone(s.Required)}
+	for name, p := range s.Properties {
+		merged.Properties[name] = p
+	}
+	for _, part := range s.AllOf {
+		part = d.resolve(part)
+		if part == nil {
+			continue
+		}
+		if merged.Type == "" {
+			merged.Type = part.Type
+		}
+		for name, p := range part.Properties {
+			merged.Properties[name] = p
+		}
+		merged.Required = append(merged.Required, part.Required...)
+	}
+	if merged.Type == "" && len(merged.Properties) > 0 {
+		merged.Type = "object"
+	}
+	return merged
+}
+
+// Returns the kind of a schema for comparisons with Go types: string, integer, number, boolean, array, object, or "" if unknown.
+func (d *Document) kind(s *Schema) string {
+	s = d.resolve(s)
+	switch {
+	case s == nil:
+		return ""
+	case s.Type != "":
+		return string(s.Type)
+	case s.Properties != nil:
+		return "object"
+	case s.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+func (d *Document) parameter(p *Parameter) *Parameter {
+	if p != nil && p.Ref != "" {
+		return d.Components.Parameters[refName(p.Ref)]
+	}
+	return p
+}
+
+// An operation of the document with its references resolved.
+type endpoint struct {
+	Method      string
+	Path        string
+	Product     string
+	Name        string
+	Summary     string
+	Description string
+	OperationID string
+	PathParams  []*Parameter
+	Query       []*Parameter
+	// JSON request body, if any
+	Body *Schema
+	// whether the request body is multipart/form-data, i.e. a file upload
+	Upload bool
+	// JSON schema of the first 2xx response, if any
+	Response *Schema
+}
+
+var methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+func (p *PathItem) operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "PATCH":
+		return p.Patch
+	case "DELETE":
+		return p.Delete
+	}
+	return nil
+}
+
+// Returns every operation of the document, sorted by path and method.
+func (d *Document) endpoints() []*endpoint {
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	var eps []*endpoint
+	for _, path := range paths {
+		item := d.Paths[path]
+		for _, method := range methods {
+			op := item.operation(method)
+			if op == nil {
+				continue
+			}
+			ep := &endpoint{
+				Method:      method,
+				Path:        path,
+				Product:     productOf(path),
+				Summary:     strings.TrimSpace(op.Summary),
+				Description: strings.TrimSpace(op.Description),
+				OperationID: op.OperationID,
+			}
+			// operation parameters override path item parameters of the same name and location
+			params := map[string]*Parameter{}
+			var order []string
+			for _, p := range append(slices.Clone(item.Parameters), op.Parameters...) {
+				p = d.parameter(p)
+				if p == nil {
+					continue
+				}
+				key := p.In + " " + p.Name
+				if _, ok := params[key]; !ok {
+					order = append(order, key)
+				}
+				params[key] = p
+			}
+			for _, key := range order {
+				switch p := params[key]; p.In {
+				case "path":
+					ep.PathParams = append(ep.PathParams, p)
+				case "query":
+					ep.Query = append(ep.Query, p)
+				}
+			}
+			d.requestBody(ep, op.RequestBody)
+			ep.Response = d.responseSchema(op)
+			eps = append(eps, ep)
+		}
+	}
+	return eps
+}
+
+func (d *Document) requestBody(ep *endpoint, body *RequestBody) {
+	if body != nil && body.Ref != "" {
+		body = d.Components.RequestBodies[refName(body.Ref)]
+	}
+	if body == nil {
+		return
+	}
+	for contentType, media := range body.Content {
+		switch {
+		case strings.HasPrefix(contentType, "multipart/form-data"):
+			ep.Upload = true
+		case strings.Contains(contentType, "json"):
+			ep.Body = media.Schema
+		}
+	}
+}
+
+func (d *Document) responseSchema(op *Operation) *Schema {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		res := op.Responses[code]
+		if res != nil && res.Ref != "" {
+			res = d.Components.Responses[refName(res.Ref)]
+		}
+		if res == nil {
+			continue
+		}
+		for contentType, media := range res.Content {
+			if strings.Contains(contentType, "json") {
+				return media.Schema
+			}
+		}
+	}
+	return nil
+}
+
+// Product clients by path prefix, most specific first, following the client package.
+var products = []struct {
+	prefix  string
+	product string
+}{
+	{"/cameras/v1/video_tagging", "Helix"},
+	{"/cameras/", "Camera"},
+	{"/stream/", "Camera"},
+	{"/v2/analytics/", "Camera"},
+	{"/core/", "Core"},
+	{"/access/", "Access"},
+	{"/events/", "Access"},
+	{"/environment/", "Sensor"},
+	{"/guest/", "Guest"},
+	{"/v2/guest/", "Guest"},
+	{"/alarms/", "ClassicAlarms"},
+	{"/viewing_station/", "VX"},
+}
+
+func productOf(path string) string {
+	for _, p := range products {
+		if strings.HasPrefix(path, p.prefix) {
+			return p.product
+		}
+	}
+	return "Core"
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// Returns a path with its parameters replaced by "{}", so that paths from the document and the package can be compared.
+func normalizePath(path string) string {
+	return strings.TrimSuffix(pathParam.ReplaceAllString(path, "{}"), "/")
+}
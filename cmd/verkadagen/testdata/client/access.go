@@ -0,0 +1,77 @@
+// A client package drifting from openapi.json, for the tests of -diff. It is parsed, not compiled.
+package client
+
+import "context"
+
+type AdminUnlockDoorResponse struct {
+	Door_id         string `json:"door_id"`
+	Unlock_duration int    `json:"unlock_duration"`
+}
+
+type UserUnlockDoorOptions struct {
+	User_id     string `name:"user_id"`
+	External_id string `name:"external_id"`
+}
+
+type GetDoorsOptions struct {
+	Door_ids []string `name:"door_ids"`
+	Site_ids string   `name:"site_ids"`
+}
+
+type GetDoorsResponse struct {
+	Doors []Door `json:"doors"`
+}
+
+type Door struct {
+	Door_id string `json:"door_id"`
+	Name    int    `json:"name"`
+}
+
+func (c *AccessClient) AdminUnlockDoorContext(ctx context.Context, door_id string) (*AdminUnlockDoorResponse, error) {
+	body := struct {
+		Door_id string `json:"door_id"`
+	}{
+		Door_id: door_id,
+	}
+	var ret AdminUnlockDoorResponse
+	url := c.client.baseURL + "/access/v1/door/admin_unlock"
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
+	return &ret, err
+}
+
+// Calls the admin endpoint instead of user_unlock.
+func (c *AccessClient) UserUnlockDoorContext(ctx context.Context, door_id string, options *UserUnlockDoorOptions) (*AdminUnlockDoorResponse, error) {
+	body := struct {
+		Door_id     string `json:"door_id"`
+		User_id     string `json:"user_id,omitempty"`
+		External_id string `json:"external_id,omitempty"`
+	}{
+		Door_id:     door_id,
+		User_id:     options.User_id,
+		External_id: options.External_id,
+	}
+	var ret AdminUnlockDoorResponse
+	url := c.client.baseURL + "/access/v1/door/admin_unlock"
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
+	return &ret, err
+}
+
+func (c *AccessClient) GetDoorsContext(ctx context.Context, options *GetDoorsOptions) (*GetDoorsResponse, error) {
+	var ret GetDoorsResponse
+	url := c.client.baseURL + "/access/v1/doors"
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
+	return &ret, err
+}
+
+func (c *AccessClient) GetDoorContext(ctx context.Context, door_id string) (*Door, error) {
+	var ret Door
+	url := c.client.baseURL + "/access/v1/doors/" + door_id
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
+	return &ret, err
+}
+
+// Not a product client method, so not compared.
+func (c *Client) GetToken(ctx context.Context) error {
+	url := c.baseURL + "/token"
+	return c.MakeVerkadaRequestContext(ctx, "POST", url, nil, nil, nil, 0)
+}
@@ -0,0 +1,9 @@
+package client
+
+import "context"
+
+// Test files are not part of the package compared with the document.
+func (c *AccessClient) testRequest() error {
+	url := c.client.baseURL + "/access/v1/test_only"
+	return c.client.MakeVerkadaRequestContext(context.Background(), "GET", url, nil, nil, nil, 0)
+}
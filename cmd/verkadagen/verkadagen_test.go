@@ -0,0 +1,211 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Declarations of the client package that generated methods use, to type-check them on their own.
+const clientStub = `package client
+
+import "context"
+
+type Client struct {
+	baseURL      string
+	streamingURL string
+}
+
+type AccessClient struct {
+	client *Client
+}
+
+type span struct{}
+
+func (span) End() {}
+
+func (c *Client) startSpan(ctx context.Context, name string) (context.Context, span) {
+	return ctx, span{}
+}
+
+func (c *Client) MakeVerkadaRequestContext(ctx context.Context, method string, url string, params any, body any, target any, retry int) error {
+	return nil
+}
+`
+
+func TestGenerate(t *testing.T) {
+	doc, err := loadDocument("testdata/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files, err := generate(doc, dir, "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"access_params.go", "access_responses.go", "access_methods.go"}
+	for i, file := range want {
+		want[i] = filepath.Join(dir, file)
+	}
+	if !slices.Equal(files, want) {
+		t.Fatalf("wrote %q, want %q", files, want)
+	}
+
+	// the generated files compile against the client package
+	if err := os.WriteFile(filepath.Join(dir, "stub.go"), []byte(clientStub), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for _, file := range append(files, filepath.Join(dir, "stub.go")) {
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed = append(parsed, f)
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("client", fset, parsed, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signatures := []struct {
+		name string
+		want string
+	}{
+		{"UnlockDoorAsAdministrator", "func(body *client.UnlockRequest) (*client.UnlockDoorAsAdministratorResponse, error)"},
+		{"UnlockDoorAsUserContext", "func(ctx context.Context, body *client.UnlockDoorAsUserBody) (*client.UnlockDoorAsUserResponse, error)"},
+		{"GetDoors", "func(page_size int, options *client.GetDoorsOptions) (*client.GetDoorsResponse, error)"},
+		{"DeleteDoor", "func(door_id string) (*client.DeleteDoorResponse, error)"},
+	}
+	access := types.NewPointer(pkg.Scope().Lookup("AccessClient").Type())
+	for _, tt := range signatures {
+		obj, _, _ := types.LookupFieldOrMethod(access, false, pkg, tt.name)
+		if obj == nil {
+			t.Errorf("no method %s", tt.name)
+			continue
+		}
+		if got := types.TypeString(obj.Type(), nil); got != tt.want {
+			t.Errorf("%s is %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	fields := []struct {
+		typ  string
+		want string
+	}{
+		// optional body fields are omitted when empty, and allOf is merged
+		{"UnlockDoorAsUserBody", `struct{Door_id string "json:\"door_id\""; External_id string "json:\"external_id,omitempty\""; User_id string "json:\"user_id,omitempty\""}`},
+		// required query parameters are arguments, stored in unexported fields
+		{"GetDoorsOptions", `struct{Door_ids []string "name:\"door_ids\""; Site_ids []string "name:\"site_ids\""; page_size int "name:\"page_size\""}`},
+		// inline objects are named after where they appear
+		{"Door", `struct{Door_id string "json:\"door_id\""; Name string "json:\"name\""; Site client.DoorSite "json:\"site\""}`},
+	}
+	for _, tt := range fields {
+		obj := pkg.Scope().Lookup(tt.typ)
+		if obj == nil {
+			t.Errorf("no type %s", tt.typ)
+			continue
+		}
+		if got := types.TypeString(obj.Type().Underlying(), nil); got != tt.want {
+			t.Errorf("%s is %s, want %s", tt.typ, got, tt.want)
+		}
+	}
+
+	// and agree with the document they were generated from
+	lines, err := diff(doc, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) > 0 {
+		t.Errorf("generated package differs from the document:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	doc, err := loadDocument("testdata/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := diff(doc, "testdata/client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		// UserUnlockDoor calls the admin endpoint
+		`POST /access/v1/door/admin_unlock (Access.UserUnlockDoor): request body field external_id (string) is not in the spec`,
+		`POST /access/v1/door/admin_unlock (Access.UserUnlockDoor): request body field user_id (string) is not in the spec`,
+		`POST /access/v1/door/user_unlock: not implemented (operation postAccessUserApiUnlockViewV1 "Unlock Door as User")`,
+		`GET /access/v1/doors (Access.GetDoors): missing required query parameter page_size (integer)`,
+		`GET /access/v1/doors (Access.GetDoors): query parameter site_ids is string (string) but array in the spec`,
+		`GET /access/v1/doors (Access.GetDoors): response.doors[] field name is integer (int) but string in the spec`,
+		`GET /access/v1/doors (Access.GetDoors): response.doors[] is missing field site (object)`,
+		`DELETE /access/v1/doors/{door_id}: not implemented (operation deleteAccessDoorViewV1 "Delete Door")`,
+		`GET /access/v1/doors/{} (Access.GetDoor): not in the spec`,
+	}
+	for i := range max(len(lines), len(want)) {
+		var got, w string
+		if i < len(lines) {
+			got = lines[i]
+		}
+		if i < len(want) {
+			w = want[i]
+		}
+		if got != w {
+			t.Errorf("line %d:\n got %s\nwant %s", i, got, w)
+		}
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	doc, err := loadDocument("testdata/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		dir  string
+	}{
+		{"no product client methods", "testdata"},
+		{"missing directory", "testdata/missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := diff(doc, tt.dir); err == nil {
+				t.Error("got no error")
+			}
+		})
+	}
+}
+
+func TestLoadDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"openapi 3", `{"openapi": "3.0.3", "paths": {}}`, false},
+		{"swagger 2", `{"swagger": "2.0", "paths": {}}`, true},
+		{"yaml", "openapi: 3.0.3\npaths: {}\n", true},
+		{"nullable type list", `{"openapi": "3.1.0", "components": {"schemas": {"A": {"type": ["null", "string"]}}}}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "openapi.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadDocument(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
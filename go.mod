@@ -1,3 +1,9 @@
 module github.com/GDRCode/verkada-api-go
 
 go 1.24.2
+
+require (
+	go.opentelemetry.io/otel v1.37.0
+	go.opentelemetry.io/otel/metric v1.37.0
+	go.opentelemetry.io/otel/trace v1.37.0
+)
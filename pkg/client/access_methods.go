@@ -1,7 +1,8 @@
 package client
 
 import (
-	"fmt"
+	"context"
+	"io"
 	"strings"
 )
 
@@ -11,9 +12,16 @@ import (
 //
 // [Verkada API Docs - Get All Access Groups]: https://apidocs.verkada.com/reference/getaccessgroupsviewv1
 func (c *AccessClient) GetAllAccessGroups() (*GetAllAccessGroupsResponse, error) {
+	return c.GetAllAccessGroupsContext(context.Background())
+}
+
+// Same as GetAllAccessGroups, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAllAccessGroupsContext(ctx context.Context) (*GetAllAccessGroupsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAllAccessGroups")
+	defer span.End()
 	var ret GetAllAccessGroupsResponse
 	url := c.client.baseURL + "/access/v1/access_groups"
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -23,10 +31,17 @@ func (c *AccessClient) GetAllAccessGroups() (*GetAllAccessGroupsResponse, error)
 //
 // [Verkada API Docs - Delete Access Group]: https://apidocs.verkada.com/reference/deleteaccessgroupviewv1
 func (c *AccessClient) DeleteAccessGroups(group_id string) (*DeleteAccessGroupResponse, error) {
+	return c.DeleteAccessGroupsContext(context.Background(), group_id)
+}
+
+// Same as DeleteAccessGroups, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteAccessGroupsContext(ctx context.Context, group_id string) (*DeleteAccessGroupResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteAccessGroups")
+	defer span.End()
 	options := &DeleteAccessGroupOptions{group_id: group_id}
 	var ret DeleteAccessGroupResponse
 	url := c.client.baseURL + "/access/v1/access_groups/group"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -36,10 +51,17 @@ func (c *AccessClient) DeleteAccessGroups(group_id string) (*DeleteAccessGroupRe
 //
 // [Verkada API Docs - Get Access Group]: https://apidocs.verkada.com/reference/getaccessgroupviewv1
 func (c *AccessClient) GetAccessGroup(group_id string) (*AccessGroup, error) {
+	return c.GetAccessGroupContext(context.Background(), group_id)
+}
+
+// Same as GetAccessGroup, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAccessGroupContext(ctx context.Context, group_id string) (*AccessGroup, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAccessGroup")
+	defer span.End()
 	options := &GetAccessGroupOptions{group_id: group_id}
 	var ret AccessGroup
 	url := c.client.baseURL + "/access/v1/access_groups/group"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -50,10 +72,17 @@ func (c *AccessClient) GetAccessGroup(group_id string) (*AccessGroup, error) {
 //
 // [Verkada API Docs - Create Access Group]: https://apidocs.verkada.com/reference/postaccessgroupviewv1
 func (c *AccessClient) CreateAccessGroup(name string) (*AccessGroup, error) {
+	return c.CreateAccessGroupContext(context.Background(), name)
+}
+
+// Same as CreateAccessGroup, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) CreateAccessGroupContext(ctx context.Context, name string) (*AccessGroup, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.CreateAccessGroup")
+	defer span.End()
 	body := &CreateAccessGroupBody{Name: name}
 	var ret AccessGroup
 	url := c.client.baseURL + "/access/v1/access_groups/group"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -63,17 +92,24 @@ func (c *AccessClient) CreateAccessGroup(name string) (*AccessGroup, error) {
 //
 // [Verkada API Docs - Remove User From Access Group]: https://apidocs.verkada.com/reference/deleteaccessgroupuserviewv1
 func (c *AccessClient) RemoveUserFromAccessGroup(group_id string, options *RemoveUserFromAccessGroupOptions) (*RemoveUserFromAccessGroupResponse, error) {
+	return c.RemoveUserFromAccessGroupContext(context.Background(), group_id, options)
+}
+
+// Same as RemoveUserFromAccessGroup, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) RemoveUserFromAccessGroupContext(ctx context.Context, group_id string, options *RemoveUserFromAccessGroupOptions) (*RemoveUserFromAccessGroupResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.RemoveUserFromAccessGroup")
+	defer span.End()
 	if options == nil {
 		options = &RemoveUserFromAccessGroupOptions{}
 	}
 	options.group_id = group_id
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret RemoveUserFromAccessGroupResponse
 	url := c.client.baseURL + "/access/v1/access_groups/group/user"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -83,17 +119,24 @@ func (c *AccessClient) RemoveUserFromAccessGroup(group_id string, options *Remov
 //
 // [Verkada API Docs - Add User to Access Group]: https://apidocs.verkada.com/reference/putaccessgroupuserviewv1
 func (c *AccessClient) AddUserToAccessGroup(group_id string, body *AddUserToAccessGroupBody) (*AddUserToAccessGroupResponse, error) {
+	return c.AddUserToAccessGroupContext(context.Background(), group_id, body)
+}
+
+// Same as AddUserToAccessGroup, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) AddUserToAccessGroupContext(ctx context.Context, group_id string, body *AddUserToAccessGroupBody) (*AddUserToAccessGroupResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.AddUserToAccessGroup")
+	defer span.End()
 	options := &AddUserToAccessGroupOptions{group_id: group_id}
 	if body == nil {
 		body = &AddUserToAccessGroupBody{}
 	}
 	// should not use both external_id and user_id, but need at least one
 	if (body.External_id == "") == (body.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", body.External_id, body.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", body.External_id, body.User_id)
 	}
 	var ret AddUserToAccessGroupResponse
 	url := c.client.baseURL + "/access/v1/access_groups/group/user"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, body, &ret, 0)
 	return &ret, err
 }
 
@@ -103,9 +146,16 @@ func (c *AccessClient) AddUserToAccessGroup(group_id string, body *AddUserToAcce
 //
 // [Verkada API Docs - Get All Access Users]: https://apidocs.verkada.com/reference/getaccessmembersviewv1
 func (c *AccessClient) GetAllAccessUsers() (*GetAllAccessUsersResponse, error) {
+	return c.GetAllAccessUsersContext(context.Background())
+}
+
+// Same as GetAllAccessUsers, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAllAccessUsersContext(ctx context.Context) (*GetAllAccessUsersResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAllAccessUsers")
+	defer span.End()
 	var ret GetAllAccessUsersResponse
 	url := c.client.baseURL + "/access/v1/access_users"
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -115,6 +165,13 @@ func (c *AccessClient) GetAllAccessUsers() (*GetAllAccessUsersResponse, error) {
 //
 // [Verkada API Docs - Get Access Information Object]: https://apidocs.verkada.com/reference/getaccessuserviewv1
 func (c *AccessClient) GetAccessInformationObject(options *GetAccessInformationObjectOptions) (*AccessInformationObject, error) {
+	return c.GetAccessInformationObjectContext(context.Background(), options)
+}
+
+// Same as GetAccessInformationObject, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAccessInformationObjectContext(ctx context.Context, options *GetAccessInformationObjectOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAccessInformationObject")
+	defer span.End()
 	if options == nil {
 		options = &GetAccessInformationObjectOptions{}
 	}
@@ -125,11 +182,11 @@ func (c *AccessClient) GetAccessInformationObject(options *GetAccessInformationO
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -139,6 +196,13 @@ func (c *AccessClient) GetAccessInformationObject(options *GetAccessInformationO
 //
 // [Verkada API Docs - Activate BLE for Access User]: https://apidocs.verkada.com/reference/putactivateblemethodviewv1
 func (c *AccessClient) ActivateUserBLE(options *ActivateUserBLEOptions) (*AccessInformationObject, error) {
+	return c.ActivateUserBLEContext(context.Background(), options)
+}
+
+// Same as ActivateUserBLE, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) ActivateUserBLEContext(ctx context.Context, options *ActivateUserBLEOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.ActivateUserBLE")
+	defer span.End()
 	if options == nil {
 		options = &ActivateUserBLEOptions{}
 	}
@@ -149,11 +213,11 @@ func (c *AccessClient) ActivateUserBLE(options *ActivateUserBLEOptions) (*Access
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/ble/activate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -163,6 +227,13 @@ func (c *AccessClient) ActivateUserBLE(options *ActivateUserBLEOptions) (*Access
 //
 // [Verkada API Docs - Deactivate BLE for Access User]: https://apidocs.verkada.com/reference/putdeactivateblemethodviewv1
 func (c *AccessClient) DeactivateUserBLE(options *DeactivateUserBLEOptions) (*AccessInformationObject, error) {
+	return c.DeactivateUserBLEContext(context.Background(), options)
+}
+
+// Same as DeactivateUserBLE, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeactivateUserBLEContext(ctx context.Context, options *DeactivateUserBLEOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeactivateUserBLE")
+	defer span.End()
 	if options == nil {
 		options = &DeactivateUserBLEOptions{}
 	}
@@ -173,11 +244,11 @@ func (c *AccessClient) DeactivateUserBLE(options *DeactivateUserBLEOptions) (*Ac
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/ble/deactivate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -188,6 +259,13 @@ func (c *AccessClient) DeactivateUserBLE(options *DeactivateUserBLEOptions) (*Ac
 //
 // [Verkada API Docs - Set End Date for User]: https://apidocs.verkada.com/reference/putaccessenddateviewv1
 func (c *AccessClient) SetUserEndDate(end_date string, options *SetUserEndDateOptions) (*AccessInformationObject, error) {
+	return c.SetUserEndDateContext(context.Background(), end_date, options)
+}
+
+// Same as SetUserEndDate, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) SetUserEndDateContext(ctx context.Context, end_date string, options *SetUserEndDateOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.SetUserEndDate")
+	defer span.End()
 	body := struct {
 		End_date string `json:"end_date"`
 	}{
@@ -203,11 +281,11 @@ func (c *AccessClient) SetUserEndDate(end_date string, options *SetUserEndDateOp
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/end_date"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, body, &ret, 0)
 	return &ret, err
 }
 
@@ -217,6 +295,13 @@ func (c *AccessClient) SetUserEndDate(end_date string, options *SetUserEndDateOp
 //
 // [Verkada API Docs - Remove Entry Code for User]: https://apidocs.verkada.com/reference/deleteaccessuserpinviewv1
 func (c *AccessClient) RemoveUserEntryCode(options *RemoveUserEntryCodeOptions) (*RemoveUserEntryCodeResponse, error) {
+	return c.RemoveUserEntryCodeContext(context.Background(), options)
+}
+
+// Same as RemoveUserEntryCode, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) RemoveUserEntryCodeContext(ctx context.Context, options *RemoveUserEntryCodeOptions) (*RemoveUserEntryCodeResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.RemoveUserEntryCode")
+	defer span.End()
 	if options == nil {
 		options = &RemoveUserEntryCodeOptions{}
 	}
@@ -227,11 +312,11 @@ func (c *AccessClient) RemoveUserEntryCode(options *RemoveUserEntryCodeOptions)
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret RemoveUserEntryCodeResponse
 	url := c.client.baseURL + "/access/v1/access_users/user/entry_code"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -241,6 +326,13 @@ func (c *AccessClient) RemoveUserEntryCode(options *RemoveUserEntryCodeOptions)
 //
 // [Verkada API Docs - Set Entry Code for User]: https://apidocs.verkada.com/reference/putaccessuserpinviewv1
 func (c *AccessClient) SetUserEntryCode(entry_code string, options *SetUserEntryCodeOptions) (*AccessInformationObject, error) {
+	return c.SetUserEntryCodeContext(context.Background(), entry_code, options)
+}
+
+// Same as SetUserEntryCode, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) SetUserEntryCodeContext(ctx context.Context, entry_code string, options *SetUserEntryCodeOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.SetUserEntryCode")
+	defer span.End()
 	body := struct {
 		Entry_code string `json:"entry_code"`
 	}{
@@ -256,11 +348,11 @@ func (c *AccessClient) SetUserEntryCode(entry_code string, options *SetUserEntry
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/entry_code"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, body, &ret, 0)
 	return &ret, err
 }
 
@@ -270,6 +362,13 @@ func (c *AccessClient) SetUserEntryCode(entry_code string, options *SetUserEntry
 //
 // [Verkada API Docs - Send Pass App Invite for User]: https://apidocs.verkada.com/reference/postsendpassappinviteviewv1
 func (c *AccessClient) SendPassInvite(options *SendPassInviteOptions) (*AccessInformationObject, error) {
+	return c.SendPassInviteContext(context.Background(), options)
+}
+
+// Same as SendPassInvite, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) SendPassInviteContext(ctx context.Context, options *SendPassInviteOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.SendPassInvite")
+	defer span.End()
 	if options == nil {
 		options = &SendPassInviteOptions{}
 	}
@@ -280,11 +379,11 @@ func (c *AccessClient) SendPassInvite(options *SendPassInviteOptions) (*AccessIn
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/pass/invite"
-	err := c.client.MakeVerkadaRequest("POST", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -294,16 +393,23 @@ func (c *AccessClient) SendPassInvite(options *SendPassInviteOptions) (*AccessIn
 //
 // [Verkada API Docs - Delete Profile Photo]: https://apidocs.verkada.com/reference/deleteprofilephotoviewv1
 func (c *AccessClient) DeleteProfilePhoto(options *DeleteProfilePhotoOptions) (*DeleteProfilePhotoResponse, error) {
+	return c.DeleteProfilePhotoContext(context.Background(), options)
+}
+
+// Same as DeleteProfilePhoto, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteProfilePhotoContext(ctx context.Context, options *DeleteProfilePhotoOptions) (*DeleteProfilePhotoResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteProfilePhoto")
+	defer span.End()
 	if options == nil {
 		options = &DeleteProfilePhotoOptions{}
 	}
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret DeleteProfilePhotoResponse
 	url := c.client.baseURL + "/access/v1/access_users/user/profile_photo"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -313,45 +419,133 @@ func (c *AccessClient) DeleteProfilePhoto(options *DeleteProfilePhotoOptions) (*
 //
 // [Verkada API Docs - Get Profile Photo]: https://apidocs.verkada.com/reference/getprofilephotoviewv1
 func (c *AccessClient) GetProfilePhoto(options *GetProfilePhotoOptions, filename string) error {
+	return c.GetProfilePhotoContext(context.Background(), options, filename)
+}
+
+// Same as GetProfilePhoto, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetProfilePhotoContext(ctx context.Context, options *GetProfilePhotoOptions, filename string) error {
+	ctx, span := c.client.startSpan(ctx, "Access.GetProfilePhoto")
+	defer span.End()
 	if options == nil {
 		options = &GetProfilePhotoOptions{}
 	}
-	// should not use both external_id and user_id, but need at least one
-	if (options.External_id == "") == (options.User_id == "") {
-		return fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+	if err := validateGetProfilePhotoOptions(options); err != nil {
+		return err
 	}
 	// filename validation and replacement if left blank
 	if filename == "" {
 		filename = "profilephoto.jpg"
 	}
 	if !strings.HasSuffix(filename, ".jpg") {
-		return fmt.Errorf("included filename is not blank but does not end with \".jpg\" - received %s", filename)
+		return validationErrorf("included filename is not blank but does not end with \".jpg\" - received %s", filename)
 	}
 	url := c.client.baseURL + "/access/v1/access_users/user/profile_photo"
-	err := c.client.MakeVerkadaRequestForFile("GET", url, *options, filename, 0)
+	err := c.client.MakeVerkadaRequestForFileContext(ctx, "GET", url, *options, filename, 0)
 	return err
 }
 
+// Same as GetProfilePhoto, writing the photo to w instead of a file on disk and returning its content type.
+// Nothing is written unless the response is a 2xx image; otherwise an *APIError or *ContentTypeError is returned.
+func (c *AccessClient) GetProfilePhotoToWriter(options *GetProfilePhotoOptions, w io.Writer) (string, error) {
+	return c.GetProfilePhotoToWriterContext(context.Background(), options, w)
+}
+
+// Same as GetProfilePhotoToWriter, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetProfilePhotoToWriterContext(ctx context.Context, options *GetProfilePhotoOptions, w io.Writer) (string, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetProfilePhotoToWriter")
+	defer span.End()
+	if options == nil {
+		options = &GetProfilePhotoOptions{}
+	}
+	if err := validateGetProfilePhotoOptions(options); err != nil {
+		return "", err
+	}
+	url := c.client.baseURL + "/access/v1/access_users/user/profile_photo"
+	contentType, _, err := c.client.MakeVerkadaRequestToWriterContext(ctx, "GET", url, *options, w, imageContentTypes, 0)
+	return contentType, err
+}
+
+// Same as GetProfilePhoto, returning the photo and its content type instead of writing a file.
+func (c *AccessClient) GetProfilePhotoBytes(options *GetProfilePhotoOptions) (*Download, error) {
+	return c.GetProfilePhotoBytesContext(context.Background(), options)
+}
+
+// Same as GetProfilePhotoBytes, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetProfilePhotoBytesContext(ctx context.Context, options *GetProfilePhotoOptions) (*Download, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetProfilePhotoBytes")
+	defer span.End()
+	if options == nil {
+		options = &GetProfilePhotoOptions{}
+	}
+	if err := validateGetProfilePhotoOptions(options); err != nil {
+		return nil, err
+	}
+	url := c.client.baseURL + "/access/v1/access_users/user/profile_photo"
+	return c.client.downloadBytes(ctx, url, *options, imageContentTypes)
+}
+
+func validateGetProfilePhotoOptions(options *GetProfilePhotoOptions) error {
+	// should not use both external_id and user_id, but need at least one
+	if (options.External_id == "") == (options.User_id == "") {
+		return validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+	}
+	return nil
+}
+
 // Upload a profile photo for the specified user.
 //
 // [Verkada API Docs - Upload Profile Photo]
 //
 // [Verkada API Docs - Upload Profile Photo]: https://apidocs.verkada.com/reference/putprofilephotoviewv1
 func (c *AccessClient) UploadProfilePhoto(options *UploadProfilePhotoOptions, filename string) error {
+	return c.UploadProfilePhotoContext(context.Background(), options, filename)
+}
+
+// Same as UploadProfilePhoto, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) UploadProfilePhotoContext(ctx context.Context, options *UploadProfilePhotoOptions, filename string) error {
+	ctx, span := c.client.startSpan(ctx, "Access.UploadProfilePhoto")
+	defer span.End()
 	if options == nil {
 		options = &UploadProfilePhotoOptions{}
 	}
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	// filename validation and replacement if left blank
 	if !strings.HasSuffix(filename, ".jpg") {
-		return fmt.Errorf("included filename does not end with \".jpg\" - received %s", filename)
+		return validationErrorf("included filename does not end with \".jpg\" - received %s", filename)
+	}
+	var ret CreateProfilePhotoResponse
+	url := c.client.baseURL + "/access/v1/access_users/user/profile_photo"
+	err := c.client.MakeVerkadaRequestWithFileContext(ctx, "PUT", url, *options, filename, "image/jpeg", &ret, 0)
+	return err
+}
+
+// Same as UploadProfilePhoto, uploading the JPEG image from r instead of a file on disk.
+// uploadOptions can set a size limit and progress callback, and may be nil.
+func (c *AccessClient) UploadProfilePhotoFromReader(options *UploadProfilePhotoOptions, r io.Reader, uploadOptions *UploadOptions) error {
+	return c.UploadProfilePhotoFromReaderContext(context.Background(), options, r, uploadOptions)
+}
+
+// Same as UploadProfilePhotoFromReader, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) UploadProfilePhotoFromReaderContext(ctx context.Context, options *UploadProfilePhotoOptions, r io.Reader, uploadOptions *UploadOptions) error {
+	ctx, span := c.client.startSpan(ctx, "Access.UploadProfilePhotoFromReader")
+	defer span.End()
+	if options == nil {
+		options = &UploadProfilePhotoOptions{}
+	}
+	// should not use both external_id and user_id, but need at least one
+	if (options.External_id == "") == (options.User_id == "") {
+		return validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+	}
+	upload := Upload{Filename: "profilephoto.jpg", ContentType: "image/jpeg", Body: r}
+	if uploadOptions != nil {
+		upload.UploadOptions = *uploadOptions
 	}
 	var ret CreateProfilePhotoResponse
 	url := c.client.baseURL + "/access/v1/access_users/user/profile_photo"
-	err := c.client.MakeVerkadaRequestWithFile("PUT", url, *options, filename, "image/jpeg", &ret, 0)
+	err := c.client.MakeVerkadaRequestWithReaderContext(ctx, "PUT", url, *options, upload, &ret, 0)
 	return err
 }
 
@@ -361,6 +555,13 @@ func (c *AccessClient) UploadProfilePhoto(options *UploadProfilePhotoOptions, fi
 //
 // [Verkada API Docs - Activate Remote Unlock for User]: https://apidocs.verkada.com/reference/putactivateremoteunlockviewv1
 func (c *AccessClient) ActivateUserRemoteUnlock(options *ActivateUserRemoteUnlockOptions) (*AccessInformationObject, error) {
+	return c.ActivateUserRemoteUnlockContext(context.Background(), options)
+}
+
+// Same as ActivateUserRemoteUnlock, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) ActivateUserRemoteUnlockContext(ctx context.Context, options *ActivateUserRemoteUnlockOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.ActivateUserRemoteUnlock")
+	defer span.End()
 	if options == nil {
 		options = &ActivateUserRemoteUnlockOptions{}
 	}
@@ -371,11 +572,11 @@ func (c *AccessClient) ActivateUserRemoteUnlock(options *ActivateUserRemoteUnloc
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/remote_unlock/activate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -385,6 +586,13 @@ func (c *AccessClient) ActivateUserRemoteUnlock(options *ActivateUserRemoteUnloc
 //
 // [Verkada API Docs - Deactivate Remote Unlock for User]: https://apidocs.verkada.com/reference/putdeactivateremoteunlockviewv1
 func (c *AccessClient) DeactivateUserRemoteUnlock(options *DeactivateUserRemoteUnlockOptions) (*AccessInformationObject, error) {
+	return c.DeactivateUserRemoteUnlockContext(context.Background(), options)
+}
+
+// Same as DeactivateUserRemoteUnlock, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeactivateUserRemoteUnlockContext(ctx context.Context, options *DeactivateUserRemoteUnlockOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeactivateUserRemoteUnlock")
+	defer span.End()
 	if options == nil {
 		options = &DeactivateUserRemoteUnlockOptions{}
 	}
@@ -395,11 +603,11 @@ func (c *AccessClient) DeactivateUserRemoteUnlock(options *DeactivateUserRemoteU
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/remote_unlock/deactivate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -410,6 +618,13 @@ func (c *AccessClient) DeactivateUserRemoteUnlock(options *DeactivateUserRemoteU
 //
 // [Verkada API Docs - Set Start Date for User]: https://apidocs.verkada.com/reference/putaccessstartdateviewv1
 func (c *AccessClient) SetStartDate(start_date string, options *SetStartDateOptions) (*AccessInformationObject, error) {
+	return c.SetStartDateContext(context.Background(), start_date, options)
+}
+
+// Same as SetStartDate, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) SetStartDateContext(ctx context.Context, start_date string, options *SetStartDateOptions) (*AccessInformationObject, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.SetStartDate")
+	defer span.End()
 	body := struct {
 		Start_date string `json:"start_date"`
 	}{
@@ -425,11 +640,11 @@ func (c *AccessClient) SetStartDate(start_date string, options *SetStartDateOpti
 		}
 	}
 	if keyCount != 1 {
-		return nil, fmt.Errorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
+		return nil, validationErrorf("need one out of email, external_id, user_id, and employee_id - received email: %s, external id: %s, user_id: %s, employee_id: %s", options.Email, options.External_id, options.User_id, options.Employee_id)
 	}
 	var ret AccessInformationObject
 	url := c.client.baseURL + "/access/v1/access_users/user/start_date"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, body, &ret, 0)
 	return &ret, err
 }
 
@@ -439,17 +654,24 @@ func (c *AccessClient) SetStartDate(start_date string, options *SetStartDateOpti
 //
 // [Verkada API Docs - Delete Access Card]: https://apidocs.verkada.com/reference/deleteaccesscardviewv1
 func (c *AccessClient) DeleteAccessCard(card_id string, options *DeleteAccessCardOptions) (*DeleteAccessCardResponse, error) {
+	return c.DeleteAccessCardContext(context.Background(), card_id, options)
+}
+
+// Same as DeleteAccessCard, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteAccessCardContext(ctx context.Context, card_id string, options *DeleteAccessCardOptions) (*DeleteAccessCardResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteAccessCard")
+	defer span.End()
 	if options == nil {
 		options = &DeleteAccessCardOptions{}
 	}
 	options.card_id = card_id
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret DeleteAccessCardResponse
 	url := c.client.baseURL + "/access/v1/credentials/card"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -463,6 +685,13 @@ func (c *AccessClient) DeleteAccessCard(card_id string, options *DeleteAccessCar
 //
 // [Verkada API Docs - Delete Access Card]: https://apidocs.verkada.com/reference/deleteaccesscardviewv1
 func (c *AccessClient) AddAccessCard(format string, options *AddAccessCardOptions, body *AddAccessCardBody) (*Card, error) {
+	return c.AddAccessCardContext(context.Background(), format, options, body)
+}
+
+// Same as AddAccessCard, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) AddAccessCardContext(ctx context.Context, format string, options *AddAccessCardOptions, body *AddAccessCardBody) (*Card, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.AddAccessCard")
+	defer span.End()
 	if options == nil {
 		options = &AddAccessCardOptions{}
 	}
@@ -483,7 +712,7 @@ func (c *AccessClient) AddAccessCard(format string, options *AddAccessCardOption
 	}
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	// Card format must be one of the following:
 	format_validation := map[string]bool{
@@ -560,11 +789,11 @@ func (c *AccessClient) AddAccessCard(format string, options *AddAccessCardOption
 		"Andover Controls 37-bit":     true,
 	}
 	if ok := format_validation[format]; !ok {
-		return nil, fmt.Errorf("could not validate card format: %s", format)
+		return nil, validationErrorf("could not validate card format: %s", format)
 	}
 	var ret Card
 	url := c.client.baseURL + "/access/v1/credentials/card"
-	err := c.client.MakeVerkadaRequest("POST", url, *options, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, *options, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -574,17 +803,24 @@ func (c *AccessClient) AddAccessCard(format string, options *AddAccessCardOption
 //
 // [Verkada API Docs - Activate Access Card]: https://apidocs.verkada.com/reference/putaccesscardactivateviewv1
 func (c *AccessClient) ActivateAccessCard(card_id string, options *ActivateAccessCardOptions) (*Card, error) {
+	return c.ActivateAccessCardContext(context.Background(), card_id, options)
+}
+
+// Same as ActivateAccessCard, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) ActivateAccessCardContext(ctx context.Context, card_id string, options *ActivateAccessCardOptions) (*Card, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.ActivateAccessCard")
+	defer span.End()
 	if options == nil {
 		options = &ActivateAccessCardOptions{}
 	}
 	options.card_id = card_id
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret Card
 	url := c.client.baseURL + "/access/v1/credentials/card/activate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -594,17 +830,24 @@ func (c *AccessClient) ActivateAccessCard(card_id string, options *ActivateAcces
 //
 // [Verkada API Docs - Deactivate Access Card]: https://apidocs.verkada.com/reference/putaccesscarddeactivateviewv1
 func (c *AccessClient) DeactivateAccessCard(card_id string, options *DeactivateAccessCardOptions) (*Card, error) {
+	return c.DeactivateAccessCardContext(context.Background(), card_id, options)
+}
+
+// Same as DeactivateAccessCard, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeactivateAccessCardContext(ctx context.Context, card_id string, options *DeactivateAccessCardOptions) (*Card, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeactivateAccessCard")
+	defer span.End()
 	if options == nil {
 		options = &DeactivateAccessCardOptions{}
 	}
 	options.card_id = card_id
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret Card
 	url := c.client.baseURL + "/access/v1/credentials/card/deactivate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -614,17 +857,24 @@ func (c *AccessClient) DeactivateAccessCard(card_id string, options *DeactivateA
 //
 // [Verkada API Docs - Delete License Plate from User]: https://apidocs.verkada.com/reference/deletelicenseplateviewv1
 func (c *AccessClient) DeleteUserLicensePlate(license_plate_number string, options *DeleteUserLicensePlateOptions) (*DeleteUserLicensePlateResponse, error) {
+	return c.DeleteUserLicensePlateContext(context.Background(), license_plate_number, options)
+}
+
+// Same as DeleteUserLicensePlate, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteUserLicensePlateContext(ctx context.Context, license_plate_number string, options *DeleteUserLicensePlateOptions) (*DeleteUserLicensePlateResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteUserLicensePlate")
+	defer span.End()
 	if options == nil {
 		options = &DeleteUserLicensePlateOptions{}
 	}
 	options.license_plate_number = license_plate_number
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret DeleteUserLicensePlateResponse
 	url := c.client.baseURL + "/access/v1/credentials/license_plate"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -635,6 +885,13 @@ func (c *AccessClient) DeleteUserLicensePlate(license_plate_number string, optio
 //
 // [Verkada API Docs - Add License Plate from User]: https://apidocs.verkada.com/reference/postlicenseplateviewv1
 func (c *AccessClient) AddUserLicensePlate(license_plate_number string, options *AddUserLicensePlateOptions, body *AddUserLicensePlatedBody) (*LicensePlate, error) {
+	return c.AddUserLicensePlateContext(context.Background(), license_plate_number, options, body)
+}
+
+// Same as AddUserLicensePlate, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) AddUserLicensePlateContext(ctx context.Context, license_plate_number string, options *AddUserLicensePlateOptions, body *AddUserLicensePlatedBody) (*LicensePlate, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.AddUserLicensePlate")
+	defer span.End()
 	if options == nil {
 		options = &AddUserLicensePlateOptions{}
 	}
@@ -649,11 +906,11 @@ func (c *AccessClient) AddUserLicensePlate(license_plate_number string, options
 	}
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret LicensePlate
 	url := c.client.baseURL + "/access/v1/credentials/license_plate"
-	err := c.client.MakeVerkadaRequest("POST", url, *options, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, *options, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -663,17 +920,24 @@ func (c *AccessClient) AddUserLicensePlate(license_plate_number string, options
 //
 // [Verkada API Docs - Activate License Plate]: https://apidocs.verkada.com/reference/putlicenseplateactivateviewv1
 func (c *AccessClient) ActivateLicensePlate(license_plate_number string, options *ActivateLicensePlateOptions) (*LicensePlate, error) {
+	return c.ActivateLicensePlateContext(context.Background(), license_plate_number, options)
+}
+
+// Same as ActivateLicensePlate, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) ActivateLicensePlateContext(ctx context.Context, license_plate_number string, options *ActivateLicensePlateOptions) (*LicensePlate, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.ActivateLicensePlate")
+	defer span.End()
 	if options == nil {
 		options = &ActivateLicensePlateOptions{}
 	}
 	options.License_plate_number = license_plate_number
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret LicensePlate
 	url := c.client.baseURL + "/access/v1/credentials/license_plate/activate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -683,17 +947,24 @@ func (c *AccessClient) ActivateLicensePlate(license_plate_number string, options
 //
 // [Verkada API Docs - Deactivate License Plate]: https://apidocs.verkada.com/reference/putlicenseplatedeactivateviewv1
 func (c *AccessClient) DeactivateLicensePlate(license_plate_number string, options *DeactivateLicensePlateOptions) (*LicensePlate, error) {
+	return c.DeactivateLicensePlateContext(context.Background(), license_plate_number, options)
+}
+
+// Same as DeactivateLicensePlate, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeactivateLicensePlateContext(ctx context.Context, license_plate_number string, options *DeactivateLicensePlateOptions) (*LicensePlate, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeactivateLicensePlate")
+	defer span.End()
 	if options == nil {
 		options = &DeactivateLicensePlateOptions{}
 	}
 	options.License_plate_number = license_plate_number
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret LicensePlate
 	url := c.client.baseURL + "/access/v1/credentials/license_plate/deactivate"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -703,17 +974,24 @@ func (c *AccessClient) DeactivateLicensePlate(license_plate_number string, optio
 //
 // [Verkada API Docs - Delete MFA Code from User]: https://apidocs.verkada.com/reference/deletemfacodeviewv1
 func (c *AccessClient) DeleteMFACode(code string, options *DeleteMFACodeOptions) (*DeleteMFACodeResponse, error) {
+	return c.DeleteMFACodeContext(context.Background(), code, options)
+}
+
+// Same as DeleteMFACode, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteMFACodeContext(ctx context.Context, code string, options *DeleteMFACodeOptions) (*DeleteMFACodeResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteMFACode")
+	defer span.End()
 	if options == nil {
 		options = &DeleteMFACodeOptions{}
 	}
 	options.code = code
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret DeleteMFACodeResponse
 	url := c.client.baseURL + "/access/v1/credentials/mfa_code"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -723,6 +1001,13 @@ func (c *AccessClient) DeleteMFACode(code string, options *DeleteMFACodeOptions)
 //
 // [Verkada API Docs - Add MFA Code to User]: https://apidocs.verkada.com/reference/postmfacodeviewv1
 func (c *AccessClient) AddMFACode(code string, options *AddMFACodeOptions) (*AddMFACodeResponse, error) {
+	return c.AddMFACodeContext(context.Background(), code, options)
+}
+
+// Same as AddMFACode, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) AddMFACodeContext(ctx context.Context, code string, options *AddMFACodeOptions) (*AddMFACodeResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.AddMFACode")
+	defer span.End()
 	if options == nil {
 		options = &AddMFACodeOptions{}
 	}
@@ -733,11 +1018,11 @@ func (c *AccessClient) AddMFACode(code string, options *AddMFACodeOptions) (*Add
 	}
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret AddMFACodeResponse
 	url := c.client.baseURL + "/access/v1/credentials/mfa_code"
-	err := c.client.MakeVerkadaRequest("POST", url, *options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, *options, body, &ret, 0)
 	return &ret, err
 }
 
@@ -750,9 +1035,16 @@ func (c *AccessClient) AddMFACode(code string, options *AddMFACodeOptions) (*Add
 //
 // [Verkada API Docs - Get All Available Access Levels]: https://apidocs.verkada.com/reference/getaccesslevelview
 func (c *AccessClient) GetAllAccessLevels() (*GetAllAccessLevelsResponse, error) {
+	return c.GetAllAccessLevelsContext(context.Background())
+}
+
+// Same as GetAllAccessLevels, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAllAccessLevelsContext(ctx context.Context) (*GetAllAccessLevelsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAllAccessLevels")
+	defer span.End()
 	var ret GetAllAccessLevelsResponse
 	url := c.client.baseURL + "/access/v1/door/access_level"
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -765,6 +1057,13 @@ func (c *AccessClient) GetAllAccessLevels() (*GetAllAccessLevelsResponse, error)
 //
 // [Verkada API Docs - Create Access Level]: https://apidocs.verkada.com/reference/postaccesslevelview
 func (c *AccessClient) CreateAccessLevel(access_groups []string, access_schedule_events []AccessScheduleEvent, doors []string, name string, sites []string) (*AccessLevel, error) {
+	return c.CreateAccessLevelContext(context.Background(), access_groups, access_schedule_events, doors, name, sites)
+}
+
+// Same as CreateAccessLevel, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) CreateAccessLevelContext(ctx context.Context, access_groups []string, access_schedule_events []AccessScheduleEvent, doors []string, name string, sites []string) (*AccessLevel, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.CreateAccessLevel")
+	defer span.End()
 	body := AccessLevel{
 		Access_groups:          access_groups,
 		Access_schedule_events: access_schedule_events,
@@ -774,7 +1073,7 @@ func (c *AccessClient) CreateAccessLevel(access_groups []string, access_schedule
 	}
 	var ret AccessLevel
 	url := c.client.baseURL + "/access/v1/door/access_level"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -787,9 +1086,16 @@ func (c *AccessClient) CreateAccessLevel(access_groups []string, access_schedule
 //
 // [Verkada API Docs - Delete Access Level]: https://apidocs.verkada.com/reference/deleteaccessleveldetailview
 func (c *AccessClient) DeleteAccessLevel(access_level_id string) (*DeleteAccessLevelResponse, error) {
+	return c.DeleteAccessLevelContext(context.Background(), access_level_id)
+}
+
+// Same as DeleteAccessLevel, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteAccessLevelContext(ctx context.Context, access_level_id string) (*DeleteAccessLevelResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteAccessLevel")
+	defer span.End()
 	var ret DeleteAccessLevelResponse
 	url := c.client.baseURL + "/access/v1/door/access_level/" + access_level_id
-	err := c.client.MakeVerkadaRequest("DELETE", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -802,9 +1108,16 @@ func (c *AccessClient) DeleteAccessLevel(access_level_id string) (*DeleteAccessL
 //
 // [Verkada API Docs - Get Access Level]: https://apidocs.verkada.com/reference/getaccessleveldetailview
 func (c *AccessClient) GetAccessLevel(access_level_id string) (*AccessLevel, error) {
+	return c.GetAccessLevelContext(context.Background(), access_level_id)
+}
+
+// Same as GetAccessLevel, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAccessLevelContext(ctx context.Context, access_level_id string) (*AccessLevel, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAccessLevel")
+	defer span.End()
 	var ret AccessLevel
 	url := c.client.baseURL + "/access/v1/door/access_level/" + access_level_id
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -817,6 +1130,13 @@ func (c *AccessClient) GetAccessLevel(access_level_id string) (*AccessLevel, err
 //
 // [Verkada API Docs - Update Access Level]: https://apidocs.verkada.com/reference/putaccessleveldetailview
 func (c *AccessClient) UpdateAccessLevel(access_level_id string, access_groups []string, access_schedule_events []AccessScheduleEvent, doors []string, name string, sites []string) (*AccessLevel, error) {
+	return c.UpdateAccessLevelContext(context.Background(), access_level_id, access_groups, access_schedule_events, doors, name, sites)
+}
+
+// Same as UpdateAccessLevel, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) UpdateAccessLevelContext(ctx context.Context, access_level_id string, access_groups []string, access_schedule_events []AccessScheduleEvent, doors []string, name string, sites []string) (*AccessLevel, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.UpdateAccessLevel")
+	defer span.End()
 	body := AccessLevel{
 		Access_groups:          access_groups,
 		Access_schedule_events: access_schedule_events,
@@ -826,7 +1146,7 @@ func (c *AccessClient) UpdateAccessLevel(access_level_id string, access_groups [
 	}
 	var ret AccessLevel
 	url := c.client.baseURL + "/access/v1/door/access_level/" + access_level_id
-	err := c.client.MakeVerkadaRequest("PUT", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -839,6 +1159,13 @@ func (c *AccessClient) UpdateAccessLevel(access_level_id string, access_groups [
 //
 // [Verkada API Docs - Add Access Schedule Event to Access Level]: https://apidocs.verkada.com/reference/postaccesslevelscheduleview
 func (c *AccessClient) AddAccessScheduleEvent(access_level_id string, end_time string, start_time string, weekday string) (*AccessScheduleEvent, error) {
+	return c.AddAccessScheduleEventContext(context.Background(), access_level_id, end_time, start_time, weekday)
+}
+
+// Same as AddAccessScheduleEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) AddAccessScheduleEventContext(ctx context.Context, access_level_id string, end_time string, start_time string, weekday string) (*AccessScheduleEvent, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.AddAccessScheduleEvent")
+	defer span.End()
 	body := AccessScheduleEvent{
 		Door_status: "access_granted",
 		End_time:    end_time,
@@ -856,11 +1183,11 @@ func (c *AccessClient) AddAccessScheduleEvent(access_level_id string, end_time s
 		"SAT": true,
 	}
 	if ok := day_validation[body.Weekday]; !ok {
-		return nil, fmt.Errorf("could not validate card format: %s", body.Weekday)
+		return nil, validationErrorf("could not validate card format: %s", body.Weekday)
 	}
 	var ret AccessScheduleEvent
 	url := c.client.baseURL + "/access/v1/door/access_level/" + access_level_id + "/access_schedule_event"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -873,9 +1200,16 @@ func (c *AccessClient) AddAccessScheduleEvent(access_level_id string, end_time s
 //
 // [Verkada API Docs - Delete Access Schedule Event on Access Level]: https://apidocs.verkada.com/reference/deleteaccesslevelscheduleview
 func (c *AccessClient) DeleteAccessScheduleEvent(access_level_id string, event_id string) (*DeleteAccessScheduleEventResponse, error) {
+	return c.DeleteAccessScheduleEventContext(context.Background(), access_level_id, event_id)
+}
+
+// Same as DeleteAccessScheduleEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteAccessScheduleEventContext(ctx context.Context, access_level_id string, event_id string) (*DeleteAccessScheduleEventResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteAccessScheduleEvent")
+	defer span.End()
 	var ret DeleteAccessScheduleEventResponse
 	url := c.client.baseURL + "/access/v1/door/access_level/" + access_level_id + "/access_schedule_event/" + event_id
-	err := c.client.MakeVerkadaRequest("DELETE", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -888,9 +1222,16 @@ func (c *AccessClient) DeleteAccessScheduleEvent(access_level_id string, event_i
 //
 // [Verkada API Docs - Get Access Schedule Event Details]: https://apidocs.verkada.com/reference/getaccesslevelscheduleview
 func (c *AccessClient) GetAccessScheduleEvent(access_level_id string, event_id string) (*AccessScheduleEvent, error) {
+	return c.GetAccessScheduleEventContext(context.Background(), access_level_id, event_id)
+}
+
+// Same as GetAccessScheduleEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAccessScheduleEventContext(ctx context.Context, access_level_id string, event_id string) (*AccessScheduleEvent, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAccessScheduleEvent")
+	defer span.End()
 	var ret AccessScheduleEvent
 	url := c.client.baseURL + "/access/v1/door/access_level/" + access_level_id + "/access_schedule_event/" + event_id
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -903,6 +1244,13 @@ func (c *AccessClient) GetAccessScheduleEvent(access_level_id string, event_id s
 //
 // [Verkada API Docs - Update Access Schedule Event on Access Level]: https://apidocs.verkada.com/reference/putaccesslevelscheduleview
 func (c *AccessClient) UpdateAccessScheduleEvent(access_level_id string, event_id string, end_time string, start_time string, weekday string) (*AccessScheduleEvent, error) {
+	return c.UpdateAccessScheduleEventContext(context.Background(), access_level_id, event_id, end_time, start_time, weekday)
+}
+
+// Same as UpdateAccessScheduleEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) UpdateAccessScheduleEventContext(ctx context.Context, access_level_id string, event_id string, end_time string, start_time string, weekday string) (*AccessScheduleEvent, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.UpdateAccessScheduleEvent")
+	defer span.End()
 	body := AccessScheduleEvent{
 		Door_status: "access_granted",
 		End_time:    end_time,
@@ -920,11 +1268,11 @@ func (c *AccessClient) UpdateAccessScheduleEvent(access_level_id string, event_i
 		"SAT": true,
 	}
 	if ok := day_validation[body.Weekday]; !ok {
-		return nil, fmt.Errorf("could not validate card format: %s", body.Weekday)
+		return nil, validationErrorf("could not validate card format: %s", body.Weekday)
 	}
 	var ret AccessScheduleEvent
 	url := c.client.baseURL + "/access/v1/door/access_level/" + access_level_id + "/access_schedule_event/" + event_id
-	err := c.client.MakeVerkadaRequest("PUT", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -934,6 +1282,13 @@ func (c *AccessClient) UpdateAccessScheduleEvent(access_level_id string, event_i
 //
 // [Verkada API Docs - Unlock Door as Admin]: https://apidocs.verkada.com/reference/postaccessadminapiunlockviewv1
 func (c *AccessClient) AdminUnlockDoor(door_id string) (*AdminUnlockDoorResponse, error) {
+	return c.AdminUnlockDoorContext(context.Background(), door_id)
+}
+
+// Same as AdminUnlockDoor, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) AdminUnlockDoorContext(ctx context.Context, door_id string) (*AdminUnlockDoorResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.AdminUnlockDoor")
+	defer span.End()
 	body := struct {
 		Door_id string `json:"door_id"`
 	}{
@@ -941,7 +1296,7 @@ func (c *AccessClient) AdminUnlockDoor(door_id string) (*AdminUnlockDoorResponse
 	}
 	var ret AdminUnlockDoorResponse
 	url := c.client.baseURL + "/access/v1/door/admin_unlock"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -952,6 +1307,13 @@ func (c *AccessClient) AdminUnlockDoor(door_id string) (*AdminUnlockDoorResponse
 //
 // [Verkada API Docs - Unlock Door as User]: https://apidocs.verkada.com/reference/postaccessuserapiunlockviewv1
 func (c *AccessClient) UserUnlockDoor(door_id string, options *UserUnlockDoorOptions) (*UserUnlockDoorResponse, error) {
+	return c.UserUnlockDoorContext(context.Background(), door_id, options)
+}
+
+// Same as UserUnlockDoor, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) UserUnlockDoorContext(ctx context.Context, door_id string, options *UserUnlockDoorOptions) (*UserUnlockDoorResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.UserUnlockDoor")
+	defer span.End()
 	body := struct {
 		Door_id     string `json:"door_id"`
 		User_id     string `json:"user_id,omitempty"`
@@ -963,11 +1325,12 @@ func (c *AccessClient) UserUnlockDoor(door_id string, options *UserUnlockDoorOpt
 	}
 	// should not use both external_id and user_id, but need at least one
 	if (options.External_id == "") == (options.User_id == "") {
-		return nil, fmt.Errorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
+		return nil, validationErrorf("should use one of external_id and user_id - received external_id: %s and user_id: %s", options.External_id, options.User_id)
 	}
 	var ret UserUnlockDoorResponse
-	url := c.client.baseURL + "/access/v1/door/admin_unlock"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	// "Unlock Door as User" (operation postAccessUserApiUnlockViewV1) in Verkada's API reference
+	url := c.client.baseURL + "/access/v1/door/user_unlock"
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -979,16 +1342,23 @@ func (c *AccessClient) UserUnlockDoor(door_id string, options *UserUnlockDoorOpt
 //
 // [Verkada API Docs - Get Doors]: https://apidocs.verkada.com/reference/getaccessdoorinformationviewv1
 func (c *AccessClient) GetDoors(options *GetDoorsOptions) (*GetDoorsResponse, error) {
+	return c.GetDoorsContext(context.Background(), options)
+}
+
+// Same as GetDoors, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetDoorsContext(ctx context.Context, options *GetDoorsOptions) (*GetDoorsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetDoors")
+	defer span.End()
 	if options == nil {
 		options = &GetDoorsOptions{}
 	}
 	// should not use both door_ids and site_ids
 	if len(options.Door_ids) != 0 && len(options.Site_ids) != 0 {
-		return nil, fmt.Errorf("should not use both door_ids and site_ids - received door_ids: %v and site_ids: %v", options.Door_ids, options.Site_ids)
+		return nil, validationErrorf("should not use both door_ids and site_ids - received door_ids: %v and site_ids: %v", options.Door_ids, options.Site_ids)
 	}
 	var ret GetDoorsResponse
 	url := c.client.baseURL + "/access/v1/doors"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1002,12 +1372,19 @@ func (c *AccessClient) GetDoors(options *GetDoorsOptions) (*GetDoorsResponse, er
 //
 // [Verkada API Docs - Get All Available Door Exception Calendars]: https://apidocs.verkada.com/reference/getaccessdoorexceptioncalendarsviewv1
 func (c *AccessClient) GetAllDoorExceptionCalendars(options *GetAllDoorExceptionCalendarsOptions) (*GetAllDoorExceptionCalendarsResponse, error) {
+	return c.GetAllDoorExceptionCalendarsContext(context.Background(), options)
+}
+
+// Same as GetAllDoorExceptionCalendars, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAllDoorExceptionCalendarsContext(ctx context.Context, options *GetAllDoorExceptionCalendarsOptions) (*GetAllDoorExceptionCalendarsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAllDoorExceptionCalendars")
+	defer span.End()
 	if options == nil {
 		options = &GetAllDoorExceptionCalendarsOptions{}
 	}
 	var ret GetAllDoorExceptionCalendarsResponse
 	url := c.client.baseURL + "/access/v1/door/exception_calendar"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1020,6 +1397,13 @@ func (c *AccessClient) GetAllDoorExceptionCalendars(options *GetAllDoorException
 //
 // [Verkada API Docs - Create Door Exception Calendar]: https://apidocs.verkada.com/reference/postaccessdoorexceptioncalendarsviewv1
 func (c *AccessClient) CreateDoorExceptionCalendar(name string, body *CreateDoorExceptionCalendarBody) (*DoorExceptionCalendar, error) {
+	return c.CreateDoorExceptionCalendarContext(context.Background(), name, body)
+}
+
+// Same as CreateDoorExceptionCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) CreateDoorExceptionCalendarContext(ctx context.Context, name string, body *CreateDoorExceptionCalendarBody) (*DoorExceptionCalendar, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.CreateDoorExceptionCalendar")
+	defer span.End()
 	fullBody := struct {
 		Name       string          `json:"name"`
 		Doors      []string        `json:"doors,omitempty"`
@@ -1036,7 +1420,7 @@ func (c *AccessClient) CreateDoorExceptionCalendar(name string, body *CreateDoor
 	}
 	var ret DoorExceptionCalendar
 	url := c.client.baseURL + "/access/v1/door/exception_calendar"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -1049,9 +1433,16 @@ func (c *AccessClient) CreateDoorExceptionCalendar(name string, body *CreateDoor
 //
 // [Verkada API Docs - Delete Door Exception Calendar]: https://apidocs.verkada.com/reference/deleteaccessdoorexceptioncalendarviewv1
 func (c *AccessClient) DeleteDoorExceptionCalendar(calendar_id string) (*DeleteDoorExceptionCalendarResponse, error) {
+	return c.DeleteDoorExceptionCalendarContext(context.Background(), calendar_id)
+}
+
+// Same as DeleteDoorExceptionCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteDoorExceptionCalendarContext(ctx context.Context, calendar_id string) (*DeleteDoorExceptionCalendarResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteDoorExceptionCalendar")
+	defer span.End()
 	var ret DeleteDoorExceptionCalendarResponse
 	url := c.client.baseURL + "/access/v1/door/exception_calendar/" + calendar_id
-	err := c.client.MakeVerkadaRequest("DELETE", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1064,9 +1455,16 @@ func (c *AccessClient) DeleteDoorExceptionCalendar(calendar_id string) (*DeleteD
 //
 // [Verkada API Docs - Get Door Exception Calendar]: https://apidocs.verkada.com/reference/getaccessdoorexceptioncalendarviewv1
 func (c *AccessClient) GetDoorExceptionCalendar(calendar_id string) (*DoorExceptionCalendar, error) {
+	return c.GetDoorExceptionCalendarContext(context.Background(), calendar_id)
+}
+
+// Same as GetDoorExceptionCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetDoorExceptionCalendarContext(ctx context.Context, calendar_id string) (*DoorExceptionCalendar, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetDoorExceptionCalendar")
+	defer span.End()
 	var ret DoorExceptionCalendar
 	url := c.client.baseURL + "/access/v1/door/exception_calendar/" + calendar_id
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1079,6 +1477,13 @@ func (c *AccessClient) GetDoorExceptionCalendar(calendar_id string) (*DoorExcept
 //
 // [Verkada API Docs - Update Door Exception Calendar]: https://apidocs.verkada.com/reference/putaccessdoorexceptioncalendarviewv1
 func (c *AccessClient) UpdateDoorExceptionCalendar(calendar_id string, name string, body *UpdateDoorExceptionCalendarBody) (*DoorExceptionCalendar, error) {
+	return c.UpdateDoorExceptionCalendarContext(context.Background(), calendar_id, name, body)
+}
+
+// Same as UpdateDoorExceptionCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) UpdateDoorExceptionCalendarContext(ctx context.Context, calendar_id string, name string, body *UpdateDoorExceptionCalendarBody) (*DoorExceptionCalendar, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.UpdateDoorExceptionCalendar")
+	defer span.End()
 	fullBody := struct {
 		Name       string          `json:"name"`
 		Doors      []string        `json:"doors,omitempty"`
@@ -1095,7 +1500,7 @@ func (c *AccessClient) UpdateDoorExceptionCalendar(calendar_id string, name stri
 	}
 	var ret DoorExceptionCalendar
 	url := c.client.baseURL + "/access/v1/door/exception_calendar/" + calendar_id
-	err := c.client.MakeVerkadaRequest("PUT", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -1108,6 +1513,13 @@ func (c *AccessClient) UpdateDoorExceptionCalendar(calendar_id string, name stri
 //
 // [Verkada API Docs - Add Exception to Door Exception Calendar]: https://apidocs.verkada.com/reference/postaccessdoorexceptionsviewv1
 func (c *AccessClient) AddExceptionToCalendar(calendar_id string, date string, start_time, end_time string, body *AddExceptionToCalendarBody) (*DoorException, error) {
+	return c.AddExceptionToCalendarContext(context.Background(), calendar_id, date, start_time, end_time, body)
+}
+
+// Same as AddExceptionToCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) AddExceptionToCalendarContext(ctx context.Context, calendar_id string, date string, start_time, end_time string, body *AddExceptionToCalendarBody) (*DoorException, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.AddExceptionToCalendar")
+	defer span.End()
 	fullBody := DoorException{
 		All_day_default:           body.All_day_default,
 		Date:                      date,
@@ -1125,7 +1537,7 @@ func (c *AccessClient) AddExceptionToCalendar(calendar_id string, date string, s
 	}
 	var ret DoorException
 	url := c.client.baseURL + "/access/v1/door/exception_calendar/" + calendar_id + "/exception"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -1138,9 +1550,16 @@ func (c *AccessClient) AddExceptionToCalendar(calendar_id string, date string, s
 //
 // [Verkada API Docs - Delete Exception on Door Exception Calendar]: https://apidocs.verkada.com/reference/deleteaccessdoorexceptionviewv1
 func (c *AccessClient) DeleteExceptionFromCalendar(calendar_id string, exception_id string) (*DeleteExceptionFromCalendarResponse, error) {
+	return c.DeleteExceptionFromCalendarContext(context.Background(), calendar_id, exception_id)
+}
+
+// Same as DeleteExceptionFromCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeleteExceptionFromCalendarContext(ctx context.Context, calendar_id string, exception_id string) (*DeleteExceptionFromCalendarResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeleteExceptionFromCalendar")
+	defer span.End()
 	var ret DeleteExceptionFromCalendarResponse
 	url := c.client.baseURL + "/access/v1/door/exception_calendar/" + calendar_id + "/exception/" + exception_id
-	err := c.client.MakeVerkadaRequest("DELETE", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1153,9 +1572,16 @@ func (c *AccessClient) DeleteExceptionFromCalendar(calendar_id string, exception
 //
 // [Verkada API Docs - Get Exception on Door Exception Calendar]: https://apidocs.verkada.com/reference/getaccessdoorexceptionviewv1
 func (c *AccessClient) GetExceptionFromCalendar(calendar_id string, exception_id string) (*DoorException, error) {
+	return c.GetExceptionFromCalendarContext(context.Background(), calendar_id, exception_id)
+}
+
+// Same as GetExceptionFromCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetExceptionFromCalendarContext(ctx context.Context, calendar_id string, exception_id string) (*DoorException, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetExceptionFromCalendar")
+	defer span.End()
 	var ret DoorException
 	url := c.client.baseURL + "/access/v1/door/exception_calendar/" + calendar_id + "/exception/" + exception_id
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1168,6 +1594,13 @@ func (c *AccessClient) GetExceptionFromCalendar(calendar_id string, exception_id
 //
 // [Verkada API Docs - Update Exception on Door Exception Calendar]: https://apidocs.verkada.com/reference/putaccessdoorexceptionviewv1
 func (c *AccessClient) UpdateExceptionOnCalendar(calendar_id string, exception_id string, date string, start_time, end_time string, body *AddExceptionToCalendarBody) (*DoorException, error) {
+	return c.UpdateExceptionOnCalendarContext(context.Background(), calendar_id, exception_id, date, start_time, end_time, body)
+}
+
+// Same as UpdateExceptionOnCalendar, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) UpdateExceptionOnCalendarContext(ctx context.Context, calendar_id string, exception_id string, date string, start_time, end_time string, body *AddExceptionToCalendarBody) (*DoorException, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.UpdateExceptionOnCalendar")
+	defer span.End()
 	fullBody := DoorException{
 		All_day_default:           body.All_day_default,
 		Date:                      date,
@@ -1185,7 +1618,7 @@ func (c *AccessClient) UpdateExceptionOnCalendar(calendar_id string, exception_i
 	}
 	var ret DoorException
 	url := c.client.baseURL + "/access/v1/door/exception_calendar/" + calendar_id + "/exception/" + exception_id
-	err := c.client.MakeVerkadaRequest("PUT", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -1195,12 +1628,62 @@ func (c *AccessClient) UpdateExceptionOnCalendar(calendar_id string, exception_i
 //
 // [Verkada API Docs - Get Access Events]: https://apidocs.verkada.com/reference/geteventsviewv1
 func (c *AccessClient) GetAccessEvents(options *GetAccessEventsOptions) (*GetAccessEventsResponse, error) {
+	return c.GetAccessEventsContext(context.Background(), options)
+}
+
+// Same as GetAccessEvents, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAccessEventsContext(ctx context.Context, options *GetAccessEventsOptions) (*GetAccessEventsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAccessEvents")
+	defer span.End()
 	if options == nil {
 		options = &GetAccessEventsOptions{}
 	}
+	if err := validateGetAccessEventsOptions(options); err != nil {
+		return nil, err
+	}
+	var ret GetAccessEventsResponse
+	url := c.client.baseURL + "/events/v1/access"
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c.client.AutoPaginate {
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.accessEventsPages(*options), &ret.Events)
+	}
+	return &ret, err
+}
+
+// Same as GetAccessEvents, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *AccessClient) GetAccessEventsIter(ctx context.Context, options *GetAccessEventsOptions) *Paginator[Events] {
+	ctx = c.client.withOperation(ctx, "Access.GetAccessEvents")
+	if options == nil {
+		options = &GetAccessEventsOptions{}
+	}
+	if err := validateGetAccessEventsOptions(options); err != nil {
+		return errPaginator[Events](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.accessEventsPages(*options))
+}
+
+// Returns the page function shared by GetAccessEventsContext and GetAccessEventsIter.
+func (c *AccessClient) accessEventsPages(options GetAccessEventsOptions) pageFunc[Events] {
+	url := c.client.baseURL + "/events/v1/access"
+	return func(ctx context.Context, token string) ([]Events, string, error) {
+		options.Page_token = token
+		var ret GetAccessEventsResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Events, ret.Next_page_token, nil
+	}
+}
+
+func validateGetAccessEventsOptions(options *GetAccessEventsOptions) error {
 	// page_size must be between 1 and 200
 	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
 	}
 	// notification_type validation
 	event_type_validation := map[string]bool{
@@ -1268,28 +1751,10 @@ func (c *AccessClient) GetAccessEvents(options *GetAccessEventsOptions) (*GetAcc
 	}
 	for _, param := range options.Event_type {
 		if ok := event_type_validation[param]; !ok {
-			return nil, fmt.Errorf("could not validate parameter in event_type: %s", param)
-		}
-	}
-	var ret GetAccessEventsResponse
-	url := c.client.baseURL + "/events/v1/access"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
-	if err != nil {
-		return nil, err
-	}
-	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetAccessEventsResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Events = append(ret.Events, nextRet.Events...)
-			ret.Next_page_token = nextRet.Next_page_token
+			return validationErrorf("could not validate parameter in event_type: %s", param)
 		}
 	}
-	return &ret, err
+	return nil
 }
 
 // Lists all access scenarios for the organization.
@@ -1300,12 +1765,19 @@ func (c *AccessClient) GetAccessEvents(options *GetAccessEventsOptions) (*GetAcc
 //
 // [Verkada API Docs - Get All Access Scenarios]: https://apidocs.verkada.com/reference/getaccessscenariolistviewv1
 func (c *AccessClient) GetAllAccessScenarios(options *GetAllAccessScenariosOptions) (*GetAllAccessScenariosResponse, error) {
+	return c.GetAllAccessScenariosContext(context.Background(), options)
+}
+
+// Same as GetAllAccessScenarios, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) GetAllAccessScenariosContext(ctx context.Context, options *GetAllAccessScenariosOptions) (*GetAllAccessScenariosResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.GetAllAccessScenarios")
+	defer span.End()
 	if options == nil {
 		options = &GetAllAccessScenariosOptions{}
 	}
 	var ret GetAllAccessScenariosResponse
 	url := c.client.baseURL + "/access/v1/scenarios"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1318,9 +1790,16 @@ func (c *AccessClient) GetAllAccessScenarios(options *GetAllAccessScenariosOptio
 //
 // [Verkada API Docs - Activate Access Scenario]: https://apidocs.verkada.com/reference/postaccessscenarioactivateviewv1
 func (c *AccessClient) ActivateAccessScenario(scenario_id string) (*ActivateAccessScenarioResponse, error) {
+	return c.ActivateAccessScenarioContext(context.Background(), scenario_id)
+}
+
+// Same as ActivateAccessScenario, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) ActivateAccessScenarioContext(ctx context.Context, scenario_id string) (*ActivateAccessScenarioResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.ActivateAccessScenario")
+	defer span.End()
 	var ret ActivateAccessScenarioResponse
 	url := c.client.baseURL + "/access/v1/scenarios/" + scenario_id + "/activate"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1333,9 +1812,16 @@ func (c *AccessClient) ActivateAccessScenario(scenario_id string) (*ActivateAcce
 //
 // [Verkada API Docs - Release Access Scenario]: https://apidocs.verkada.com/reference/postaccessscenarioreleaseviewv1
 func (c *AccessClient) DeactivateAccessScenario(scenario_id string) (*DeactivateAccessScenarioResponse, error) {
+	return c.DeactivateAccessScenarioContext(context.Background(), scenario_id)
+}
+
+// Same as DeactivateAccessScenario, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *AccessClient) DeactivateAccessScenarioContext(ctx context.Context, scenario_id string) (*DeactivateAccessScenarioResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Access.DeactivateAccessScenario")
+	defer span.End()
 	var ret DeactivateAccessScenarioResponse
 	url := c.client.baseURL + "/access/v1/scenarios/" + scenario_id + "/deactivate"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -1344,13 +1830,13 @@ func validateDoorException(exception DoorException) (bool, error) {
 	// valdiating all_day_default rules
 	if exception.All_day_default {
 		if exception.Door_status != "access_controlled" {
-			return false, fmt.Errorf("door_status must be \"access_controlled\" when all_day_default is true - received %s", exception.Door_status)
+			return false, validationErrorf("door_status must be \"access_controlled\" when all_day_default is true - received %s", exception.Door_status)
 		}
 		if !(exception.Start_time == "" && exception.End_time == "") {
-			return false, fmt.Errorf("start_time and end_time both must be empty strings (not included) when all_day_default is true - received start_time%s and end_time:%s ", exception.Start_time, exception.End_time)
+			return false, validationErrorf("start_time and end_time both must be empty strings (not included) when all_day_default is true - received start_time%s and end_time:%s ", exception.Start_time, exception.End_time)
 		}
 		if exception.First_person_in || exception.Double_badge {
-			return false, fmt.Errorf("first_person_in and double_badge must be false when all_day_default is true - received first_person_in: %v and double_badge: %v", exception.First_person_in, exception.Double_badge)
+			return false, validationErrorf("first_person_in and double_badge must be false when all_day_default is true - received first_person_in: %v and double_badge: %v", exception.First_person_in, exception.Double_badge)
 		}
 	}
 	// Door status must be one of the following:
@@ -1362,23 +1848,23 @@ func validateDoorException(exception DoorException) (bool, error) {
 		"unlocked":          true,
 	}
 	if ok := door_status_validation[exception.Door_status]; !ok {
-		return false, fmt.Errorf("could not validate door_status: %s", exception.Door_status)
+		return false, validationErrorf("could not validate door_status: %s", exception.Door_status)
 	}
 	// validate double_badge rule
 	if exception.Double_badge && exception.Door_status != "access_controlled" {
-		return false, fmt.Errorf("door_status must be \"access_controlled\" when double_badge is true - received door_status: %s", exception.Door_status)
+		return false, validationErrorf("door_status must be \"access_controlled\" when double_badge is true - received door_status: %s", exception.Door_status)
 	}
 	// validate double_badge_group_ids rule
 	if len(exception.Double_badge_group_ids) > 0 && !exception.Double_badge {
-		return false, fmt.Errorf("double_badge must be true if double_badge_group_ids is not empty")
+		return false, validationErrorf("double_badge must be true if double_badge_group_ids is not empty")
 	}
 	// validate first_person_in rules
 	if exception.First_person_in && !(exception.Door_status == "card_and_code" || exception.Door_status == "access_controlled" || exception.Door_status == "unlocked") {
-		return false, fmt.Errorf("door_status must be \"card_and_code\", \"access_controlled\", or \"access_controlled\" when first_person_in is true - received %s", exception.Door_status)
+		return false, validationErrorf("door_status must be \"card_and_code\", \"access_controlled\", or \"access_controlled\" when first_person_in is true - received %s", exception.Door_status)
 	}
 	// validate first_person_in_group_ids rule
 	if len(exception.First_person_in_group_ids) > 0 && !exception.First_person_in {
-		return false, fmt.Errorf("first_person_in must be true if first_person_in_group_ids is not empty")
+		return false, validationErrorf("first_person_in must be true if first_person_in_group_ids is not empty")
 	}
 	return true, nil
 }
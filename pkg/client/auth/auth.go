@@ -3,14 +3,13 @@
 package auth
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"strings"
 	"time"
 )
 
@@ -21,24 +20,58 @@ type TokenContainer struct {
 	Expires time.Time
 }
 
+// Returned by GetAuthToken and GetStreamingToken when the token endpoint responds with a non-2xx status.
+// The client package converts it into a *client.APIError.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("token request %s %s failed, status: %s, response: %s", e.Method, e.URL, e.Status, e.Body)
+}
+
 // Get a short-lived auth token using the Client's key.
 func GetAuthToken(key string, baseURL string) (TokenContainer, error) {
-	req, _ := http.NewRequest("POST", baseURL+"/token", nil)
+	return GetAuthTokenContext(context.Background(), key, baseURL)
+}
+
+// Same as GetAuthToken, with ctx controlling cancellation and deadlines of the token request.
+func GetAuthTokenContext(ctx context.Context, key string, baseURL string) (TokenContainer, error) {
+	return GetAuthTokenWithClient(ctx, nil, key, baseURL)
+}
+
+// Same as GetAuthTokenContext, sending the request with httpClient so that its transport, proxy, and TLS settings apply.
+// http.DefaultClient is used if httpClient is nil.
+func GetAuthTokenWithClient(ctx context.Context, httpClient *http.Client, key string, baseURL string) (TokenContainer, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/token", nil)
+	if err != nil {
+		return TokenContainer{}, err
+	}
 	req.Header.Add("accept", "application/json")
 	req.Header.Add("x-api-key", key)
 
 	ret := TokenContainer{Expires: time.Now().Add(time.Minute * 29)}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return TokenContainer{}, fmt.Errorf("%s - could not retrieve auth token from API key, status: %s", err.Error(), resp.Status)
+		return TokenContainer{}, fmt.Errorf("%w - could not retrieve auth token from API key", err)
 	}
 
 	defer resp.Body.Close()
 	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return TokenContainer{}, &StatusError{Method: req.Method, URL: req.URL.String(), StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header, Body: body}
+	}
 	err = json.Unmarshal(body, &ret)
 	if err != nil {
-		fmt.Println(err)
 		return TokenContainer{}, fmt.Errorf("error parsing GetAuthToken response fields - %s", err.Error())
 	}
 	return ret, nil
@@ -49,16 +82,37 @@ func GetAuthToken(key string, baseURL string) (TokenContainer, error) {
 //
 // Does attempt to parse just the jwt string and return it if needed externally
 func GetStreamingToken(key string, baseURL string) (*bytes.Buffer, string, error) {
-	req, _ := http.NewRequest("GET", baseURL+"/cameras/v1/footage/token", nil)
+	return GetStreamingTokenContext(context.Background(), key, baseURL)
+}
+
+// Same as GetStreamingToken, with ctx controlling cancellation and deadlines of the token request.
+func GetStreamingTokenContext(ctx context.Context, key string, baseURL string) (*bytes.Buffer, string, error) {
+	return GetStreamingTokenWithClient(ctx, nil, key, baseURL)
+}
+
+// Same as GetStreamingTokenContext, sending the request with httpClient so that its transport, proxy, and TLS settings apply.
+// http.DefaultClient is used if httpClient is nil.
+func GetStreamingTokenWithClient(ctx context.Context, httpClient *http.Client, key string, baseURL string) (*bytes.Buffer, string, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/cameras/v1/footage/token", nil)
+	if err != nil {
+		return nil, "", err
+	}
 	req.Header.Add("accept", "application/json")
 	req.Header.Add("x-api-key", key)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("%v - could not retrieve streaming token from API key, status: %s", err, resp.Status)
+		return nil, "", fmt.Errorf("%w - could not retrieve streaming token from API key", err)
 	}
 
 	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", &StatusError{Method: req.Method, URL: req.URL.String(), StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header, Body: body}
+	}
 	var buf bytes.Buffer
 	rec := struct {
 		Jwt string `json:"jwt"`
@@ -72,21 +126,25 @@ func GetStreamingToken(key string, baseURL string) (*bytes.Buffer, string, error
 	return &buf, rec.Jwt, nil
 }
 
-// Parse the ".env" file in the current working directory.
-// File should be formatted as one key:value pair per line, e.g. API_KEY:key_value_here
+// Parse the ".env" file in the current working directory and set its entries as environment variables.
+// See ParseDotenv for the format, e.g. API_KEY=key_value_here
 //
-// Used internally to retrieve API key if supplied (must have key "API_KEY" to be recognized)
+// No longer used by the client package, which reads the API key through a CredentialProvider instead of modifying the
+// process environment; DotenvProvider reads the same file.
 func GetEnvFromFile() error {
 	file, err := os.Open(".env")
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		items := strings.Split(line, ":")
-		os.Setenv(items[0], items[1])
+	env, err := ParseDotenv(file)
+	if err != nil {
+		return fmt.Errorf("error parsing .env: %w", err)
+	}
+	for name, value := range env {
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Name of the environment variable and dotenv entry holding the API key by default.
+const DefaultAPIKeyName = "API_KEY"
+
+// Returned (wrapped) by a CredentialProvider that has no API key to give, so that a ChainProvider tries the next one.
+var ErrNoCredentials = errors.New("no API key found")
+
+// A CredentialProvider supplies the API key used to request auth and streaming tokens.
+// It is called whenever a token is requested rather than once, so a provider that returns a new key rotates the key of
+// a running Client: tokens already issued for the old key are used until they expire or are rejected.
+// Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// A StaticProvider always returns the same API key.
+type StaticProvider string
+
+func (p StaticProvider) APIKey(ctx context.Context) (string, error) {
+	if p == "" {
+		return "", fmt.Errorf("%w: empty API key", ErrNoCredentials)
+	}
+	return string(p), nil
+}
+
+// An EnvProvider reads the API key from an environment variable, DefaultAPIKeyName if Name is empty.
+type EnvProvider struct {
+	Name string
+}
+
+func (p EnvProvider) APIKey(ctx context.Context) (string, error) {
+	name := p.Name
+	if name == "" {
+		name = DefaultAPIKeyName
+	}
+	key := os.Getenv(name)
+	if key == "" {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrNoCredentials, name)
+	}
+	return key, nil
+}
+
+// A DotenvProvider reads the API key from a dotenv file without modifying the process environment.
+// The file is read on every call, so editing it rotates the key. See ParseDotenv for the format.
+type DotenvProvider struct {
+	// Path of the file, ".env" in the working directory if empty.
+	Path string
+	// Name of the entry holding the key, DefaultAPIKeyName if empty.
+	Name string
+}
+
+func (p DotenvProvider) APIKey(ctx context.Context) (string, error) {
+	path, name := p.Path, p.Name
+	if path == "" {
+		path = ".env"
+	}
+	if name == "" {
+		name = DefaultAPIKeyName
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %w", ErrNoCredentials, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	env, err := ParseDotenv(file)
+	if err != nil {
+		return "", fmt.Errorf("error parsing %s: %w", path, err)
+	}
+	if env[name] == "" {
+		return "", fmt.Errorf("%w: %s has no %s entry", ErrNoCredentials, path, name)
+	}
+	return env[name], nil
+}
+
+// A FileProvider reads the API key from a file holding only the key, such as a Docker or Kubernetes secret.
+// Surrounding whitespace is ignored. The file is read on every call, so a remounted secret rotates the key.
+type FileProvider struct {
+	Path string
+}
+
+func (p FileProvider) APIKey(ctx context.Context) (string, error) {
+	b, err := os.ReadFile(p.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %w", ErrNoCredentials, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(b))
+	if key == "" {
+		return "", fmt.Errorf("%w: %s is empty", ErrNoCredentials, p.Path)
+	}
+	return key, nil
+}
+
+// A ChainProvider returns the key of the first provider that has one, trying them in order.
+// A provider that fails, such as a DotenvProvider with a malformed file, is skipped like one without a key, so that a
+// later provider such as an explicit StaticProvider still applies. If none has a key, the failures are returned, or
+// the ErrNoCredentials errors of every provider if none failed.
+type ChainProvider []CredentialProvider
+
+func (p ChainProvider) APIKey(ctx context.Context) (string, error) {
+	var missing, failed []error
+	for _, provider := range p {
+		key, err := provider.APIKey(ctx)
+		switch {
+		case err == nil:
+			return key, nil
+		case errors.Is(err, ErrNoCredentials):
+			missing = append(missing, err)
+		default:
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		return "", errors.Join(failed...)
+	}
+	if len(missing) == 0 {
+		return "", fmt.Errorf("%w: no credential providers", ErrNoCredentials)
+	}
+	return "", errors.Join(missing...)
+}
+
+// Returns the providers used by client.New when no other is configured:
+// the API_KEY environment variable, then the API_KEY entry of ./.env.
+func DefaultCredentials() ChainProvider {
+	return ChainProvider{EnvProvider{}, DotenvProvider{}}
+}
+
+// Parses a dotenv file: one NAME=value entry per line, with blank lines and lines starting with # ignored.
+// Names may be preceded by "export", values may be single or double quoted (double quotes allow Go escapes such as \n),
+// and unquoted values end at " #". NAME:value entries, the format formerly documented by GetEnvFromFile, are also accepted.
+func ParseDotenv(r io.Reader) (map[string]string, error) {
+	env := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		// the first separator ends the name, so values may contain either
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected NAME=value", n)
+		}
+		name, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch {
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid quoted value for %s", n, name)
+			}
+			value = unquoted
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		default:
+			if j := strings.Index(value, " #"); j >= 0 {
+				value = strings.TrimSpace(value[:j])
+			}
+		}
+		env[name] = value
+	}
+	return env, scanner.Err()
+}
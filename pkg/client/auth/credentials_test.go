@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"equals", "API_KEY=abc", map[string]string{"API_KEY": "abc"}, false},
+		{"colon", "API_KEY: abc", map[string]string{"API_KEY": "abc"}, false},
+		{"spaces around the separator", "API_KEY = abc ", map[string]string{"API_KEY": "abc"}, false},
+		{"first separator ends the name", "URL=https://x.example?a=b\nNOTE: a=b", map[string]string{"URL": "https://x.example?a=b", "NOTE": "a=b"}, false},
+		{"double quoted", `API_KEY="a b # not a comment\n"`, map[string]string{"API_KEY": "a b # not a comment\n"}, false},
+		{"single quoted", `API_KEY='a "b" \n'`, map[string]string{"API_KEY": `a "b" \n`}, false},
+		{"export", "export API_KEY=abc", map[string]string{"API_KEY": "abc"}, false},
+		{"comment suffix", "API_KEY=abc # rotated monthly", map[string]string{"API_KEY": "abc"}, false},
+		{"hash inside a value", "API_KEY=abc#def", map[string]string{"API_KEY": "abc#def"}, false},
+		{"blank lines and comments", "\n  \n# API_KEY=old\n   # indented\nAPI_KEY=abc\n\n", map[string]string{"API_KEY": "abc"}, false},
+		{"empty value", "API_KEY=", map[string]string{"API_KEY": ""}, false},
+		{"later entries win", "API_KEY=old\nAPI_KEY=new", map[string]string{"API_KEY": "new"}, false},
+		{"CRLF line endings", "A=1\r\nB=2\r\n", map[string]string{"A": "1", "B": "2"}, false},
+		{"no separator", "API_KEY=abc\njust some text", nil, true},
+		{"no name", "=abc", nil, true},
+		{"invalid double quoted value", `API_KEY="a\qb"`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv(strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v", err)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Writes a .env file in a temporary working directory.
+func writeDotenv(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefaultCredentialsPrecedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		dotenv string
+		// key given as ClientOptions.APIKey, appended to the chain as client.New does
+		explicit string
+		want     string
+		wantErr  error
+	}{
+		{"environment first", "env-key", "API_KEY=dotenv-key", "explicit-key", "env-key", nil},
+		{"then .env", "", "API_KEY=dotenv-key", "explicit-key", "dotenv-key", nil},
+		{"then the explicit key", "", "OTHER=1", "explicit-key", "explicit-key", nil},
+		{"malformed .env is skipped", "", "API_KEY=dotenv-key\nnot an entry", "explicit-key", "explicit-key", nil},
+		{"malformed .env without another key", "", "not an entry", "", "", nil},
+		{"no key anywhere", "", "OTHER=1", "", "", ErrNoCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DefaultAPIKeyName, tt.env)
+			writeDotenv(t, tt.dotenv)
+			chain := DefaultCredentials()
+			if tt.explicit != "" {
+				chain = append(chain, StaticProvider(tt.explicit))
+			}
+			key, err := chain.APIKey(context.Background())
+			switch {
+			case tt.want != "":
+				if err != nil || key != tt.want {
+					t.Errorf("got %q, %v, want %q", key, err, tt.want)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got %q, %v, want %v", key, err, tt.wantErr)
+				}
+			default:
+				// the parse error is reported rather than hidden behind ErrNoCredentials
+				if err == nil || errors.Is(err, ErrNoCredentials) || !strings.Contains(err.Error(), ".env") {
+					t.Errorf("got %q, %v, want the parse error", key, err)
+				}
+			}
+		})
+	}
+}
+
+func TestProviderRotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider func(path string) CredentialProvider
+		// writes the key where the provider reads it
+		write func(t *testing.T, path string, key string)
+	}{
+		{"dotenv", func(path string) CredentialProvider { return DotenvProvider{Path: path} }, func(t *testing.T, path string, key string) {
+			if err := os.WriteFile(path, []byte("API_KEY="+key+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"file", func(path string) CredentialProvider { return FileProvider{Path: path} }, func(t *testing.T, path string, key string) {
+			if err := os.WriteFile(path, []byte(key+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}},
+		{"environment", func(path string) CredentialProvider { return EnvProvider{Name: "VERKADA_TEST_KEY"} }, func(t *testing.T, path string, key string) {
+			t.Setenv("VERKADA_TEST_KEY", key)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "key")
+			p := tt.provider(path)
+			for _, want := range []string{"key-1", "key-2"} {
+				tt.write(t, path, want)
+				if key, err := p.APIKey(context.Background()); err != nil || key != want {
+					t.Errorf("got %q, %v, want %q", key, err, want)
+				}
+			}
+		})
+	}
+}
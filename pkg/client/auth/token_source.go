@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default time before a token's expiration at which TokenSource starts refreshing it.
+const DefaultRefreshWindow = 2 * time.Minute
+
+// A TokenSource caches the short-lived auth token for an API key and refreshes it when needed.
+// It is safe for concurrent use: however many goroutines need a new token at once, only one token request is made
+// and every caller receives its result.
+//
+// Tokens are refreshed once they are within the refresh window of their expiration, so that in-flight requests
+// are not sent with a token that expires before they reach the API.
+type TokenSource struct {
+	httpClient    *http.Client
+	baseURL       string
+	refreshWindow time.Duration
+	// Optional logger for token refreshes; tokens and API keys are never logged. Set before first use.
+	Logger *slog.Logger
+	// Optional hook called after every token request with its error, if any, e.g. to count refreshes. Set before first use.
+	OnRefresh func(ctx context.Context, err error)
+
+	mu          sync.Mutex
+	credentials CredentialProvider
+	token       TokenContainer
+	// incremented by SetCredentials so that refreshes started with the previous credentials are not cached
+	generation int
+	// in-flight refresh shared by all callers, nil if none
+	call *refreshCall
+}
+
+type refreshCall struct {
+	done  chan struct{}
+	token TokenContainer
+	err   error
+}
+
+// Returns a TokenSource for key that requests tokens from baseURL using httpClient (http.DefaultClient if nil).
+// A refreshWindow of zero uses DefaultRefreshWindow; use a negative value to only refresh expired tokens.
+func NewTokenSource(httpClient *http.Client, key string, baseURL string, refreshWindow time.Duration) *TokenSource {
+	return NewTokenSourceWithCredentials(httpClient, StaticProvider(key), baseURL, refreshWindow)
+}
+
+// Same as NewTokenSource, getting the API key from credentials each time a token is requested.
+func NewTokenSourceWithCredentials(httpClient *http.Client, credentials CredentialProvider, baseURL string, refreshWindow time.Duration) *TokenSource {
+	if refreshWindow == 0 {
+		refreshWindow = DefaultRefreshWindow
+	} else if refreshWindow < 0 {
+		refreshWindow = 0
+	}
+	return &TokenSource{httpClient: httpClient, credentials: credentials, baseURL: baseURL, refreshWindow: refreshWindow}
+}
+
+// Returns the current API key from the TokenSource's CredentialProvider.
+func (s *TokenSource) APIKey(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	credentials := s.credentials
+	s.mu.Unlock()
+	return credentials.APIKey(ctx)
+}
+
+// Replaces the CredentialProvider, e.g. with a StaticProvider holding a new key, and discards the cached token
+// so that the next request gets a token for the new key.
+func (s *TokenSource) SetCredentials(credentials CredentialProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.credentials = credentials
+	s.token = TokenContainer{}
+	s.generation++
+	// callers from now on start a refresh with the new credentials instead of waiting on one with the old
+	s.call = nil
+}
+
+// Returns a valid auth token, requesting a new one if the cached token is missing or within the refresh window.
+// If a refresh fails while the cached token has not yet expired, the cached token is returned instead of the error.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	token := s.token
+	if token.Token != "" && time.Until(token.Expires) > s.refreshWindow {
+		s.mu.Unlock()
+		return token.Token, nil
+	}
+	call := s.refreshLocked(ctx)
+	s.mu.Unlock()
+
+	refreshed, err := wait(ctx, call)
+	if err != nil {
+		if token.Token != "" && time.Now().Before(token.Expires) && ctx.Err() == nil {
+			return token.Token, nil
+		}
+		return "", err
+	}
+	return refreshed.Token, nil
+}
+
+// Discards stale, a token rejected by the API, and returns a new one.
+// If the cached token has already been replaced by another caller, that token is returned without a new request.
+func (s *TokenSource) Refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	if s.token.Token != "" && s.token.Token != stale && time.Now().Before(s.token.Expires) {
+		token := s.token.Token
+		s.mu.Unlock()
+		return token, nil
+	}
+	call := s.refreshLocked(ctx)
+	s.mu.Unlock()
+
+	refreshed, err := wait(ctx, call)
+	if err != nil {
+		return "", err
+	}
+	return refreshed.Token, nil
+}
+
+// Returns the cached token and its expiration without refreshing it.
+func (s *TokenSource) Current() TokenContainer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// Replaces the cached token, e.g. with one obtained separately through GetAuthToken.
+func (s *TokenSource) Set(token TokenContainer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// Returns the in-flight refresh, starting one if there is none. s.mu must be held.
+// The request keeps the values of ctx but not its cancellation, so that one caller giving up does not fail the others waiting on it.
+func (s *TokenSource) refreshLocked(ctx context.Context) *refreshCall {
+	if s.call != nil {
+		return s.call
+	}
+	call := &refreshCall{done: make(chan struct{})}
+	s.call = call
+	credentials, generation := s.credentials, s.generation
+	go func() {
+		// bounded so that a hung token endpoint cannot block every future caller
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+		start := time.Now()
+		var key string
+		key, call.err = credentials.APIKey(ctx)
+		if call.err == nil {
+			call.token, call.err = GetAuthTokenWithClient(ctx, s.httpClient, key, s.baseURL)
+		}
+		if s.Logger != nil {
+			if call.err != nil {
+				s.Logger.WarnContext(ctx, "verkada auth token refresh failed", "latency", time.Since(start), "error", call.err)
+			} else {
+				s.Logger.DebugContext(ctx, "verkada auth token refreshed", "latency", time.Since(start), "expires", call.token.Expires)
+			}
+		}
+		if s.OnRefresh != nil {
+			s.OnRefresh(ctx, call.err)
+		}
+		s.mu.Lock()
+		if call.err == nil && generation == s.generation {
+			s.token = call.token
+		}
+		if s.call == call {
+			s.call = nil
+		}
+		s.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// Waits for call to finish, returning early with the context's error if ctx is done first.
+func wait(ctx context.Context, call *refreshCall) (TokenContainer, error) {
+	select {
+	case <-ctx.Done():
+		return TokenContainer{}, ctx.Err()
+	case <-call.done:
+		return call.token, call.err
+	}
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Starts a token endpoint answering with numbered tokens ("token-1", "token-2", ...) after delay, counting requests.
+func tokenServer(t *testing.T, delay time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" || r.Header.Get("x-api-key") != "key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		n := requests.Add(1)
+		time.Sleep(delay)
+		json.NewEncoder(w).Encode(map[string]string{"token": fmt.Sprintf("token-%d", n)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestTokenSourceSingleFlight(t *testing.T) {
+	srv, requests := tokenServer(t, 50*time.Millisecond)
+	s := NewTokenSource(nil, "key", srv.URL, 0)
+	var wg sync.WaitGroup
+	tokens := make([]string, 50)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if tokens[i], err = s.Token(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := requests.Load(); got != 1 {
+		t.Errorf("made %d token requests, want 1", got)
+	}
+	for _, token := range tokens {
+		if token != "token-1" {
+			t.Fatalf("got tokens %q", tokens)
+		}
+	}
+}
+
+func TestTokenSourceRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		// cached token before the call, and its remaining lifetime
+		cached  string
+		expires time.Duration
+		window  time.Duration
+		// Refresh with this stale token, or Token if empty
+		stale        string
+		want         string
+		wantRequests int32
+	}{
+		{"cached token", "cached", time.Hour, 0, "", "cached", 0},
+		{"no token", "", 0, 0, "", "token-1", 1},
+		{"within the refresh window", "cached", time.Minute, 0, "", "token-1", 1},
+		{"outside a smaller window", "cached", time.Minute, 30 * time.Second, "", "cached", 0},
+		{"negative window keeps the token until it expires", "cached", time.Second, -1, "", "cached", 0},
+		{"expired", "cached", -time.Second, -1, "", "token-1", 1},
+		{"rejected token is replaced", "cached", time.Hour, 0, "cached", "token-1", 1},
+		{"already replaced", "cached", time.Hour, 0, "older", "cached", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := tokenServer(t, 0)
+			s := NewTokenSource(nil, "key", srv.URL, tt.window)
+			if tt.cached != "" {
+				s.Set(TokenContainer{Token: tt.cached, Expires: time.Now().Add(tt.expires)})
+			}
+			var got string
+			var err error
+			if tt.stale != "" {
+				got, err = s.Refresh(context.Background(), tt.stale)
+			} else {
+				got, err = s.Token(context.Background())
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || requests.Load() != tt.wantRequests {
+				t.Errorf("got %s after %d requests, want %s after %d", got, requests.Load(), tt.want, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestTokenSourceConcurrentRefresh(t *testing.T) {
+	srv, requests := tokenServer(t, 50*time.Millisecond)
+	s := NewTokenSource(nil, "key", srv.URL, 0)
+	s.Set(TokenContainer{Token: "rejected", Expires: time.Now().Add(time.Hour)})
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := s.Refresh(context.Background(), "rejected")
+			if err != nil || token != "token-1" {
+				t.Errorf("got %s, %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := requests.Load(); got != 1 {
+		t.Errorf("made %d token requests, want 1", got)
+	}
+}
+
+func TestTokenSourceCallerCancellation(t *testing.T) {
+	srv, _ := tokenServer(t, 100*time.Millisecond)
+	s := NewTokenSource(nil, "key", srv.URL, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := s.Token(ctx)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	// the shared refresh is not cancelled with the first caller
+	if token, err := s.Token(context.Background()); err != nil || token != "token-1" {
+		t.Errorf("got %s, %v", token, err)
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// AVI header flags and index flags.
+const (
+	aviHasIndex = 0x10
+	aviKeyFrame = 0x10
+)
+
+// Sizes of the fixed AVI header chunks, without their 8-byte chunk headers.
+const (
+	aviMainHeaderSize   = 56
+	aviStreamHeaderSize = 56
+	aviBitmapInfoSize   = 40
+	// 'strl' list: its type, then the strh and strf chunks
+	aviStreamListSize = 4 + 8 + aviStreamHeaderSize + 8 + aviBitmapInfoSize
+	// 'hdrl' list: its type, then the avih chunk and the strl list
+	aviHeaderListSize = 4 + 8 + aviMainHeaderSize + 8 + aviStreamListSize
+)
+
+// Writes JPEG frames as a Motion-JPEG AVI (RIFF) file with a single video stream and an index.
+func writeAVI(w io.Writer, frames [][]byte, width int, height int, frameRate int) error {
+	// the movi list holds its type, then a chunk per frame, each padded to an even size
+	moviSize, maxFrame := int64(4), 0
+	for _, f := range frames {
+		moviSize += 8 + int64(len(f)+len(f)%2)
+		maxFrame = max(maxFrame, len(f))
+	}
+	indexSize := int64(16 * len(frames))
+	riffSize := 4 + 8 + aviHeaderListSize + 8 + moviSize + 8 + indexSize
+	if riffSize > math.MaxUint32 {
+		return fmt.Errorf("timelapse of %d bytes is too large for an AVI file", riffSize)
+	}
+
+	var h bytes.Buffer
+	put := func(values ...any) {
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				h.WriteString(s)
+				continue
+			}
+			binary.Write(&h, binary.LittleEndian, v)
+		}
+	}
+	put("RIFF", uint32(riffSize), "AVI ")
+	put("LIST", uint32(aviHeaderListSize), "hdrl")
+	// MainAVIHeader
+	put("avih", uint32(aviMainHeaderSize),
+		uint32(1000000/frameRate), uint32(maxFrame*frameRate), uint32(0), uint32(aviHasIndex),
+		uint32(len(frames)), uint32(0), uint32(1), uint32(maxFrame),
+		uint32(width), uint32(height), [4]uint32{})
+	put("LIST", uint32(aviStreamListSize), "strl")
+	// AVISTREAMHEADER
+	put("strh", uint32(aviStreamHeaderSize),
+		"vids", "MJPG", uint32(0), uint16(0), uint16(0),
+		uint32(0), uint32(1), uint32(frameRate), uint32(0), uint32(len(frames)),
+		uint32(maxFrame), uint32(math.MaxUint32), uint32(0),
+		[4]uint16{0, 0, uint16(width), uint16(height)})
+	// BITMAPINFOHEADER
+	put("strf", uint32(aviBitmapInfoSize),
+		uint32(aviBitmapInfoSize), int32(width), int32(height), uint16(1), uint16(24),
+		"MJPG", uint32(width*height*3), int32(0), int32(0), uint32(0), uint32(0))
+	put("LIST", uint32(moviSize), "movi")
+
+	b := bufio.NewWriter(w)
+	if _, err := b.Write(h.Bytes()); err != nil {
+		return err
+	}
+	var chunk [8]byte
+	copy(chunk[:4], "00dc")
+	for _, f := range frames {
+		binary.LittleEndian.PutUint32(chunk[4:], uint32(len(f)))
+		b.Write(chunk[:])
+		b.Write(f)
+		if len(f)%2 == 1 {
+			b.WriteByte(0)
+		}
+	}
+
+	// idx1 offsets are relative to the movi list type
+	copy(chunk[:4], "idx1")
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(indexSize))
+	b.Write(chunk[:])
+	var entry [16]byte
+	copy(entry[:4], "00dc")
+	binary.LittleEndian.PutUint32(entry[4:], aviKeyFrame)
+	offset := 4
+	for _, f := range frames {
+		binary.LittleEndian.PutUint32(entry[8:], uint32(offset))
+		binary.LittleEndian.PutUint32(entry[12:], uint32(len(f)))
+		b.Write(entry[:])
+		offset += 8 + len(f) + len(f)%2
+	}
+	return b.Flush()
+}
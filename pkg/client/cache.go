@@ -0,0 +1,304 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Number of responses kept by the LRUCache used when CacheOptions.Store is nil.
+const DefaultCacheEntries = 1000
+
+// Options for caching GET responses, set with ClientOptions.Cache.
+//
+// Only endpoints with a TTL are cached. A write (any other method) to an endpoint family, such as CreateAccessGroup,
+// invalidates the cached responses of the whole family (see EndpointFamily); Client.InvalidateCache covers changes
+// made elsewhere, e.g. in Command.
+type CacheOptions struct {
+	// Where responses are kept; an LRUCache of DefaultCacheEntries is used if nil.
+	// A Store can be shared by several Clients, whose entries are kept apart by base URL and API key.
+	// The key is the one the Client currently uses, so responses cached before a key rotation are not returned after it.
+	Store CacheStore
+	// How long responses stay fresh, by endpoint path (e.g. "/cameras/v1/devices"); DefaultCacheTTLs() is used if nil.
+	TTLs map[string]time.Duration
+	// How long after expiring a response may still be returned while it is refreshed in the background.
+	// Zero waits for a fresh response instead.
+	StaleWhileRevalidate time.Duration
+}
+
+// Returns TTLs for slow-changing resources: camera, door, and viewing station devices, access groups, and guest sites.
+func DefaultCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		"/cameras/v1/devices":         5 * time.Minute,
+		"/access/v1/doors":            5 * time.Minute,
+		"/access/v1/access_groups":    time.Minute,
+		"/guest/v1/sites":             10 * time.Minute,
+		"/viewing_station/v1/devices": 5 * time.Minute,
+	}
+}
+
+// A cached response body. Entries are shared between readers and must not be modified once stored.
+type CacheEntry struct {
+	Body    []byte
+	Header  http.Header
+	Stored  time.Time
+	Expires time.Time
+}
+
+// A CacheStore keeps cached responses by key. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	// Removes every entry whose key starts with prefix.
+	DeletePrefix(prefix string)
+}
+
+// An LRUCache is an in-memory CacheStore that evicts the least recently used entry once it is full.
+type LRUCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// Returns an LRUCache holding up to maxEntries responses (DefaultCacheEntries if zero or less).
+func NewLRUCache(maxEntries int) *LRUCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+	return &LRUCache{max: maxEntries, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (l *LRUCache) Get(key string) (*CacheEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*lruItem).entry, true
+}
+
+func (l *LRUCache) Set(key string, entry *CacheEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok := l.entries[key]; ok {
+		e.Value.(*lruItem).entry = entry
+		l.order.MoveToFront(e)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruItem{key: key, entry: entry})
+	for l.order.Len() > l.max {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (l *LRUCache) DeletePrefix(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, e := range l.entries {
+		if strings.HasPrefix(key, prefix) {
+			l.order.Remove(e)
+			delete(l.entries, key)
+		}
+	}
+}
+
+// Returns the number of cached responses.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// The cache of a Client.
+type responseCache struct {
+	store CacheStore
+	ttls  map[string]time.Duration
+	stale time.Duration
+	// base URL of the Client, the start of every key
+	baseURL string
+
+	mu sync.Mutex
+	// keys being refreshed in the background
+	revalidating map[string]bool
+}
+
+func newResponseCache(options *CacheOptions, baseURL string) *responseCache {
+	c := &responseCache{
+		store:        options.Store,
+		ttls:         options.TTLs,
+		stale:        options.StaleWhileRevalidate,
+		baseURL:      baseURL,
+		revalidating: map[string]bool{},
+	}
+	if c.store == nil {
+		c.store = NewLRUCache(DefaultCacheEntries)
+	}
+	if c.ttls == nil {
+		c.ttls = DefaultCacheTTLs()
+	}
+	return c
+}
+
+// Returns the prefix of every key cached with apiKey.
+func (c *responseCache) namespace(apiKey string) string {
+	// the key itself is never stored, only enough of its hash to keep Clients of different orgs apart
+	sum := sha256.Sum256([]byte(apiKey))
+	return c.baseURL + "|" + hex.EncodeToString(sum[:8]) + "|"
+}
+
+// Returns the key of a GET request in namespace, grouped by endpoint family so that writes can invalidate their family.
+func (c *responseCache) key(namespace string, url string, query string) string {
+	key := namespace + string(endpointFamily(url)) + "|" + endpointOf(url)
+	if query != "" {
+		key += "?" + query
+	}
+	return key
+}
+
+// Returns the cache namespace of the API key the Client currently uses, which changes when the key is rotated
+// through its CredentialProvider or Tokens.SetCredentials.
+func (c *Client) cacheNamespace(ctx context.Context) (string, error) {
+	key, err := c.Tokens.APIKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error reading API key: %w", err)
+	}
+	return c.cache.namespace(key), nil
+}
+
+// Removes the cached responses of an endpoint family.
+func (c *Client) invalidateCacheFamily(ctx context.Context, family EndpointFamily) {
+	namespace, err := c.cacheNamespace(ctx)
+	if err != nil {
+		c.logger.WarnContext(ctx, "verkada cache invalidation failed", "family", family, "error", err)
+		return
+	}
+	c.cache.store.DeletePrefix(namespace + string(family) + "|")
+}
+
+// Removes cached responses for endpoints whose path starts with any of paths, or every cached response of the
+// Client if no paths are given. Paths are relative to the base URL, e.g. "/access/v1/access_groups".
+// Only responses cached with the current API key are removed; those of a previous key are never returned again.
+// Does nothing if the Client has no cache.
+func (c *Client) InvalidateCache(paths ...string) {
+	if c.cache == nil {
+		return
+	}
+	namespace, err := c.cacheNamespace(context.Background())
+	if err != nil {
+		c.logger.Warn("verkada cache invalidation failed", "error", err)
+		return
+	}
+	if len(paths) == 0 {
+		c.cache.store.DeletePrefix(namespace)
+		return
+	}
+	for _, path := range paths {
+		c.cache.store.DeletePrefix(namespace + string(endpointFamily(path)) + "|" + path)
+	}
+}
+
+// Returns the TTL of a GET request to url, or zero if it is not cached.
+func (c *Client) cacheTTL(method string, url string) time.Duration {
+	if c.cache == nil || method != http.MethodGet {
+		return 0
+	}
+	return c.cache.ttls[endpointOf(url)]
+}
+
+// Makes a GET request through the cache, for MakeVerkadaRequestContext.
+func (c *Client) cachedRequest(ctx context.Context, url string, params any, target any, ttl time.Duration, retry int) error {
+	values, err := encodeQuery(params)
+	if err != nil {
+		return validationErrorf("cannot encode query params: %v", err)
+	}
+	namespace, err := c.cacheNamespace(ctx)
+	if err != nil {
+		return err
+	}
+	key := c.cache.key(namespace, url, values.Encode())
+	now := time.Now()
+	entry, ok := c.cache.store.Get(key)
+	result := "miss"
+	switch {
+	case ok && now.Before(entry.Expires):
+		result = "hit"
+	case ok && now.Before(entry.Expires.Add(c.cache.stale)):
+		result = "stale"
+		c.revalidate(ctx, key, url, values, ttl)
+	}
+	c.telemetry.recordCacheLookup(ctx, url, result)
+	if result == "miss" {
+		c.logger.DebugContext(ctx, "verkada cache miss", "method", http.MethodGet, "endpoint", endpointOf(url))
+		entry, err = c.fetchCacheEntry(ctx, key, url, values, ttl, retry)
+		if err != nil {
+			return err
+		}
+	} else {
+		c.logger.DebugContext(ctx, "verkada cache hit", "method", http.MethodGet, "endpoint", endpointOf(url), "stale", result == "stale", "age", now.Sub(entry.Stored))
+	}
+	res := &http.Response{Status: "200 OK", StatusCode: http.StatusOK, Header: entry.Header, Body: io.NopCloser(bytes.NewReader(entry.Body))}
+	return c.decodeResponse(http.MethodGet, url, res, target)
+}
+
+// Requests a response and caches it if it is a 2xx response.
+func (c *Client) fetchCacheEntry(ctx context.Context, key string, url string, values neturl.Values, ttl time.Duration, retry int) (*CacheEntry, error) {
+	header := http.Header{}
+	header.Add("accept", "application/json")
+	res, err := c.send(ctx, http.MethodGet, url, values, header, nil, retry)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entry := &CacheEntry{Body: body, Header: res.Header, Stored: now, Expires: now.Add(ttl)}
+	c.cache.store.Set(key, entry)
+	return entry, nil
+}
+
+// Refreshes a stale entry in the background, unless a refresh of it is already running.
+// The refresh keeps the values of ctx but not its cancellation, so that it outlives the request that returned the stale entry.
+func (c *Client) revalidate(ctx context.Context, key string, url string, values neturl.Values, ttl time.Duration) {
+	c.cache.mu.Lock()
+	if c.cache.revalidating[key] {
+		c.cache.mu.Unlock()
+		return
+	}
+	c.cache.revalidating[key] = true
+	c.cache.mu.Unlock()
+	go func() {
+		defer func() {
+			c.cache.mu.Lock()
+			delete(c.cache.revalidating, key)
+			c.cache.mu.Unlock()
+		}()
+		// bounded so that a hung request cannot keep the entry from being refreshed again
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+		if _, err := c.fetchCacheEntry(ctx, key, url, values, ttl, 0); err != nil {
+			c.logger.WarnContext(ctx, "verkada cache revalidation failed", "method", http.MethodGet, "endpoint", endpointOf(url), "error", err)
+		}
+	}()
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client/auth"
+)
+
+func TestCacheInvalidation(t *testing.T) {
+	tests := []struct {
+		name string
+		// called between two identical cached GET requests
+		between      func(c *Client, baseURL string) error
+		wantRequests int32
+	}{
+		{"fresh", func(c *Client, baseURL string) error { return nil }, 1},
+		{"write to the family", func(c *Client, baseURL string) error {
+			return c.MakeVerkadaRequest("POST", baseURL+"/cameras/v1/analytics/lpr/license_plate_of_interest", nil, nil, &map[string]any{}, 0)
+		}, 2},
+		{"write to another family", func(c *Client, baseURL string) error {
+			return c.MakeVerkadaRequest("POST", baseURL+"/access/v1/access_groups/group", nil, nil, &map[string]any{}, 0)
+		}, 1},
+		{"invalidate everything", func(c *Client, baseURL string) error {
+			c.InvalidateCache()
+			return nil
+		}, 2},
+		{"invalidate the path", func(c *Client, baseURL string) error {
+			c.InvalidateCache("/cameras/v1/devices")
+			return nil
+		}, 2},
+		{"invalidate another path", func(c *Client, baseURL string) error {
+			c.InvalidateCache("/access/v1/doors")
+			return nil
+		}, 1},
+		{"key rotated", func(c *Client, baseURL string) error {
+			c.Tokens.SetCredentials(auth.StaticProvider("rotated-key"))
+			return nil
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					requests.Add(1)
+				}
+				w.Write([]byte(`{"cameras": []}`))
+			}, &ClientOptions{Cache: &CacheOptions{}})
+			get := func() {
+				if err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/devices", nil, nil, &map[string]any{}, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			get()
+			if err := tt.between(c, srv.URL); err != nil {
+				t.Fatal(err)
+			}
+			get()
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("sent %d GET requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCacheKeyRotation(t *testing.T) {
+	// a response of the previous key is not returned after a rotation, and each key keeps its own entries
+	store := NewLRUCache(0)
+	var requests atomic.Int32
+	c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{}`))
+	}, &ClientOptions{Cache: &CacheOptions{Store: store}})
+	keys := []struct {
+		key          string
+		wantRequests int32
+	}{
+		{"test-key", 1},
+		{"rotated-key", 2},
+		{"rotated-key", 2},
+		{"test-key", 2},
+	}
+	for _, k := range keys {
+		c.Tokens.SetCredentials(auth.StaticProvider(k.key))
+		if err := c.MakeVerkadaRequest("GET", srv.URL+"/access/v1/doors", nil, nil, &map[string]any{}, 0); err != nil {
+			t.Fatal(err)
+		}
+		if got := requests.Load(); got != k.wantRequests {
+			t.Errorf("with %s: sent %d requests, want %d", k.key, got, k.wantRequests)
+		}
+	}
+	if store.Len() != 2 {
+		t.Errorf("store holds %d entries, want one per key", store.Len())
+	}
+}
@@ -1,11 +1,13 @@
 package client
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/GDRCode/verkada-api-go/pkg/client/auth"
@@ -27,9 +29,59 @@ import (
 //
 // [Verkada API Docs - Get Alerts]: https://apidocs.verkada.com/reference/getnotificationsviewv1
 func (c *CameraClient) GetAlerts(options *GetAlertsOptions) (*GetAlertsResponse, error) {
+	return c.GetAlertsContext(context.Background(), options)
+}
+
+// Same as GetAlerts, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetAlertsContext(ctx context.Context, options *GetAlertsOptions) (*GetAlertsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetAlerts")
+	defer span.End()
 	if options == nil {
 		options = &GetAlertsOptions{}
 	}
+	if err := validateGetAlertsOptions(options); err != nil {
+		return nil, err
+	}
+	var ret GetAlertsResponse
+	url := c.client.baseURL + "/cameras/v1/alerts"
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c.client.AutoPaginate {
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.alertsPages(*options), &ret.Notifications)
+	}
+	return &ret, err
+}
+
+// Same as GetAlerts, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CameraClient) GetAlertsIter(ctx context.Context, options *GetAlertsOptions) *Paginator[Notification] {
+	ctx = c.client.withOperation(ctx, "Camera.GetAlerts")
+	if options == nil {
+		options = &GetAlertsOptions{}
+	}
+	if err := validateGetAlertsOptions(options); err != nil {
+		return errPaginator[Notification](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.alertsPages(*options))
+}
+
+// Returns the page function shared by GetAlertsContext and GetAlertsIter.
+func (c *CameraClient) alertsPages(options GetAlertsOptions) pageFunc[Notification] {
+	url := c.client.baseURL + "/cameras/v1/alerts"
+	return func(ctx context.Context, token string) ([]Notification, string, error) {
+		options.Page_token = token
+		var ret GetAlertsResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Notifications, ret.Next_page_token, nil
+	}
+}
+
+func validateGetAlertsOptions(options *GetAlertsOptions) error {
 	// Notification type must be one of the following:
 	notification_type_validation := map[string]bool{
 		"person_of_interest":        true,
@@ -44,32 +96,14 @@ func (c *CameraClient) GetAlerts(options *GetAlertsOptions) (*GetAlertsResponse,
 	}
 	for _, param := range options.Notification_type {
 		if ok := notification_type_validation[param]; !ok {
-			return nil, fmt.Errorf("could not validate parameter in notification_type: %s", param)
+			return validationErrorf("could not validate parameter in notification_type: %s", param)
 		}
 	}
 	// page_size must be between 1 and 200
 	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
-	}
-	var ret GetAlertsResponse
-	url := c.client.baseURL + "/cameras/v1/alerts"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
-	if err != nil {
-		return nil, err
-	}
-	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetAlertsResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Notifications = append(ret.Notifications, nextRet.Notifications...)
-			ret.Next_page_token = nextRet.Next_page_token
-		}
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
 	}
-	return &ret, err
+	return nil
 }
 
 // Returns all occupancy trends data for a particular dashboard over a specified time range.
@@ -78,6 +112,13 @@ func (c *CameraClient) GetAlerts(options *GetAlertsOptions) (*GetAlertsResponse,
 //
 // [Verkada API Docs - Get Dashboard Occupancy Trend Data]: https://apidocs.verkada.com/reference/getdashboardoccupancytrendsview
 func (c *CameraClient) GetDashboardOTData(dashboard_id string, options *GetDashboardOTDataOptions) (*GetDashboardOTDataResponse, error) {
+	return c.GetDashboardOTDataContext(context.Background(), dashboard_id, options)
+}
+
+// Same as GetDashboardOTData, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetDashboardOTDataContext(ctx context.Context, dashboard_id string, options *GetDashboardOTDataOptions) (*GetDashboardOTDataResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetDashboardOTData")
+	defer span.End()
 	if options == nil {
 		options = &GetDashboardOTDataOptions{}
 	}
@@ -93,11 +134,11 @@ func (c *CameraClient) GetDashboardOTData(dashboard_id string, options *GetDashb
 		"30_days":    true,
 	}
 	if !intervalValidation[options.Interval] {
-		return nil, fmt.Errorf("could not validate interval parameter: %s", options.Interval)
+		return nil, validationErrorf("could not validate interval parameter: %s", options.Interval)
 	}
 	var ret GetDashboardOTDataResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/dashboard_occupancy_trends"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -108,6 +149,13 @@ func (c *CameraClient) GetDashboardOTData(dashboard_id string, options *GetDashb
 //
 // [Verkada API Docs - Get Max People/Vehicle Counts]: https://apidocs.verkada.com/reference/getmaxobjectcountsviewv1
 func (c *CameraClient) GetMaxCounts(camera_id string, options *GetMaxCountsOptions) (*GetMaxCountsResponse, error) {
+	return c.GetMaxCountsContext(context.Background(), camera_id, options)
+}
+
+// Same as GetMaxCounts, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetMaxCountsContext(ctx context.Context, camera_id string, options *GetMaxCountsOptions) (*GetMaxCountsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetMaxCounts")
+	defer span.End()
 	if options == nil {
 		options = &GetMaxCountsOptions{}
 	}
@@ -116,15 +164,14 @@ func (c *CameraClient) GetMaxCounts(camera_id string, options *GetMaxCountsOptio
 	var b strings.Builder
 	for _, pair := range options.Search_zones {
 		if len(pair) != 2 {
-			return nil, fmt.Errorf("failer to parse GetMaxCountsOptions search_zones: inner arrays must have length 2")
+			return nil, validationErrorf("failed to parse GetMaxCountsOptions search_zones: inner arrays must have length 2")
 		}
-		fmt.Fprintf(&b, "%d.%d.", pair[0], pair[0])
+		fmt.Fprintf(&b, "%d.%d.", pair[0], pair[1])
 	}
 	options.search_zones = strings.TrimSuffix(b.String(), ".")
-	options.Search_zones = nil
 	var ret GetMaxCountsResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/max_object_counts"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -134,41 +181,81 @@ func (c *CameraClient) GetMaxCounts(camera_id string, options *GetMaxCountsOptio
 //
 // [Verkada API Docs - Get People/Vehicle Counts]: https://apidocs.verkada.com/reference/getobjectcountsviewv1
 func (c *CameraClient) GetObjectCounts(camera_id string, options *GetObjectCountsOptions) (*GetObjectCountsResponse, error) {
+	return c.GetObjectCountsContext(context.Background(), camera_id, options)
+}
+
+// Same as GetObjectCounts, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetObjectCountsContext(ctx context.Context, camera_id string, options *GetObjectCountsOptions) (*GetObjectCountsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetObjectCounts")
+	defer span.End()
 	if options == nil {
 		options = &GetObjectCountsOptions{}
 	}
 	options.camera_id = camera_id
-	// page_size must be between 1 and 200
-	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	if err := validateGetObjectCountsOptions(options); err != nil {
+		return nil, err
 	}
 	var ret GetObjectCountsResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/object_counts"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetObjectCountsResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Object_counts = append(ret.Object_counts, nextRet.Object_counts...)
-			ret.Next_page_token = nextRet.Next_page_token
-		}
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.objectCountsPages(*options), &ret.Object_counts)
 	}
 	return &ret, err
 }
 
+// Same as GetObjectCounts, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CameraClient) GetObjectCountsIter(ctx context.Context, camera_id string, options *GetObjectCountsOptions) *Paginator[ObjectCount] {
+	ctx = c.client.withOperation(ctx, "Camera.GetObjectCounts")
+	if options == nil {
+		options = &GetObjectCountsOptions{}
+	}
+	options.camera_id = camera_id
+	if err := validateGetObjectCountsOptions(options); err != nil {
+		return errPaginator[ObjectCount](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.objectCountsPages(*options))
+}
+
+// Returns the page function shared by GetObjectCountsContext and GetObjectCountsIter.
+func (c *CameraClient) objectCountsPages(options GetObjectCountsOptions) pageFunc[ObjectCount] {
+	url := c.client.baseURL + "/cameras/v1/analytics/object_counts"
+	return func(ctx context.Context, token string) ([]ObjectCount, string, error) {
+		options.Page_token = token
+		var ret GetObjectCountsResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Object_counts, ret.Next_page_token, nil
+	}
+}
+
+func validateGetObjectCountsOptions(options *GetObjectCountsOptions) error {
+	// page_size must be between 1 and 200
+	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	}
+	return nil
+}
+
 // Sets the MQTT config for a particular camera.
 //
 // [Verkada API Docs - Set Object Position MQTT Config]
 //
 // [Verkada API Docs - Set Object Position MQTT Config]: https://apidocs.verkada.com/reference/postoccupancytrendsmqttconfigview
 func (c *CameraClient) SetMQTTConfig(broker_cert string, broker_host_port string, camera_id string, body *SetMQTTConfigBody) (*SetMQTTConfigResponse, error) {
+	return c.SetMQTTConfigContext(context.Background(), broker_cert, broker_host_port, camera_id, body)
+}
+
+// Same as SetMQTTConfig, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) SetMQTTConfigContext(ctx context.Context, broker_cert string, broker_host_port string, camera_id string, body *SetMQTTConfigBody) (*SetMQTTConfigResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.SetMQTTConfig")
+	defer span.End()
 	if body == nil {
 		body = &SetMQTTConfigBody{}
 	}
@@ -187,7 +274,7 @@ func (c *CameraClient) SetMQTTConfig(broker_cert string, broker_host_port string
 	}
 	var ret SetMQTTConfigResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/object_position_mqtt"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -197,6 +284,13 @@ func (c *CameraClient) SetMQTTConfig(broker_cert string, broker_host_port string
 //
 // [Verkada API Docs - Get Occupancy Trend Data]: https://apidocs.verkada.com/reference/getoccupancytrendsview
 func (c *CameraClient) GetOTData(camera_id string, preset_id string, options *GetOTDataOptions) (*GetOTDataResponse, error) {
+	return c.GetOTDataContext(context.Background(), camera_id, preset_id, options)
+}
+
+// Same as GetOTData, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetOTDataContext(ctx context.Context, camera_id string, preset_id string, options *GetOTDataOptions) (*GetOTDataResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetOTData")
+	defer span.End()
 	if options == nil {
 		options = &GetOTDataOptions{}
 	}
@@ -209,11 +303,11 @@ func (c *CameraClient) GetOTData(camera_id string, preset_id string, options *Ge
 		"1_day":      true,
 	}
 	if !intervalValidation[options.Interval] {
-		return nil, fmt.Errorf("could not validate interval parameter: %s", options.Interval)
+		return nil, validationErrorf("could not validate interval parameter: %s", options.Interval)
 	}
 	var ret GetOTDataResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/occupancy_trends"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -223,6 +317,13 @@ func (c *CameraClient) GetOTData(camera_id string, preset_id string, options *Ge
 //
 // [Verkada API Docs - Get Dashboard Widget Trend Data]: https://apidocs.verkada.com/reference/postdashboardwidgettrendsview-1
 func (c *CameraClient) GetDashBoardWidgetTrendData(dashboard_id string, body *GetDashboardWidgetTrendDataOptions) (*GetDashboardWidgetTrendDataResponse, error) {
+	return c.GetDashBoardWidgetTrendDataContext(context.Background(), dashboard_id, body)
+}
+
+// Same as GetDashBoardWidgetTrendData, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetDashBoardWidgetTrendDataContext(ctx context.Context, dashboard_id string, body *GetDashboardWidgetTrendDataOptions) (*GetDashboardWidgetTrendDataResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetDashBoardWidgetTrendData")
+	defer span.End()
 	if body == nil {
 		body = &GetDashboardWidgetTrendDataOptions{}
 	}
@@ -233,7 +334,7 @@ func (c *CameraClient) GetDashBoardWidgetTrendData(dashboard_id string, body *Ge
 		"PT1D":  true,
 	}
 	if !intervalValidation[body.Interval] {
-		return nil, fmt.Errorf("could not validate interval parameter: %s", body.Interval)
+		return nil, validationErrorf("could not validate interval parameter: %s", body.Interval)
 	}
 	widgetValidation := map[string]bool{
 		"occupancy":  true,
@@ -243,12 +344,12 @@ func (c *CameraClient) GetDashBoardWidgetTrendData(dashboard_id string, body *Ge
 	}
 	for _, widget_type := range body.Widget_types {
 		if !widgetValidation[widget_type] {
-			return nil, fmt.Errorf("parameter widget_types should only contain \"occupancy\", \"helix\", \"conversion\", and/or \"queue\" - received %s", widget_type)
+			return nil, validationErrorf("parameter widget_types should only contain \"occupancy\", \"helix\", \"conversion\", and/or \"queue\" - received %s", widget_type)
 		}
 	}
 	var ret GetDashboardWidgetTrendDataResponse
 	url := c.client.baseURL + "/v2/analytics/operational_dashboard/" + dashboard_id + "/widget_trends/query"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, *body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, *body, &ret, 0)
 	return &ret, err
 }
 
@@ -259,33 +360,79 @@ func (c *CameraClient) GetDashBoardWidgetTrendData(dashboard_id string, body *Ge
 //
 // [Verkada API Docs - Get Seen License Plates]: https://apidocs.verkada.com/reference/getlprimagesview
 func (c *CameraClient) GetSeenPlates(camera_id string, options *GetSeenPlatesOptions) (*GetSeenPlatesResponse, error) {
+	return c.GetSeenPlatesContext(context.Background(), camera_id, options)
+}
+
+// Same as GetSeenPlates, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetSeenPlatesContext(ctx context.Context, camera_id string, options *GetSeenPlatesOptions) (*GetSeenPlatesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetSeenPlates")
+	defer span.End()
 	if options == nil {
 		options = &GetSeenPlatesOptions{}
 	}
 	options.camera_id = camera_id
-	// page_size must be between 1 and 200
-	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	if err := validateGetSeenPlatesOptions(options); err != nil {
+		return nil, err
 	}
 	var ret GetSeenPlatesResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/images"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for ret.Next_page_token != 0 {
-			var nextRet GetSeenPlatesResponse
-			options.Page_token = Ptr(ret.Next_page_token)
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
+		var next string
+		next, err = collectPages(ctx, intToken(ret.Next_page_token), c.seenPlatesPages(*options), &ret.Detections)
+		ret.Next_page_token, _ = strconv.Atoi(next)
+	}
+	return &ret, err
+}
+
+// Same as GetSeenPlates, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CameraClient) GetSeenPlatesIter(ctx context.Context, camera_id string, options *GetSeenPlatesOptions) *Paginator[PlateDetection] {
+	ctx = c.client.withOperation(ctx, "Camera.GetSeenPlates")
+	if options == nil {
+		options = &GetSeenPlatesOptions{}
+	}
+	options.camera_id = camera_id
+	if err := validateGetSeenPlatesOptions(options); err != nil {
+		return errPaginator[PlateDetection](err)
+	}
+	var token string
+	if options.Page_token != nil {
+		token = intToken(*options.Page_token)
+	}
+	return newPaginator(ctx, token, c.seenPlatesPages(*options))
+}
+
+// Returns the page function shared by GetSeenPlatesContext and GetSeenPlatesIter.
+func (c *CameraClient) seenPlatesPages(options GetSeenPlatesOptions) pageFunc[PlateDetection] {
+	url := c.client.baseURL + "/cameras/v1/analytics/lpr/images"
+	return func(ctx context.Context, token string) ([]PlateDetection, string, error) {
+		options.Page_token = nil
+		if token != "" {
+			n, err := strconv.Atoi(token)
 			if err != nil {
-				return &ret, err
+				return nil, "", validationErrorf("invalid page token: %s", token)
 			}
-			ret.Detections = append(ret.Detections, nextRet.Detections...)
-			ret.Next_page_token = nextRet.Next_page_token
+			options.Page_token = &n
+		}
+		var ret GetSeenPlatesResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
 		}
+		return ret.Detections, intToken(ret.Next_page_token), nil
 	}
-	return &ret, err
+}
+
+func validateGetSeenPlatesOptions(options *GetSeenPlatesOptions) error {
+	// page_size must be between 1 and 200
+	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	}
+	return nil
 }
 
 // Deletes a license plate from License Plates of Interest using a specified license plate number.
@@ -294,10 +441,17 @@ func (c *CameraClient) GetSeenPlates(camera_id string, options *GetSeenPlatesOpt
 //
 // [Verkada API Docs - Delete a License Plate of Interest]: https://apidocs.verkada.com/reference/deletelicenseplateofinterestviewv1
 func (c *CameraClient) DeleteLPOI(license_plate string) (*DeleteLPOIResponse, error) {
+	return c.DeleteLPOIContext(context.Background(), license_plate)
+}
+
+// Same as DeleteLPOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) DeleteLPOIContext(ctx context.Context, license_plate string) (*DeleteLPOIResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.DeleteLPOI")
+	defer span.End()
 	options := &DeleteLPOIOptions{license_plate: license_plate}
 	var ret DeleteLPOIResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -307,40 +461,79 @@ func (c *CameraClient) DeleteLPOI(license_plate string) (*DeleteLPOIResponse, er
 //
 // [Verkada API Docs - Get All License Plates of Interest]: https://apidocs.verkada.com/reference/getlicenseplateofinterestviewv1
 func (c *CameraClient) GetAllLPOI(options *GetAllLPOIOptions) (*GetAllLPOIResponse, error) {
+	return c.GetAllLPOIContext(context.Background(), options)
+}
+
+// Same as GetAllLPOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetAllLPOIContext(ctx context.Context, options *GetAllLPOIOptions) (*GetAllLPOIResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetAllLPOI")
+	defer span.End()
 	if options == nil {
 		options = &GetAllLPOIOptions{}
 	}
-	// page_size must be between 1 and 10,000
-	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 10000) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 10,000", *options.Page_size)
+	if err := validateGetAllLPOIOptions(options); err != nil {
+		return nil, err
 	}
 	var ret GetAllLPOIResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetAllLPOIResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.License_plate_of_interest = append(ret.License_plate_of_interest, nextRet.License_plate_of_interest...)
-			ret.Next_page_token = nextRet.Next_page_token
-		}
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.allLPOIPages(*options), &ret.License_plate_of_interest)
 	}
 	return &ret, err
 }
 
+// Same as GetAllLPOI, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CameraClient) GetAllLPOIIter(ctx context.Context, options *GetAllLPOIOptions) *Paginator[LicensePlateOfInterest] {
+	ctx = c.client.withOperation(ctx, "Camera.GetAllLPOI")
+	if options == nil {
+		options = &GetAllLPOIOptions{}
+	}
+	if err := validateGetAllLPOIOptions(options); err != nil {
+		return errPaginator[LicensePlateOfInterest](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.allLPOIPages(*options))
+}
+
+// Returns the page function shared by GetAllLPOIContext and GetAllLPOIIter.
+func (c *CameraClient) allLPOIPages(options GetAllLPOIOptions) pageFunc[LicensePlateOfInterest] {
+	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest"
+	return func(ctx context.Context, token string) ([]LicensePlateOfInterest, string, error) {
+		options.Page_token = token
+		var ret GetAllLPOIResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.License_plate_of_interest, ret.Next_page_token, nil
+	}
+}
+
+func validateGetAllLPOIOptions(options *GetAllLPOIOptions) error {
+	// page_size must be between 1 and 10,000
+	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 10000) {
+		return validationErrorf("parameter page_size (%d) is not between 1 and 10,000", *options.Page_size)
+	}
+	return nil
+}
+
 // Updates a license plate description from License Plates of Interest using a specified license plate number.
 //
 // [Verkada API Docs - Update a License of Interest]
 //
 // [Verkada API Docs - Update a License of Interest]: https://apidocs.verkada.com/reference/patchlicenseplateofinterestviewv1
 func (c *CameraClient) UpdateLPOI(license_plate string, description string) (*UpdateLPOIResponse, error) {
+	return c.UpdateLPOIContext(context.Background(), license_plate, description)
+}
+
+// Same as UpdateLPOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) UpdateLPOIContext(ctx context.Context, license_plate string, description string) (*UpdateLPOIResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.UpdateLPOI")
+	defer span.End()
 	options := &UpdateLPOIOptions{license_plate: license_plate}
 	body := struct {
 		Description string `json:"description"`
@@ -349,7 +542,7 @@ func (c *CameraClient) UpdateLPOI(license_plate string, description string) (*Up
 	}
 	var ret UpdateLPOIResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest"
-	err := c.client.MakeVerkadaRequest("PATCH", url, *options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PATCH", url, *options, body, &ret, 0)
 	return &ret, err
 }
 
@@ -359,6 +552,13 @@ func (c *CameraClient) UpdateLPOI(license_plate string, description string) (*Up
 //
 // [Verkada API Docs - Create a License Plate of Interest]: https://apidocs.verkada.com/reference/postlicenseplateofinterestviewv1
 func (c *CameraClient) CreateLPOI(license_plate string, description string) (*CreateLPOIResponse, error) {
+	return c.CreateLPOIContext(context.Background(), license_plate, description)
+}
+
+// Same as CreateLPOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) CreateLPOIContext(ctx context.Context, license_plate string, description string) (*CreateLPOIResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.CreateLPOI")
+	defer span.End()
 	body := struct {
 		License_plate string `json:"license_plate"`
 		Description   string `json:"description"`
@@ -368,7 +568,7 @@ func (c *CameraClient) CreateLPOI(license_plate string, description string) (*Cr
 	}
 	var ret CreateLPOIResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -378,9 +578,36 @@ func (c *CameraClient) CreateLPOI(license_plate string, description string) (*Cr
 //
 // [Verkada API Docs - Delete the License Plates of Interest by CSV]: https://apidocs.verkada.com/reference/deletelicenseplateofinterestbulkoperationviewv1
 func (c *CameraClient) DeleteLPOIByCSV(filename string) (*DeleteLPOIByCSVResponse, error) {
+	return c.DeleteLPOIByCSVContext(context.Background(), filename)
+}
+
+// Same as DeleteLPOIByCSV, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) DeleteLPOIByCSVContext(ctx context.Context, filename string) (*DeleteLPOIByCSVResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.DeleteLPOIByCSV")
+	defer span.End()
+	var ret DeleteLPOIByCSVResponse
+	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest/batch"
+	err := c.client.MakeVerkadaRequestWithFileContext(ctx, "DELETE", url, nil, filename, "text/csv", &ret, 0)
+	return &ret, err
+}
+
+// Same as DeleteLPOIByCSV, uploading the csv from r instead of a file on disk.
+// options can set a size limit and progress callback, and may be nil.
+func (c *CameraClient) DeleteLPOIByCSVFromReader(r io.Reader, options *UploadOptions) (*DeleteLPOIByCSVResponse, error) {
+	return c.DeleteLPOIByCSVFromReaderContext(context.Background(), r, options)
+}
+
+// Same as DeleteLPOIByCSVFromReader, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) DeleteLPOIByCSVFromReaderContext(ctx context.Context, r io.Reader, options *UploadOptions) (*DeleteLPOIByCSVResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.DeleteLPOIByCSVFromReader")
+	defer span.End()
+	upload := Upload{Filename: "license_plates.csv", ContentType: "text/csv", Body: r}
+	if options != nil {
+		upload.UploadOptions = *options
+	}
 	var ret DeleteLPOIByCSVResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest/batch"
-	err := c.client.MakeVerkadaRequestWithFile("DELETE", url, nil, filename, "text/csv", &ret, 0)
+	err := c.client.MakeVerkadaRequestWithReaderContext(ctx, "DELETE", url, nil, upload, &ret, 0)
 	return &ret, err
 }
 
@@ -390,9 +617,36 @@ func (c *CameraClient) DeleteLPOIByCSV(filename string) (*DeleteLPOIByCSVRespons
 //
 // [Verkada API Docs - Create License Plates of Interest by CSV]: https://apidocs.verkada.com/reference/postlicenseplateofinterestbulkoperationviewv1
 func (c *CameraClient) CreateLPOIByCSV(filename string) (*CreateLPOIByCSVResponse, error) {
+	return c.CreateLPOIByCSVContext(context.Background(), filename)
+}
+
+// Same as CreateLPOIByCSV, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) CreateLPOIByCSVContext(ctx context.Context, filename string) (*CreateLPOIByCSVResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.CreateLPOIByCSV")
+	defer span.End()
+	var ret CreateLPOIByCSVResponse
+	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest/batch"
+	err := c.client.MakeVerkadaRequestWithFileContext(ctx, "POST", url, nil, filename, "text/csv", &ret, 0)
+	return &ret, err
+}
+
+// Same as CreateLPOIByCSV, uploading the csv from r instead of a file on disk.
+// options can set a size limit and progress callback, and may be nil.
+func (c *CameraClient) CreateLPOIByCSVFromReader(r io.Reader, options *UploadOptions) (*CreateLPOIByCSVResponse, error) {
+	return c.CreateLPOIByCSVFromReaderContext(context.Background(), r, options)
+}
+
+// Same as CreateLPOIByCSVFromReader, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) CreateLPOIByCSVFromReaderContext(ctx context.Context, r io.Reader, options *UploadOptions) (*CreateLPOIByCSVResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.CreateLPOIByCSVFromReader")
+	defer span.End()
+	upload := Upload{Filename: "license_plates.csv", ContentType: "text/csv", Body: r}
+	if options != nil {
+		upload.UploadOptions = *options
+	}
 	var ret CreateLPOIByCSVResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/license_plate_of_interest/batch"
-	err := c.client.MakeVerkadaRequestWithFile("POST", url, nil, filename, "text/csv", &ret, 0)
+	err := c.client.MakeVerkadaRequestWithReaderContext(ctx, "POST", url, nil, upload, &ret, 0)
 	return &ret, err
 }
 
@@ -402,29 +656,65 @@ func (c *CameraClient) CreateLPOIByCSV(filename string) (*CreateLPOIByCSVRespons
 //
 // [Verkada API Docs - Get Timestamps for a License Plate]: https://apidocs.verkada.com/reference/getlprtimestampsview
 func (c *CameraClient) GetLicensePlateTS(camera_id string, license_plate string, options *GetLicensePlateTSOptions) (*GetLicensePlateTSResponse, error) {
+	return c.GetLicensePlateTSContext(context.Background(), camera_id, license_plate, options)
+}
+
+// Same as GetLicensePlateTS, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetLicensePlateTSContext(ctx context.Context, camera_id string, license_plate string, options *GetLicensePlateTSOptions) (*GetLicensePlateTSResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetLicensePlateTS")
+	defer span.End()
 	if options == nil {
 		options = &GetLicensePlateTSOptions{}
 	}
 	options.camera_id, options.license_plate = camera_id, license_plate
 	var ret GetLicensePlateTSResponse
 	url := c.client.baseURL + "/cameras/v1/analytics/lpr/timestamps"
-	err := c.client.MakeVerkadaRequest("GET", url, options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for ret.Next_page_token != 0 {
-			var nextRet GetLicensePlateTSResponse
-			options.Page_token = Ptr(ret.Next_page_token)
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
+		var next string
+		next, err = collectPages(ctx, intToken(ret.Next_page_token), c.licensePlateTSPages(*options), &ret.Detections)
+		ret.Next_page_token, _ = strconv.Atoi(next)
+	}
+	return &ret, err
+}
+
+// Same as GetLicensePlateTS, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CameraClient) GetLicensePlateTSIter(ctx context.Context, camera_id string, license_plate string, options *GetLicensePlateTSOptions) *Paginator[int] {
+	ctx = c.client.withOperation(ctx, "Camera.GetLicensePlateTS")
+	if options == nil {
+		options = &GetLicensePlateTSOptions{}
+	}
+	options.camera_id, options.license_plate = camera_id, license_plate
+	var token string
+	if options.Page_token != nil {
+		token = intToken(*options.Page_token)
+	}
+	return newPaginator(ctx, token, c.licensePlateTSPages(*options))
+}
+
+// Returns the page function shared by GetLicensePlateTSContext and GetLicensePlateTSIter.
+func (c *CameraClient) licensePlateTSPages(options GetLicensePlateTSOptions) pageFunc[int] {
+	url := c.client.baseURL + "/cameras/v1/analytics/lpr/timestamps"
+	return func(ctx context.Context, token string) ([]int, string, error) {
+		options.Page_token = nil
+		if token != "" {
+			n, err := strconv.Atoi(token)
 			if err != nil {
-				return &ret, err
+				return nil, "", validationErrorf("invalid page token: %s", token)
 			}
-			ret.Detections = append(ret.Detections, nextRet.Detections...)
-			ret.Next_page_token = nextRet.Next_page_token
+			options.Page_token = &n
+		}
+		var ret GetLicensePlateTSResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
 		}
+		return ret.Detections, intToken(ret.Next_page_token), nil
 	}
-	return &ret, err
 }
 
 // Return the software enabled status of the specified camera.
@@ -433,10 +723,17 @@ func (c *CameraClient) GetLicensePlateTS(camera_id string, license_plate string,
 //
 // [Verkada API Docs - Get Camera Audio Status]: https://apidocs.verkada.com/reference/getcameraaudioviewv1
 func (c *CameraClient) GetCameraAudioStatus(camera_id string) (*GetCameraAudioStatusResponse, error) {
+	return c.GetCameraAudioStatusContext(context.Background(), camera_id)
+}
+
+// Same as GetCameraAudioStatus, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetCameraAudioStatusContext(ctx context.Context, camera_id string) (*GetCameraAudioStatusResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetCameraAudioStatus")
+	defer span.End()
 	options := &GetCameraAudioStatusOptions{camera_id: camera_id}
 	var ret GetCameraAudioStatusResponse
 	url := c.client.baseURL + "/cameras/v1/audio/status"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -446,6 +743,13 @@ func (c *CameraClient) GetCameraAudioStatus(camera_id string) (*GetCameraAudioSt
 //
 // [Verkada API Docs - Update Camera Audio Status]: https://apidocs.verkada.com/reference/postcameraaudioviewv1
 func (c *CameraClient) UpdateCameraAudio(camera_id string, enabled bool) (*UpdateCameraAudioResponse, error) {
+	return c.UpdateCameraAudioContext(context.Background(), camera_id, enabled)
+}
+
+// Same as UpdateCameraAudio, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) UpdateCameraAudioContext(ctx context.Context, camera_id string, enabled bool) (*UpdateCameraAudioResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.UpdateCameraAudio")
+	defer span.End()
 	body := struct {
 		Camera_id string `json:"camera_id"`
 		Enabled   bool   `json:"enabled"`
@@ -455,7 +759,7 @@ func (c *CameraClient) UpdateCameraAudio(camera_id string, enabled bool) (*Updat
 	}
 	var ret UpdateCameraAudioResponse
 	url := c.client.baseURL + "/cameras/v1/audio/status"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -466,10 +770,17 @@ func (c *CameraClient) UpdateCameraAudio(camera_id string, enabled bool) (*Updat
 //
 // [Verkada API Docs - Get Cloud Backup Settings]: https://apidocs.verkada.com/reference/getcloudbackupviewv1
 func (c *CameraClient) GetCBSettings(camera_id string) (*GetCBSettingsResponse, error) {
+	return c.GetCBSettingsContext(context.Background(), camera_id)
+}
+
+// Same as GetCBSettings, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetCBSettingsContext(ctx context.Context, camera_id string) (*GetCBSettingsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetCBSettings")
+	defer span.End()
 	options := &GetCBSettingsOptions{camera_id: camera_id}
 	var ret GetCBSettingsResponse
 	url := c.client.baseURL + "/cameras/v1/cloud_backup/settings"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -480,9 +791,16 @@ func (c *CameraClient) GetCBSettings(camera_id string) (*GetCBSettingsResponse,
 //
 // [Verkada API Docs - Update Cloud Backup Settings]: https://apidocs.verkada.com/reference/postcloudbackupviewv1
 func (c *CameraClient) UpdateCBSettings(camera_id string, days_to_preserve string, enabled int, time_to_preserve string, upload_timeslot string, video_quality string, video_to_upload string) (*UpdateCBSettingsResponse, error) {
+	return c.UpdateCBSettingsContext(context.Background(), camera_id, days_to_preserve, enabled, time_to_preserve, upload_timeslot, video_quality, video_to_upload)
+}
+
+// Same as UpdateCBSettings, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) UpdateCBSettingsContext(ctx context.Context, camera_id string, days_to_preserve string, enabled int, time_to_preserve string, upload_timeslot string, video_quality string, video_to_upload string) (*UpdateCBSettingsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.UpdateCBSettings")
+	defer span.End()
 	// check formatting on days_to_preserve (7 characters 0/1, 6 delimiters ",")
 	if len(days_to_preserve) != 13 {
-		return nil, fmt.Errorf("parameter days_to_preserve is not the correct length (13) - %s length %d", days_to_preserve, len(days_to_preserve))
+		return nil, validationErrorf("parameter days_to_preserve is not the correct length (13) - %s length %d", days_to_preserve, len(days_to_preserve))
 	}
 	validLetters := map[string]bool{
 		"0": true,
@@ -491,40 +809,40 @@ func (c *CameraClient) UpdateCBSettings(camera_id string, days_to_preserve strin
 	}
 	for _, l := range days_to_preserve {
 		if !validLetters[string(l)] {
-			return nil, fmt.Errorf("parameter days_to_preserve includes invalid letters - should only be 0 or 1 with \",\" as a delimiter - received %s", days_to_preserve)
+			return nil, validationErrorf("parameter days_to_preserve includes invalid letters - should only be 0 or 1 with \",\" as a delimiter - received %s", days_to_preserve)
 		}
 	}
 	// enabled is int but can only be 0 or 1
 	if !(enabled == 0 || enabled == 1) {
-		return nil, fmt.Errorf("parameter enabled can only be 0 or 1 - received %d", enabled)
+		return nil, validationErrorf("parameter enabled can only be 0 or 1 - received %d", enabled)
 	}
 	// check if time_to_preserve is delimited correctly (does not check valid values)
 	// valid values should be 0 <= start_time < end_time < 86399
 	splitTimeToPreserve := strings.Split(time_to_preserve, ",")
 	switch len(splitTimeToPreserve) {
 	case 1:
-		return nil, fmt.Errorf("did not delimit time_to_preserve with \",\" - received %s", time_to_preserve)
+		return nil, validationErrorf("did not delimit time_to_preserve with \",\" - received %s", time_to_preserve)
 	case 2:
 	default:
-		return nil, fmt.Errorf("too many delimiters detected in time_to_preserve - received %s", time_to_preserve)
+		return nil, validationErrorf("too many delimiters detected in time_to_preserve - received %s", time_to_preserve)
 	}
 	// check if upload_timeslot is delimited correctly (does not check valid values)
 	// valid values should be 0 <= start_time < end_time < 86399
 	splitUploadTimeslot := strings.Split(upload_timeslot, ",")
 	switch len(splitUploadTimeslot) {
 	case 1:
-		return nil, fmt.Errorf("did not delimit upload_timeslot with \",\" - received %s", upload_timeslot)
+		return nil, validationErrorf("did not delimit upload_timeslot with \",\" - received %s", upload_timeslot)
 	case 2:
 	default:
-		return nil, fmt.Errorf("too many delimiters detected in upload_timeslot - received %s", upload_timeslot)
+		return nil, validationErrorf("too many delimiters detected in upload_timeslot - received %s", upload_timeslot)
 	}
 	// video_quality can only be STANDARD_QUALITY or HIGH_QUALITY
 	if !(video_quality == "STANDARD_QUALITY" || video_quality == "HIGH_QUALITY") {
-		return nil, fmt.Errorf("parameter video_quality can only be \"STANDARD_QUALITY\" or \"HIGH_QUALITY\" - received %s", video_quality)
+		return nil, validationErrorf("parameter video_quality can only be \"STANDARD_QUALITY\" or \"HIGH_QUALITY\" - received %s", video_quality)
 	}
 	// video_to_upload can only be MOTION or ALL
 	if !(video_to_upload == "MOTION" || video_to_upload == "ALL") {
-		return nil, fmt.Errorf("parameter video_to_upload can only be \"MOTIONS\" or \"ALL\" - received %s", video_to_upload)
+		return nil, validationErrorf("parameter video_to_upload can only be \"MOTIONS\" or \"ALL\" - received %s", video_to_upload)
 	}
 	fullBody := struct {
 		Camera_id        string `json:"camera_id"`
@@ -545,7 +863,7 @@ func (c *CameraClient) UpdateCBSettings(camera_id string, days_to_preserve strin
 	}
 	var ret UpdateCBSettingsResponse
 	url := c.client.baseURL + "/cameras/v1/cloud_backup/settings"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -555,43 +873,82 @@ func (c *CameraClient) UpdateCBSettings(camera_id string, days_to_preserve strin
 //
 // [Verkada API Docs - Get Camera Data]: https://apidocs.verkada.com/reference/getcamerainfoviewv1
 func (c *CameraClient) GetCameraDevices(options *GetCameraDevicesOptions) (*GetCameraDevicesResponse, error) {
+	return c.GetCameraDevicesContext(context.Background(), options)
+}
+
+// Same as GetCameraDevices, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetCameraDevicesContext(ctx context.Context, options *GetCameraDevicesOptions) (*GetCameraDevicesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetCameraDevices")
+	defer span.End()
 	if options == nil {
 		options = &GetCameraDevicesOptions{}
 	}
-	// page_size must be between 1 and 10,000
-	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 10000) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 10,000", *options.Page_size)
+	if err := validateGetCameraDevicesOptions(options); err != nil {
+		return nil, err
 	}
 	var ret GetCameraDevicesResponse
 	url := c.client.baseURL + "/cameras/v1/devices"
-	err := c.client.MakeVerkadaRequest("GET", url, options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetCameraDevicesResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Cameras = append(ret.Cameras, nextRet.Cameras...)
-			ret.Next_page_token = nextRet.Next_page_token
-		}
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.cameraDevicesPages(*options), &ret.Cameras)
 	}
 	return &ret, err
 }
 
+// Same as GetCameraDevices, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CameraClient) GetCameraDevicesIter(ctx context.Context, options *GetCameraDevicesOptions) *Paginator[CameraDevice] {
+	ctx = c.client.withOperation(ctx, "Camera.GetCameraDevices")
+	if options == nil {
+		options = &GetCameraDevicesOptions{}
+	}
+	if err := validateGetCameraDevicesOptions(options); err != nil {
+		return errPaginator[CameraDevice](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.cameraDevicesPages(*options))
+}
+
+// Returns the page function shared by GetCameraDevicesContext and GetCameraDevicesIter.
+func (c *CameraClient) cameraDevicesPages(options GetCameraDevicesOptions) pageFunc[CameraDevice] {
+	url := c.client.baseURL + "/cameras/v1/devices"
+	return func(ctx context.Context, token string) ([]CameraDevice, string, error) {
+		options.Page_token = token
+		var ret GetCameraDevicesResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Cameras, ret.Next_page_token, nil
+	}
+}
+
+func validateGetCameraDevicesOptions(options *GetCameraDevicesOptions) error {
+	// page_size must be between 1 and 10,000
+	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 10000) {
+		return validationErrorf("parameter page_size (%d) is not between 1 and 10,000", *options.Page_size)
+	}
+	return nil
+}
+
 // Returns a list of of each camera in the organization that supports occupancy trends with its line preset identifiers.
 //
 // [Verkada API Docs - Get Occupancy Trends Cameras]
 //
 // [Verkada API Docs - Get Occupancy Trends Cameras]: https://apidocs.verkada.com/reference/getoccupancytrendscamerasviewv1
 func (c *CameraClient) GetOTCameras() (*GetOTCamerasResponse, error) {
+	return c.GetOTCamerasContext(context.Background())
+}
+
+// Same as GetOTCameras, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetOTCamerasContext(ctx context.Context) (*GetOTCamerasResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetOTCameras")
+	defer span.End()
 	var ret GetOTCamerasResponse
 	url := c.client.baseURL + "/cameras/v1/occupancy_trend_enabled"
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -601,13 +958,20 @@ func (c *CameraClient) GetOTCameras() (*GetOTCamerasResponse, error) {
 //
 // [Verkada API Docs - Get Link to Footage]: https://apidocs.verkada.com/reference/gethistoryurlviewv1
 func (c *CameraClient) GetLinkToFootage(camera_id string, options *GetLinkToFootageOptions) (*GetLinkToFootageResponse, error) {
+	return c.GetLinkToFootageContext(context.Background(), camera_id, options)
+}
+
+// Same as GetLinkToFootage, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetLinkToFootageContext(ctx context.Context, camera_id string, options *GetLinkToFootageOptions) (*GetLinkToFootageResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetLinkToFootage")
+	defer span.End()
 	if options == nil {
 		options = &GetLinkToFootageOptions{}
 	}
 	options.camera_id = camera_id
 	var ret GetLinkToFootageResponse
 	url := c.client.baseURL + "/cameras/v1/footage/link"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -617,75 +981,177 @@ func (c *CameraClient) GetLinkToFootage(camera_id string, options *GetLinkToFoot
 //
 // [Verkada API Docs - Get Thumbnail Image]: https://apidocs.verkada.com/reference/getthumbnailimageviewv1
 func (c *CameraClient) GetThumbnailImage(camera_id string, options *GetThumbnailImageOptions, filename string) error {
+	return c.GetThumbnailImageContext(context.Background(), camera_id, options, filename)
+}
+
+// Same as GetThumbnailImage, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetThumbnailImageContext(ctx context.Context, camera_id string, options *GetThumbnailImageOptions, filename string) error {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetThumbnailImage")
+	defer span.End()
 	if options == nil {
 		options = &GetThumbnailImageOptions{}
 	}
 	options.camera_id = camera_id
-	// resolution can only be low_res or hi_res
-	resolutionValidation := map[string]bool{
-		"":        true,
-		"low-res": true,
-		"hi-res":  true,
-	}
-	if !resolutionValidation[options.Resolution] {
-		return fmt.Errorf("could not validate resolution parameter: %s", options.Resolution)
+	if err := validateThumbnailResolution(options.Resolution); err != nil {
+		return err
 	}
 	// filename validation and replacement if left blank
 	if filename == "" {
 		filename = "thumbnail.jpg"
 	}
 	if !strings.HasSuffix(filename, ".jpg") {
-		return fmt.Errorf("included filename is not blank but does not end with \".jpg\" - received %s", filename)
+		return validationErrorf("included filename is not blank but does not end with \".jpg\" - received %s", filename)
 	}
 	url := c.client.baseURL + "/cameras/v1/footage/thumbnails"
-	err := c.client.MakeVerkadaRequestForFile("GET", url, *options, filename, 0)
+	err := c.client.MakeVerkadaRequestForFileContext(ctx, "GET", url, *options, filename, 0)
 	return err
 }
 
+// Same as GetThumbnailImage, writing the image to w instead of a file on disk and returning its content type.
+// Nothing is written unless the response is a 2xx image; otherwise an *APIError or *ContentTypeError is returned.
+func (c *CameraClient) GetThumbnailImageToWriter(camera_id string, options *GetThumbnailImageOptions, w io.Writer) (string, error) {
+	return c.GetThumbnailImageToWriterContext(context.Background(), camera_id, options, w)
+}
+
+// Same as GetThumbnailImageToWriter, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetThumbnailImageToWriterContext(ctx context.Context, camera_id string, options *GetThumbnailImageOptions, w io.Writer) (string, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetThumbnailImageToWriter")
+	defer span.End()
+	if options == nil {
+		options = &GetThumbnailImageOptions{}
+	}
+	options.camera_id = camera_id
+	if err := validateThumbnailResolution(options.Resolution); err != nil {
+		return "", err
+	}
+	url := c.client.baseURL + "/cameras/v1/footage/thumbnails"
+	contentType, _, err := c.client.MakeVerkadaRequestToWriterContext(ctx, "GET", url, *options, w, imageContentTypes, 0)
+	return contentType, err
+}
+
+// Same as GetThumbnailImage, returning the image and its content type instead of writing a file.
+func (c *CameraClient) GetThumbnailImageBytes(camera_id string, options *GetThumbnailImageOptions) (*Download, error) {
+	return c.GetThumbnailImageBytesContext(context.Background(), camera_id, options)
+}
+
+// Same as GetThumbnailImageBytes, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetThumbnailImageBytesContext(ctx context.Context, camera_id string, options *GetThumbnailImageOptions) (*Download, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetThumbnailImageBytes")
+	defer span.End()
+	if options == nil {
+		options = &GetThumbnailImageOptions{}
+	}
+	options.camera_id = camera_id
+	if err := validateThumbnailResolution(options.Resolution); err != nil {
+		return nil, err
+	}
+	url := c.client.baseURL + "/cameras/v1/footage/thumbnails"
+	return c.client.downloadBytes(ctx, url, *options, imageContentTypes)
+}
+
 // Returns the latest thumbnail from a specified camera in either low resolution or high resolution.
 //
 // [Verkada API Docs - Get Latest Thumbnail Image]
 //
 // [Verkada API Docs - Get Latest Thumbnail Image]: https://apidocs.verkada.com/reference/getthumbnaillatestviewv1
 func (c *CameraClient) GetLatestThumbnailImage(camera_id string, options *GetLatestThumbnailImageOptions, filename string) error {
+	return c.GetLatestThumbnailImageContext(context.Background(), camera_id, options, filename)
+}
+
+// Same as GetLatestThumbnailImage, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetLatestThumbnailImageContext(ctx context.Context, camera_id string, options *GetLatestThumbnailImageOptions, filename string) error {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetLatestThumbnailImage")
+	defer span.End()
 	if options == nil {
 		options = &GetLatestThumbnailImageOptions{}
 	}
 	options.camera_id = camera_id
-	// resolution can only be low_res or hi_res
-	resolutionValidation := map[string]bool{
-		"":        true,
-		"low-res": true,
-		"hi-res":  true,
-	}
-	if !resolutionValidation[options.Resolution] {
-		return fmt.Errorf("could not validate resolution parameter: %s", options.Resolution)
+	if err := validateThumbnailResolution(options.Resolution); err != nil {
+		return err
 	}
 	// filename validation and replacement if left blank
 	if filename == "" {
 		filename = "thumbnail.jpg"
 	}
 	if !strings.HasSuffix(filename, ".jpg") {
-		return fmt.Errorf("included filename is not blank but does not end with \".jpg\" - received %s", filename)
+		return validationErrorf("included filename is not blank but does not end with \".jpg\" - received %s", filename)
 	}
 	url := c.client.baseURL + "/cameras/v1/footage/thumbnails/latest"
-	err := c.client.MakeVerkadaRequestForFile("GET", url, *options, filename, 0)
+	err := c.client.MakeVerkadaRequestForFileContext(ctx, "GET", url, *options, filename, 0)
 	return err
 }
 
+// Same as GetLatestThumbnailImage, writing the image to w instead of a file on disk and returning its content type.
+// Nothing is written unless the response is a 2xx image; otherwise an *APIError or *ContentTypeError is returned.
+func (c *CameraClient) GetLatestThumbnailImageToWriter(camera_id string, options *GetLatestThumbnailImageOptions, w io.Writer) (string, error) {
+	return c.GetLatestThumbnailImageToWriterContext(context.Background(), camera_id, options, w)
+}
+
+// Same as GetLatestThumbnailImageToWriter, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetLatestThumbnailImageToWriterContext(ctx context.Context, camera_id string, options *GetLatestThumbnailImageOptions, w io.Writer) (string, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetLatestThumbnailImageToWriter")
+	defer span.End()
+	if options == nil {
+		options = &GetLatestThumbnailImageOptions{}
+	}
+	options.camera_id = camera_id
+	if err := validateThumbnailResolution(options.Resolution); err != nil {
+		return "", err
+	}
+	url := c.client.baseURL + "/cameras/v1/footage/thumbnails/latest"
+	contentType, _, err := c.client.MakeVerkadaRequestToWriterContext(ctx, "GET", url, *options, w, imageContentTypes, 0)
+	return contentType, err
+}
+
+// Same as GetLatestThumbnailImage, returning the image and its content type instead of writing a file.
+func (c *CameraClient) GetLatestThumbnailImageBytes(camera_id string, options *GetLatestThumbnailImageOptions) (*Download, error) {
+	return c.GetLatestThumbnailImageBytesContext(context.Background(), camera_id, options)
+}
+
+// Same as GetLatestThumbnailImageBytes, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetLatestThumbnailImageBytesContext(ctx context.Context, camera_id string, options *GetLatestThumbnailImageOptions) (*Download, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetLatestThumbnailImageBytes")
+	defer span.End()
+	if options == nil {
+		options = &GetLatestThumbnailImageOptions{}
+	}
+	options.camera_id = camera_id
+	if err := validateThumbnailResolution(options.Resolution); err != nil {
+		return nil, err
+	}
+	url := c.client.baseURL + "/cameras/v1/footage/thumbnails/latest"
+	return c.client.downloadBytes(ctx, url, *options, imageContentTypes)
+}
+
+// Thumbnail resolution can only be low-res or hi-res.
+func validateThumbnailResolution(resolution string) error {
+	switch resolution {
+	case "", "low-res", "hi-res":
+		return nil
+	}
+	return validationErrorf("could not validate resolution parameter: %s", resolution)
+}
+
 // Returns a link to thumbnail image from a specified camera at a specified time.
 //
 // [Verkada API Docs - Get Thumbnail Link]
 //
 // [Verkada API Docs - Get Thumbnail Link]: https://apidocs.verkada.com/reference/getthumbnaillinkviewv1
 func (c *CameraClient) GetThumbnailLink(camera_id string, options *GetThumbnailLinkOptions) (*GetThumbnailLinkResponse, error) {
+	return c.GetThumbnailLinkContext(context.Background(), camera_id, options)
+}
+
+// Same as GetThumbnailLink, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetThumbnailLinkContext(ctx context.Context, camera_id string, options *GetThumbnailLinkOptions) (*GetThumbnailLinkResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetThumbnailLink")
+	defer span.End()
 	if options == nil {
 		options = &GetThumbnailLinkOptions{}
 	}
 	options.camera_id = camera_id
 	var ret GetThumbnailLinkResponse
 	url := c.client.baseURL + "/cameras/v1/footage/thumbnails/link"
-	err := c.client.MakeVerkadaRequest("GET", url, options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -696,11 +1162,22 @@ func (c *CameraClient) GetThumbnailLink(camera_id string, options *GetThumbnailL
 //
 // [Verkada API Docs - Get Streaming Token]: https://apidocs.verkada.com/reference/getfootagetokenviewv1
 func (c *CameraClient) GetStreamingToken() (*GetStreamingTokenResponse, error) {
+	return c.GetStreamingTokenContext(context.Background())
+}
+
+// Same as GetStreamingToken, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetStreamingTokenContext(ctx context.Context) (*GetStreamingTokenResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetStreamingToken")
+	defer span.End()
 	var ret GetStreamingTokenResponse
-	buf, jwt, err := auth.GetStreamingToken(c.client.Key, c.client.baseURL)
+	key, err := c.client.Tokens.APIKey(ctx)
 	if err != nil {
 		return nil, err
 	}
+	buf, jwt, err := auth.GetStreamingTokenWithClient(ctx, c.client.httpClient, key, c.client.baseURL)
+	if err != nil {
+		return nil, fromAuthError(err)
+	}
 	err = json.Unmarshal(buf.Bytes(), &ret)
 	if err != nil {
 		return nil, fmt.Errorf("%v - error unmarshing streaming token response - jwt maybe be found \"%s\" <-- empty string if not", err, jwt)
@@ -716,57 +1193,157 @@ func (c *CameraClient) GetStreamingToken() (*GetStreamingTokenResponse, error) {
 //   - If a file is desired, ensure the filename ends with ".m3u8"
 //   - If no file is desired, input an empty string for filename.
 //
+// If jwt is empty, that of the Client's StreamingTokens is used, and a *StreamingScopeError is returned if it does not
+// grant access to the camera (historical access if options has a start time, live otherwise).
+//
 // [Verkada API Docs - Stream Footage]
 //
 // [Verkada API Docs - Stream Footage]: https://apidocs.verkada.com/reference/getfootagestreamviewv1
 func (c *CameraClient) StreamFootage(org_id string, camera_id string, jwt string, options *GetFootageOptions, filename string) (*StreamFootageResponse, error) {
+	return c.StreamFootageContext(context.Background(), org_id, camera_id, jwt, options, filename)
+}
+
+// Same as StreamFootage, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) StreamFootageContext(ctx context.Context, org_id string, camera_id string, jwt string, options *GetFootageOptions, filename string) (*StreamFootageResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.StreamFootage")
+	defer span.End()
 	if options == nil {
 		options = &GetFootageOptions{}
 	}
-	options.org_id, options.camera_id, options.jwt = org_id, camera_id, jwt
-	// check for request duration validity
-	if (options.Start_time != nil) != (options.End_time != nil) {
-		return nil, fmt.Errorf("start_time is provided without end_time (or vice versa) for streaming timestamps")
-	} else if (options.Start_time != nil && options.End_time != nil) && *options.End_time-*options.Start_time > 3600 {
-		return nil, fmt.Errorf("difference between start_time and end_time is too large: %d - %d = %d", *options.End_time, *options.Start_time, (*options.End_time - *options.Start_time))
+	if err := validateGetFootageOptions(options); err != nil {
+		return nil, err
 	}
-	// check for resolution validity
-	resolutionValidation := map[string]bool{
-		"":         true,
-		"low_res":  true,
-		"high_res": true,
+	if jwt == "" {
+		var err error
+		if jwt, err = c.client.StreamingTokens.JWTForCamera(ctx, camera_id, footagePermission(options)); err != nil {
+			return nil, err
+		}
 	}
-	if !resolutionValidation[options.Resolution] {
-		return nil, fmt.Errorf("could not validate resolution parameter: %s", options.Resolution)
+	options.org_id, options.camera_id, options.jwt = org_id, camera_id, jwt
+	url := c.client.streamingURL + "/stream/cameras/v1/footage/stream/stream.m3u8"
+	query, err := encodeQuery(options)
+	if err != nil {
+		return nil, validationErrorf("cannot encode query params: %v", err)
 	}
-	url := c.client.baseURL + "/stream/cameras/v1/footage/stream/stream.m3u8"
 	ret := StreamFootageResponse{
-		Streaming_link: url + "?" + assembleQueryParams(*options),
+		Streaming_link: url + "?" + query.Encode(),
 	}
 	// filename validation and skip writing to file if left blank
 	if filename == "" {
 		return &ret, nil
 	}
 	if !strings.HasSuffix(filename, ".m3u8") {
-		return nil, fmt.Errorf("included filename is not blank but does not end with \".m3u8\" - received %s", filename)
+		return nil, validationErrorf("included filename is not blank but does not end with \".m3u8\" - received %s", filename)
 	}
-	err := c.client.MakeVerkadaRequestForFile("GET", url, *options, filename, 0)
+	err = c.client.MakeVerkadaRequestForFileContext(ctx, "GET", url, *options, filename, 0)
 	return &ret, err
 }
 
+// Same as StreamFootage, writing the HLS playlist to w instead of a file on disk.
+// Nothing is written unless the response is a 2xx playlist; otherwise an *APIError or *ContentTypeError is returned.
+func (c *CameraClient) StreamFootageToWriter(org_id string, camera_id string, jwt string, options *GetFootageOptions, w io.Writer) (*StreamFootageResponse, error) {
+	return c.StreamFootageToWriterContext(context.Background(), org_id, camera_id, jwt, options, w)
+}
+
+// Same as StreamFootageToWriter, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) StreamFootageToWriterContext(ctx context.Context, org_id string, camera_id string, jwt string, options *GetFootageOptions, w io.Writer) (*StreamFootageResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.StreamFootageToWriter")
+	defer span.End()
+	if options == nil {
+		options = &GetFootageOptions{}
+	}
+	if err := validateGetFootageOptions(options); err != nil {
+		return nil, err
+	}
+	if jwt == "" {
+		var err error
+		if jwt, err = c.client.StreamingTokens.JWTForCamera(ctx, camera_id, footagePermission(options)); err != nil {
+			return nil, err
+		}
+	}
+	options.org_id, options.camera_id, options.jwt = org_id, camera_id, jwt
+	url := c.client.streamingURL + "/stream/cameras/v1/footage/stream/stream.m3u8"
+	query, err := encodeQuery(options)
+	if err != nil {
+		return nil, validationErrorf("cannot encode query params: %v", err)
+	}
+	ret := StreamFootageResponse{
+		Streaming_link: url + "?" + query.Encode(),
+	}
+	_, _, err = c.client.MakeVerkadaRequestToWriterContext(ctx, "GET", url, *options, w, playlistContentTypes, 0)
+	return &ret, err
+}
+
+// Same as StreamFootage, returning the HLS playlist and its content type instead of writing a file.
+func (c *CameraClient) StreamFootageBytes(org_id string, camera_id string, jwt string, options *GetFootageOptions) (*StreamFootageResponse, *Download, error) {
+	return c.StreamFootageBytesContext(context.Background(), org_id, camera_id, jwt, options)
+}
+
+// Same as StreamFootageBytes, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) StreamFootageBytesContext(ctx context.Context, org_id string, camera_id string, jwt string, options *GetFootageOptions) (*StreamFootageResponse, *Download, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.StreamFootageBytes")
+	defer span.End()
+	if options == nil {
+		options = &GetFootageOptions{}
+	}
+	if err := validateGetFootageOptions(options); err != nil {
+		return nil, nil, err
+	}
+	if jwt == "" {
+		var err error
+		if jwt, err = c.client.StreamingTokens.JWTForCamera(ctx, camera_id, footagePermission(options)); err != nil {
+			return nil, nil, err
+		}
+	}
+	options.org_id, options.camera_id, options.jwt = org_id, camera_id, jwt
+	url := c.client.streamingURL + "/stream/cameras/v1/footage/stream/stream.m3u8"
+	query, err := encodeQuery(options)
+	if err != nil {
+		return nil, nil, validationErrorf("cannot encode query params: %v", err)
+	}
+	ret := StreamFootageResponse{
+		Streaming_link: url + "?" + query.Encode(),
+	}
+	download, err := c.client.downloadBytes(ctx, url, *options, playlistContentTypes)
+	return &ret, download, err
+}
+
+// Checks the time range and resolution shared by the StreamFootage variants.
+func validateGetFootageOptions(options *GetFootageOptions) error {
+	// check for request duration validity
+	if (options.Start_time != nil) != (options.End_time != nil) {
+		return validationErrorf("start_time is provided without end_time (or vice versa) for streaming timestamps")
+	} else if (options.Start_time != nil && options.End_time != nil) && *options.End_time-*options.Start_time > MaxFootageWindow {
+		return validationErrorf("difference between start_time and end_time is too large: %d - %d = %d", *options.End_time, *options.Start_time, (*options.End_time - *options.Start_time))
+	}
+	// check for resolution validity
+	switch options.Resolution {
+	case "", "low_res", "high_res":
+		return nil
+	}
+	return validationErrorf("could not validate resolution parameter: %s", options.Resolution)
+}
+
 // Deletes a Person of Interest from an organization using a specified person ID.
 //
 // [Verkada API Docs - Delete a Person of Interest]
 //
 // [Verkada API Docs - Delete a Person of Interest]: https://apidocs.verkada.com/reference/deletepersonofinterestviewv1
 func (c *CameraClient) DeletePOI(person_id string, options *DeletePOIOptions) (*POIProfile, error) {
+	return c.DeletePOIContext(context.Background(), person_id, options)
+}
+
+// Same as DeletePOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) DeletePOIContext(ctx context.Context, person_id string, options *DeletePOIOptions) (*POIProfile, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.DeletePOI")
+	defer span.End()
 	if options == nil {
 		options = &DeletePOIOptions{}
 	}
 	options.person_id = person_id
 	var ret POIProfile
 	url := c.client.baseURL + "/cameras/v1/people/person_of_interest"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -776,36 +1353,65 @@ func (c *CameraClient) DeletePOI(person_id string, options *DeletePOIOptions) (*
 //
 // [Verkada API Docs - Get All Person of Interest]: https://apidocs.verkada.com/reference/getpersonofinterestviewv1
 func (c *CameraClient) GetAllPOI(options *GetAllPOIOptions) (*GetAllPOIResponse, error) {
+	return c.GetAllPOIContext(context.Background(), options)
+}
+
+// Same as GetAllPOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) GetAllPOIContext(ctx context.Context, options *GetAllPOIOptions) (*GetAllPOIResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.GetAllPOI")
+	defer span.End()
 	if options == nil {
 		options = &GetAllPOIOptions{}
 	}
 	var ret GetAllPOIResponse
 	url := c.client.baseURL + "/cameras/v1/people/person_of_interest"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for ret.Next_token != "" {
-			var nextRet GetAllPOIResponse
-			options.Page_token = ret.Next_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Persons_of_interest = append(ret.Persons_of_interest, nextRet.Persons_of_interest...)
-			ret.Next_token = nextRet.Next_token
-		}
+		ret.Next_token, err = collectPages(ctx, ret.Next_token, c.allPOIPages(*options), &ret.Persons_of_interest)
 	}
 	return &ret, err
 }
 
+// Same as GetAllPOI, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CameraClient) GetAllPOIIter(ctx context.Context, options *GetAllPOIOptions) *Paginator[POIProfile] {
+	ctx = c.client.withOperation(ctx, "Camera.GetAllPOI")
+	if options == nil {
+		options = &GetAllPOIOptions{}
+	}
+	return newPaginator(ctx, options.Page_token, c.allPOIPages(*options))
+}
+
+// Returns the page function shared by GetAllPOIContext and GetAllPOIIter.
+func (c *CameraClient) allPOIPages(options GetAllPOIOptions) pageFunc[POIProfile] {
+	url := c.client.baseURL + "/cameras/v1/people/person_of_interest"
+	return func(ctx context.Context, token string) ([]POIProfile, string, error) {
+		options.Page_token = token
+		var ret GetAllPOIResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Persons_of_interest, ret.Next_token, nil
+	}
+}
+
 // Updates a label of Person of Interest for an organization using a specified person ID.
 //
 // [Verkada API Docs - Update a Person of Interest]
 //
 // [Verkada API Docs - Update a Person of Interest]: https://apidocs.verkada.com/reference/patchpersonofinterestviewv1
 func (c *CameraClient) UpdatePOI(person_id string, label string) (*POIProfile, error) {
+	return c.UpdatePOIContext(context.Background(), person_id, label)
+}
+
+// Same as UpdatePOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) UpdatePOIContext(ctx context.Context, person_id string, label string) (*POIProfile, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.UpdatePOI")
+	defer span.End()
 	options := UpdatePOIOptions{person_id: person_id}
 	body := struct {
 		Label string `json:"label"`
@@ -814,7 +1420,7 @@ func (c *CameraClient) UpdatePOI(person_id string, label string) (*POIProfile, e
 	}
 	var ret POIProfile
 	url := c.client.baseURL + "/cameras/v1/people/person_of_interest"
-	err := c.client.MakeVerkadaRequest("PATCH", url, options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PATCH", url, options, body, &ret, 0)
 	return &ret, err
 }
 
@@ -825,6 +1431,13 @@ func (c *CameraClient) UpdatePOI(person_id string, label string) (*POIProfile, e
 //
 // [Verkada API Docs - Update a Person of Interest]: https://apidocs.verkada.com/reference/patchpersonofinterestviewv1
 func (c *CameraClient) CreatePOI(filename string, label string) (*POIProfile, error) {
+	return c.CreatePOIContext(context.Background(), filename, label)
+}
+
+// Same as CreatePOI, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) CreatePOIContext(ctx context.Context, filename string, label string) (*POIProfile, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.CreatePOI")
+	defer span.End()
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -843,6 +1456,6 @@ func (c *CameraClient) CreatePOI(filename string, label string) (*POIProfile, er
 	}
 	var ret POIProfile
 	url := c.client.baseURL + "/cameras/v1/people/person_of_interest"
-	err = c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err = c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
@@ -21,7 +21,7 @@ type GetMaxCountsOptions struct {
 	Start_time   *int   `name:"start_time"`
 	End_time     *int   `name:"end_time"`
 	Search_zones [][]int64
-	search_zones string `name:"search#zones"`
+	search_zones string `name:"search_zones"`
 }
 
 type GetObjectCountsOptions struct {
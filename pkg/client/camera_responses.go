@@ -1,17 +1,19 @@
 package client
 
 type GetAlertsResponse struct {
-	Next_page_token string `json:"next_page_token"`
-	Notifications   []struct {
-		Camera_id         string   `json:"camera_id"`
-		Created           int      `json:"created"`
-		Crowd_threshold   int      `json:"crowd_threshold"`
-		Image_url         string   `json:"image_url"`
-		Notification_type string   `json:"notification_type"`
-		Objects           []string `json:"objects"`
-		Person_label      string   `json:"person_label"`
-		Video_url         string   `json:"video_url"`
-	} `json:"notifications"`
+	Next_page_token string         `json:"next_page_token"`
+	Notifications   []Notification `json:"notifications"`
+}
+
+type Notification struct {
+	Camera_id         string   `json:"camera_id"`
+	Created           int      `json:"created"`
+	Crowd_threshold   int      `json:"crowd_threshold"`
+	Image_url         string   `json:"image_url"`
+	Notification_type string   `json:"notification_type"`
+	Objects           []string `json:"objects"`
+	Person_label      string   `json:"person_label"`
+	Video_url         string   `json:"video_url"`
 }
 
 type GetDashboardOTDataResponse struct {
@@ -32,12 +34,14 @@ type GetMaxCountsResponse struct {
 }
 
 type GetObjectCountsResponse struct {
-	Next_page_token string `json:"next_page_token"`
-	Object_counts   []struct {
-		Detected_time int `json:"detected_time"`
-		People_count  int `json:"people_count"`
-		Vehicle_count int `json:"vehicle_count"`
-	} `json:"object_counts"`
+	Next_page_token string        `json:"next_page_token"`
+	Object_counts   []ObjectCount `json:"object_counts"`
+}
+
+type ObjectCount struct {
+	Detected_time int `json:"detected_time"`
+	People_count  int `json:"people_count"`
+	Vehicle_count int `json:"vehicle_count"`
 }
 
 type SetMQTTConfigResponse struct {
@@ -106,14 +110,16 @@ type CameraPreset struct {
 }
 
 type GetSeenPlatesResponse struct {
-	Camera_id  string `json:"camera_id"`
-	Detections []struct {
-		Image_url         string `json:"image_url"`
-		License_plate     string `json:"license_plate"`
-		Timestamp         int    `json:"timestamp"`
-		Vehicle_image_url string `json:"vehicle_image_url"`
-	} `json:"detections"`
-	Next_page_token int `json:"next_page_token"`
+	Camera_id       string           `json:"camera_id"`
+	Detections      []PlateDetection `json:"detections"`
+	Next_page_token int              `json:"next_page_token"`
+}
+
+type PlateDetection struct {
+	Image_url         string `json:"image_url"`
+	License_plate     string `json:"license_plate"`
+	Timestamp         int    `json:"timestamp"`
+	Vehicle_image_url string `json:"vehicle_image_url"`
 }
 
 type DeleteLPOIResponse struct {
@@ -123,12 +129,14 @@ type DeleteLPOIResponse struct {
 }
 
 type GetAllLPOIResponse struct {
-	License_plate_of_interest []struct {
-		Creation_time int    `json:"creation_time"`
-		Description   string `json:"description"`
-		License_plate string `json:"license_plate"`
-	} `json:"license_plate_of_interest"`
-	Next_page_token string `json:"next_page_token"`
+	License_plate_of_interest []LicensePlateOfInterest `json:"license_plate_of_interest"`
+	Next_page_token           string                   `json:"next_page_token"`
+}
+
+type LicensePlateOfInterest struct {
+	Creation_time int    `json:"creation_time"`
+	Description   string `json:"description"`
+	License_plate string `json:"license_plate"`
 }
 
 type UpdateLPOIResponse struct {
@@ -182,31 +190,33 @@ type UpdateCBSettingsResponse struct {
 }
 
 type GetCameraDevicesResponse struct {
-	Cameras []struct {
-		Camera_id                string  `json:"camera_id"`
-		Cloud_retention          int     `json:"cloud_retention"`
-		Date_added               int     `json:"date_added"`
-		Device_retention         int     `json:"device_retention"`
-		Firmware                 string  `json:"firmware"`
-		Firmware_update_schedule string  `json:"firmware_update_schedule"`
-		Last_online              int     `json:"last_online"`
-		Local_ip                 string  `json:"local_ip"`
-		Location                 string  `json:"location"`
-		Location_angle           float64 `json:"location_angle"`
-		Location_lat             float64 `json:"location_lat"`
-		Location_lon             float64 `json:"location_lon"`
-		Mac                      string  `json:"mac"`
-		Model                    string  `json:"model"`
-		Name                     string  `json:"name"`
-		People_history_enabled   bool    `json:"people_history_enabled"`
-		Serial                   string  `json:"serial"`
-		Site                     string  `json:"site"`
-		Site_id                  string  `json:"site_id"`
-		Status                   string  `json:"status"`
-		Timezone                 string  `json:"timezone"`
-		Vehicle_history_enabled  bool    `json:"vehicle_history_enabled"`
-	} `json:"cameras"`
-	Next_page_token string `json:"next_page_token"`
+	Cameras         []CameraDevice `json:"cameras"`
+	Next_page_token string         `json:"next_page_token"`
+}
+
+type CameraDevice struct {
+	Camera_id                string  `json:"camera_id"`
+	Cloud_retention          int     `json:"cloud_retention"`
+	Date_added               int     `json:"date_added"`
+	Device_retention         int     `json:"device_retention"`
+	Firmware                 string  `json:"firmware"`
+	Firmware_update_schedule string  `json:"firmware_update_schedule"`
+	Last_online              int     `json:"last_online"`
+	Local_ip                 string  `json:"local_ip"`
+	Location                 string  `json:"location"`
+	Location_angle           float64 `json:"location_angle"`
+	Location_lat             float64 `json:"location_lat"`
+	Location_lon             float64 `json:"location_lon"`
+	Mac                      string  `json:"mac"`
+	Model                    string  `json:"model"`
+	Name                     string  `json:"name"`
+	People_history_enabled   bool    `json:"people_history_enabled"`
+	Serial                   string  `json:"serial"`
+	Site                     string  `json:"site"`
+	Site_id                  string  `json:"site_id"`
+	Status                   string  `json:"status"`
+	Timezone                 string  `json:"timezone"`
+	Vehicle_history_enabled  bool    `json:"vehicle_history_enabled"`
 }
 
 type GetOTCamerasResponse struct {
@@ -1,5 +1,7 @@
 package client
 
+import "context"
+
 // Gets information about the all the devices in an alarm site specified by site_id.
 //
 // This method is for Classic Alarms sites and devices ONLY.
@@ -8,13 +10,20 @@ package client
 //
 // [Verkada API Docs - Get Alarm Devices]: https://apidocs.verkada.com/reference/getalarmsdevicesviewv1
 func (c *ClassicAlarmsClient) GetAlarmDevices(site_id string, options *GetAlarmDevicesOptions) (*GetAlarmDevicesResponse, error) {
+	return c.GetAlarmDevicesContext(context.Background(), site_id, options)
+}
+
+// Same as GetAlarmDevices, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *ClassicAlarmsClient) GetAlarmDevicesContext(ctx context.Context, site_id string, options *GetAlarmDevicesOptions) (*GetAlarmDevicesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "ClassicAlarms.GetAlarmDevices")
+	defer span.End()
 	if options == nil {
 		options = &GetAlarmDevicesOptions{}
 	}
 	options.site_id = site_id
 	var ret GetAlarmDevicesResponse
 	url := c.client.baseURL + "/alarms/v1/devices"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -26,12 +35,19 @@ func (c *ClassicAlarmsClient) GetAlarmDevices(site_id string, options *GetAlarmD
 //
 // [Verkada API Docs - Get Site Information]: https://apidocs.verkada.com/reference/getalarmssitesviewv1
 func (c *ClassicAlarmsClient) GetAlarmSites(site_ids []string, options *GetAlarmSitesOptions) (*GetAlarmSitesResponse, error) {
+	return c.GetAlarmSitesContext(context.Background(), site_ids, options)
+}
+
+// Same as GetAlarmSites, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *ClassicAlarmsClient) GetAlarmSitesContext(ctx context.Context, site_ids []string, options *GetAlarmSitesOptions) (*GetAlarmSitesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "ClassicAlarms.GetAlarmSites")
+	defer span.End()
 	if options == nil {
 		options = &GetAlarmSitesOptions{}
 	}
 	options.Site_ids = site_ids
 	var ret GetAlarmSitesResponse
 	url := c.client.baseURL + "/alarms/v1/sites"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
@@ -1,40 +1,52 @@
 package client
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"math"
+	"log/slog"
 	"net/http"
 	"os"
-	"reflect"
-	"strings"
 	"time"
 
 	"github.com/GDRCode/verkada-api-go/pkg/client/auth"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // A Client contains the overarching information needed to make API calls.
 // All API requests are made via an underlying http.Client.
 // {Product}Client fields are used to organize which methods apply to which products.
-// The API Key is used to obtain a TokenContainer for a short-lived auth token and its expiration.
+// Tokens obtains and refreshes a short-lived auth token, shared safely between goroutines, using the API key from the
+// Client's auth.CredentialProvider. Key is the API key found when the Client was created and does not follow rotation.
+// StreamingTokens caches the streaming JWT used by footage methods that are not given one.
 type Client struct {
-	httpClient     *http.Client
-	Key            string
-	TokenContainer auth.TokenContainer
-	baseURL        string
-	AutoPaginate   bool
-	Helix          *HelixClient
-	Camera         *CameraClient
-	Core           *CoreClient
-	Sensor         *SensorClient
-	Guest          *GuestClient
-	Access         *AccessClient
-	ClassicAlarms  *ClassicAlarmsClient
-	VX             *VXClient
+	httpClient      *http.Client
+	Key             string
+	Tokens          *auth.TokenSource
+	StreamingTokens *StreamingTokenSource
+	baseURL         string
+	streamingURL    string
+	AutoPaginate    bool
+	retryPolicy     *RetryPolicy
+	decodeMode      DecodeMode
+	onUnknownFields func(UnknownFieldsReport)
+	unknown         unknownFieldTracker
+	logger          *slog.Logger
+	telemetry       *telemetry
+	rateLimiter     *RateLimiter
+	cache           *responseCache
+	Helix           *HelixClient
+	Camera          *CameraClient
+	Core            *CoreClient
+	Sensor          *SensorClient
+	Guest           *GuestClient
+	Access          *AccessClient
+	ClassicAlarms   *ClassicAlarmsClient
+	VX              *VXClient
 }
 
 type HelixClient struct {
@@ -72,31 +84,95 @@ type VXClient struct {
 // Potential options for initiating a new Client.
 // Made into a type struct to allow for future non-breaking option additions.
 //
-// It is recommended to use a .env file for auth.GetEnvFromFile() or os.Setenv() for the API key.
-// The field here is provided in case environment variables are not provided.
+// Credentials supplies the API key, and is asked again whenever a token is requested so that keys can be rotated without
+// rebuilding the Client (see auth.CredentialProvider). If nil, the key is taken from the API_KEY environment variable,
+// then the API_KEY entry of a .env file in the working directory, then APIKey. The process environment is never modified.
+//
+// RetryPolicy controls retries of rate limited, failed, or interrupted requests; DefaultRetryPolicy() is used if nil.
+//
+// HTTPClient can supply proxies, custom TLS roots, or timeouts, and Middleware wraps its transport in order.
+// Both apply to every request the Client makes, including auth and streaming token requests.
+//
+// TokenRefreshWindow is how long before expiration the auth token is refreshed; auth.DefaultRefreshWindow is used if zero.
+//
+// Region names a region registered with RegisterRegion; the built-in regions are "prod1", "prod2", and "au".
+// BaseURL replaces the region's base URL when set, e.g. to point the Client at a verkadatest.Server or a proxy; Region is then ignored.
+// StreamingBaseURL likewise replaces the base URL of streaming links, which otherwise follow the region or BaseURL.
+//
+// SkipTokenFetch defers the first auth token request until the first API request, so that a Client can be built offline.
+//
+// DecodeMode selects strict (the default) or lenient decoding of responses; OnUnknownFields is called in DecodeLenientReport mode
+// whenever responses from an endpoint contain fields missing from the response structs that were not reported before.
+//
+// Logger receives structured logs of every request (method, endpoint, status, latency, retries, and pagination page) and of
+// auth token refreshes, with API keys, tokens, and JWTs redacted. Nothing is logged if nil.
+//
+// TracerProvider and MeterProvider enable OpenTelemetry instrumentation: a span per method call named after the method
+// (e.g. "Camera.GetAlerts") with child spans for pagination pages, and metrics for request latency, 429 responses, retries,
+// and token refreshes. Either is disabled if nil; use otel.GetTracerProvider() and otel.GetMeterProvider() for the global providers.
+//
+// RateLimiter delays requests to stay under per-key quotas, and can be shared by several Clients using the same API key.
+// Requests are only limited by the API's 429 responses if nil.
+//
+// Cache enables caching of GET responses from slow-changing endpoints such as GetCameraDevices, with per-endpoint TTLs,
+// stale-while-revalidate, and invalidation after writes (see CacheOptions). Nothing is cached if nil.
 type ClientOptions struct {
-	Region       string
-	AutoPaginate bool
-	APIKey       string
+	Region             string
+	BaseURL            string
+	StreamingBaseURL   string
+	SkipTokenFetch     bool
+	DecodeMode         DecodeMode
+	OnUnknownFields    func(UnknownFieldsReport)
+	AutoPaginate       bool
+	APIKey             string
+	Credentials        auth.CredentialProvider
+	RetryPolicy        *RetryPolicy
+	HTTPClient         *http.Client
+	Middleware         []Middleware
+	TokenRefreshWindow time.Duration
+	Logger             *slog.Logger
+	TracerProvider     trace.TracerProvider
+	MeterProvider      metric.MeterProvider
+	RateLimiter        *RateLimiter
+	Cache              *CacheOptions
 }
 
 // New returns a Client and any errors relating to configuration options.
 // Region (and therefore base URL for requests) is set at Client creation and cannot be changed.
 // Auto-pagination can be enabled so that paginated responses are combined into one response.
 func New(options *ClientOptions) (*Client, error) {
-	auth.GetEnvFromFile()
-	envKey := os.Getenv("API_KEY")
-	if envKey == "" {
+	credentials := options.Credentials
+	if credentials == nil {
+		chain := auth.DefaultCredentials()
 		if options.APIKey != "" {
-			envKey = options.APIKey
-		} else {
-			return nil, fmt.Errorf("error: no environment variable \"API_KEY\", either set in a .env file for auth.GetEnvFromFile() or set directly using os.SetEnv()")
+			chain = append(chain, auth.StaticProvider(options.APIKey))
 		}
+		credentials = chain
+	}
+	return newClient(options, credentials)
+}
+
+// Builds a Client for New or a ClientPool, getting the API key from credentials.
+func newClient(options *ClientOptions, credentials auth.CredentialProvider) (*Client, error) {
+	key, err := credentials.APIKey(context.Background())
+	if errors.Is(err, auth.ErrNoCredentials) {
+		return nil, fmt.Errorf("error: no API key, set the API_KEY environment variable or .env entry, or ClientOptions.APIKey or Credentials - %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading API key: %w", err)
 	}
 	c := &Client{
-		httpClient:   &http.Client{},
-		Key:          envKey,
-		AutoPaginate: options.AutoPaginate,
+		httpClient:      buildHTTPClient(options.HTTPClient, options.Middleware),
+		Key:             key,
+		AutoPaginate:    options.AutoPaginate,
+		retryPolicy:     options.RetryPolicy,
+		decodeMode:      options.DecodeMode,
+		onUnknownFields: options.OnUnknownFields,
+		logger:          options.Logger,
+		rateLimiter:     options.RateLimiter,
+	}
+	if c.logger == nil {
+		c.logger = slog.New(slog.DiscardHandler)
 	}
 	c.Helix = &HelixClient{client: c}
 	c.Camera = &CameraClient{client: c}
@@ -106,25 +182,46 @@ func New(options *ClientOptions) (*Client, error) {
 	c.Access = &AccessClient{client: c}
 	c.ClassicAlarms = &ClassicAlarmsClient{client: c}
 	c.VX = &VXClient{client: c}
-	switch options.Region {
-	case "prod1":
-		c.baseURL = "https://api.verkada.com"
-	case "prod2":
-		c.baseURL = "https://api.eu.verkada.com"
-	case "au":
-		c.baseURL = "https://api.au.verkada.com"
-	default:
-		return nil, fmt.Errorf("error: invalid region/shard, must be \"prod1\", \"prod2\", or \"au\" - received %s", options.Region)
+	c.StreamingTokens = NewStreamingTokenSource(c, 0)
+	c.baseURL, c.streamingURL, err = resolveBaseURLs(options)
+	if err != nil {
+		return nil, err
 	}
-	tokenResponse, err := auth.GetAuthToken(envKey, c.baseURL)
+	if options.Cache != nil {
+		c.cache = newResponseCache(options.Cache, c.baseURL)
+	}
+	c.telemetry, err = newTelemetry(options.TracerProvider, options.MeterProvider, options.Region, c.baseURL)
 	if err != nil {
-		return c, err
-	} else {
-		c.TokenContainer = tokenResponse
+		return nil, fmt.Errorf("error creating OpenTelemetry instruments: %w", err)
+	}
+	c.Tokens = auth.NewTokenSourceWithCredentials(c.httpClient, credentials, c.baseURL, options.TokenRefreshWindow)
+	c.Tokens.Logger = c.logger
+	c.Tokens.OnRefresh = c.telemetry.recordTokenRefresh
+	if options.SkipTokenFetch {
+		return c, nil
+	}
+	if _, err := c.Tokens.Token(context.Background()); err != nil {
+		return c, fromAuthError(err)
 	}
 	return c, nil
 }
 
+// Returns the base URL used for API requests, from ClientOptions.BaseURL or the region.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Returns the base URL used for Streaming API requests, from ClientOptions.StreamingBaseURL, the region, or BaseURL.
+func (c *Client) StreamingBaseURL() string {
+	return c.streamingURL
+}
+
+// Returns the http.Client used for all requests, with any ClientOptions.Middleware applied.
+// Useful for requests to URLs returned by the API, such as footage or thumbnail links.
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
 // Helper function to one-line a bool to *bool conversion.
 // Required because a nullable value is needed to identify disincluded parameters in options structs.
 func Bool(b bool) *bool {
@@ -152,147 +249,72 @@ func Ptr[T any](v T) *T {
 
 // Used by all methods that don't require file upload or download.
 // Handles auth token refresh automatically based on the Client's API key.
+// Failed requests are retried according to the Client's RetryPolicy, with retry as the number of attempts already made.
+// params is an options struct whose name tags give the query parameters (see the package's Options structs), a pointer
+// to one, url.Values, or nil.
 //
 // Exported so custom requests can be made and can also be used in case new endpoints are not reflected in the package.
 func (c *Client) MakeVerkadaRequest(method string, url string, params any, body any, target any, retry int) error {
+	return c.MakeVerkadaRequestContext(context.Background(), method, url, params, body, target, retry)
+}
+
+// Same as MakeVerkadaRequest, with ctx controlling cancellation and deadlines of the request, any retry backoff, and any auth token refresh.
+func (c *Client) MakeVerkadaRequestContext(ctx context.Context, method string, url string, params any, body any, target any, retry int) error {
+	if ttl := c.cacheTTL(method, url); ttl > 0 {
+		return c.cachedRequest(ctx, url, params, target, ttl, retry)
+	}
 	b, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("failed to parse body params via JSON marshal: %+v", body)
 	}
-	req, _ := http.NewRequest(method, url, bytes.NewReader(b))
-	req.Header.Add("accept", "application/json")
+	header := http.Header{}
+	header.Add("accept", "application/json")
 	if body != nil {
-		req.Header.Add("content-type", "application/json")
+		header.Add("content-type", "application/json")
 	}
-	if time.Now().After(c.TokenContainer.Expires) {
-		tokenResponse, err := auth.GetAuthToken(c.Key, c.baseURL)
-		if err != nil {
-			return err
-		} else {
-			c.TokenContainer = tokenResponse
-		}
-	}
-	req.Header.Add("x-verkada-auth", c.TokenContainer.Token)
-
-	req.URL.RawQuery = assembleQueryParams(params)
-
-	res, err := c.httpClient.Do(req)
+	res, err := c.send(ctx, method, url, params, header, func() (io.Reader, int64, error) { return bytes.NewReader(b), int64(len(b)), nil }, retry)
 	if err != nil {
-		return errors.New("request error: " + err.Error() + ", request for: " + req.URL.String())
-	}
-
-	if res.StatusCode == 429 {
-		retryPeriod := 50 * math.Pow(2, float64(retry))
-		time.Sleep(time.Millisecond * 50 * time.Duration(retryPeriod))
-		c.MakeVerkadaRequest(method, url, params, body, target, retry+1)
+		return err
 	}
 
 	defer res.Body.Close()
-	var buf bytes.Buffer
-	tee := io.TeeReader(res.Body, &buf)
-	decode := json.NewDecoder(tee)
-	decode.DisallowUnknownFields()
-	err = decode.Decode(target)
-	if err != nil {
-		return errors.New(err.Error() + ", status: " + res.Status + ", response: " + buf.String())
-	}
-	return nil
+	return c.decodeResponse(method, url, res, target)
 }
 
 // Used by all methods that require file upload (typically csv or pictures).
 // Handles auth token refresh automatically based on the Client's API key.
+// Failed requests are retried according to the Client's RetryPolicy, with retry as the number of attempts already made.
 //
 // Exported so custom requests can be made and can also be used in case new endpoints are not reflected in the package.
 func (c *Client) MakeVerkadaRequestWithFile(method string, url string, params any, filename string, filetype string, target any, retry int) error {
-	boundary := "WebKitFormBoundaryPublicAPIClient"
-	var b strings.Builder
-	fmt.Fprintf(&b, "--%s\r\nContent-Disposition: form-data; name=\"file\"; filename=\"%s\"\r\nContent-Type: %s\r\n\r\n", boundary, filename, filetype)
-	file, err := os.Open(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	if filetype == "text/csv" {
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
-			fmt.Fprintf(&b, "%s\r\n", line)
-		}
-	} else {
-		buf, err := io.ReadAll(file)
-		if err != nil {
-			return fmt.Errorf("failure to read file %s into bytes buffer", filename)
-		}
-		fmt.Fprintf(&b, "%s", string(buf))
-	}
-	fmt.Fprintf(&b, "\r\n--%s--\r\n", boundary)
-	body := strings.NewReader(b.String())
-	req, _ := http.NewRequest(method, url, body)
-	req.Header.Add("accept", "application/json")
-	req.Header.Add("content-type", "multipart/form-data; boundary="+boundary)
-	if time.Now().After(c.TokenContainer.Expires) {
-		tokenResponse, err := auth.GetAuthToken(c.Key, c.baseURL)
-		if err != nil {
-			return err
-		} else {
-			c.TokenContainer = tokenResponse
-		}
-	}
-	req.Header.Add("x-verkada-auth", c.TokenContainer.Token)
-
-	req.URL.RawQuery = assembleQueryParams(params)
+	return c.MakeVerkadaRequestWithFileContext(context.Background(), method, url, params, filename, filetype, target, retry)
+}
 
-	res, err := c.httpClient.Do(req)
+// Same as MakeVerkadaRequestWithFile, with ctx controlling cancellation and deadlines of the request, any retry backoff, and any auth token refresh.
+func (c *Client) MakeVerkadaRequestWithFileContext(ctx context.Context, method string, url string, params any, filename string, filetype string, target any, retry int) error {
+	upload, file, err := openUpload(filename, filetype)
 	if err != nil {
 		return err
 	}
-
-	if res.StatusCode == 429 {
-		retryPeriod := 50 * math.Pow(2, float64(retry))
-		time.Sleep(time.Millisecond * 50 * time.Duration(retryPeriod))
-		c.MakeVerkadaRequestWithFile(method, url, params, filename, filetype, target, retry+1)
-	}
-
-	defer res.Body.Close()
-	var buf bytes.Buffer
-	tee := io.TeeReader(res.Body, &buf)
-	decode := json.NewDecoder(tee)
-	decode.DisallowUnknownFields()
-	err = decode.Decode(target)
-	if err != nil {
-		return errors.New(err.Error() + ", status: " + res.Status + ", response: " + buf.String())
-	}
-	return nil
+	defer file.Close()
+	return c.MakeVerkadaRequestWithReaderContext(ctx, method, url, params, upload, target, retry)
 }
 
 // Used by all methods that require file download (typically csv or pictures).
+// The file is only created once a 2xx response that is not JSON has been received, and an error is returned otherwise.
 // Handles auth token refresh automatically based on the Client's API key.
+// Failed requests are retried according to the Client's RetryPolicy, with retry as the number of attempts already made.
 //
 // Exported so custom requests can be made and can also be used in case new endpoints are not reflected in the package.
 func (c *Client) MakeVerkadaRequestForFile(method string, url string, params any, filename string, retry int) error {
-	req, _ := http.NewRequest(method, url, nil)
-	if time.Now().After(c.TokenContainer.Expires) {
-		tokenResponse, err := auth.GetAuthToken(c.Key, c.baseURL)
-		if err != nil {
-			return err
-		} else {
-			c.TokenContainer = tokenResponse
-		}
-	}
-	req.Header.Add("x-verkada-auth", c.TokenContainer.Token)
-
-	req.URL.RawQuery = assembleQueryParams(params)
-	fmt.Println(req.URL.RawQuery)
+	return c.MakeVerkadaRequestForFileContext(context.Background(), method, url, params, filename, retry)
+}
 
-	res, err := c.httpClient.Do(req)
+// Same as MakeVerkadaRequestForFile, with ctx controlling cancellation and deadlines of the request, any retry backoff, and any auth token refresh.
+func (c *Client) MakeVerkadaRequestForFileContext(ctx context.Context, method string, url string, params any, filename string, retry int) error {
+	res, err := c.sendDownload(ctx, method, url, params, nil, retry)
 	if err != nil {
-		return errors.New("request error: " + err.Error() + ", request for: " + req.URL.String())
-	}
-
-	if res.StatusCode == 429 {
-		retryPeriod := 50 * math.Pow(2, float64(retry))
-		time.Sleep(time.Millisecond * 50 * time.Duration(retryPeriod))
-		c.MakeVerkadaRequestForFile(method, url, params, filename, retry+1)
+		return err
 	}
 
 	defer res.Body.Close()
@@ -303,62 +325,179 @@ func (c *Client) MakeVerkadaRequestForFile(method string, url string, params any
 	defer file.Close()
 	written, err1 := io.Copy(file, res.Body)
 	if err1 != nil {
-		return fmt.Errorf("error writing to file from request for %s, %d bytes written", req.URL.String(), written)
+		return fmt.Errorf("error writing to file from request for %s, %d bytes written", redactURL(res.Request.URL.String()), written)
 	}
 	return nil
 }
 
-// Take any options struct and assemble query parameter string.
-// Nil/zero values are treated differently per type.
+// Shared by all MakeVerkadaRequest variants to send a request and apply the Client's RetryPolicy.
+// newBody is called once per attempt so that the request body can be replayed, returning the body and its length (-1 if unknown);
+// nil means no body.
+// retry is the number of attempts already made by the caller.
 //
-// Strings: Included if not empty
+// The returned response is that of the final attempt and its body must be closed by the caller.
 //
-// *Bool, *Int64, *Float64: Included if not nil (includes zero and non-zero)
-//
-// Slices and Arrays: Comma-delimited list of values if not empty
-//
-// Structs: assembleQueryParams is called recursively and added to the overall result
-func assembleQueryParams(params any) string {
-	if params == nil {
-		return ""
+// The outcome is recorded on the span of the calling method, or on a new "Client.MakeVerkadaRequest" span for custom requests.
+func (c *Client) send(ctx context.Context, method string, url string, params any, header http.Header, newBody func() (io.Reader, int64, error), retry int) (*http.Response, error) {
+	if operationFromContext(ctx) == nil {
+		var span trace.Span
+		ctx, span = c.startSpan(ctx, "Client.MakeVerkadaRequest")
+		defer span.End()
+	}
+	res, retries, err := c.sendAttempts(ctx, method, url, params, header, newBody, retry)
+	if c.cache != nil && method != http.MethodGet && method != http.MethodHead {
+		// even a failed write may have changed something, so its family is invalidated regardless
+		c.invalidateCacheFamily(ctx, endpointFamily(url))
 	}
-	typ := reflect.TypeOf(params)
-	if typ.Kind() != reflect.Struct {
-		fmt.Printf("Params (%+v type %s %v) is not a struct and cannot be parsed", params, typ.Kind().String(), params == nil)
-		return ""
+	status := 0
+	var apiErr *APIError
+	if res != nil {
+		status = res.StatusCode
+	} else if errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	endRequestSpan(trace.SpanFromContext(ctx), endpointOf(url), status, retries, err)
+	return res, err
+}
+
+// Makes the attempts of a request for send, also returning the number of retries made.
+func (c *Client) sendAttempts(ctx context.Context, method string, url string, params any, header http.Header, newBody func() (io.Reader, int64, error), retry int) (*http.Response, int, error) {
+	op := operationFromContext(ctx)
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	maxAttempts := max(policy.MaxAttempts, 1)
+	values, err := encodeQuery(params)
+	if err != nil {
+		return nil, 0, validationErrorf("cannot encode query params: %v", err)
+	}
+	query := values.Encode()
+	family := endpointFamily(url)
+	page := pageFromContext(ctx)
+	logAttrs := func(attempt int, attrs ...any) []any {
+		attrs = append([]any{"method", method, "endpoint", endpointOf(url), "retries", attempt - retry - 1}, attrs...)
+		if page > 0 {
+			attrs = append(attrs, "page", page)
+		}
+		return attrs
 	}
-	var b strings.Builder
-	val := reflect.ValueOf(params)
-	for i := 0; i < typ.NumField(); i++ {
-		fld := typ.Field(i)
-		if fld.Type.Kind() == reflect.String {
-			if val.Field(i).String() != "" {
-				fmt.Fprintf(&b, "%s=%s&", fld.Tag.Get("name"), val.Field(i).String())
+	// a 401 forces one token refresh and an immediate retry, without counting as an attempt;
+	// rejected holds its error in case the body cannot be sent again
+	var rejected *APIError
+	for attempt := retry + 1; ; attempt++ {
+		var body io.Reader
+		length := int64(0)
+		if newBody != nil {
+			var err error
+			body, length, err = newBody()
+			if errors.Is(err, errBodyNotReplayable) && rejected != nil {
+				c.logger.WarnContext(ctx, "verkada request failed", logAttrs(attempt, "status", rejected.StatusCode, "request_id", rejected.RequestID, "error", rejected.Message)...)
+				return nil, attempt - retry - 1, rejected
 			}
-		} else if !val.Field(i).IsNil() {
-			if val.Field(i).Kind() == reflect.Struct {
-				fmt.Fprintf(&b, "%s&", assembleQueryParams(val.Field(i).Elem()))
-			} else if val.Field(i).Kind() == reflect.Array || val.Field(i).Kind() == reflect.Slice {
-				fmt.Fprintf(&b, "%s=", fld.Tag.Get("name"))
-				for j := 0; j < val.Field(i).Len(); j++ {
-					fmt.Fprintf(&b, "%v", val.Field(i).Index(j))
-					if j != val.Field(i).Len()-1 {
-						fmt.Fprintf(&b, ",")
-					} else {
-						fmt.Fprintf(&b, "&")
-					}
-				}
-			} else {
-				switch val.Field(i).Elem().Kind() {
-				case reflect.Bool:
-					fmt.Fprintf(&b, "%s=%t&", fld.Tag.Get("name"), val.Field(i).Elem().Bool())
-				case reflect.Int64:
-					fmt.Fprintf(&b, "%s=%d&", fld.Tag.Get("name"), val.Field(i).Elem().Int())
-				case reflect.Float64:
-					fmt.Fprintf(&b, "%s=%f&", fld.Tag.Get("name"), val.Field(i).Elem().Float())
+			if err != nil {
+				return nil, attempt - retry - 1, err
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			return nil, attempt - retry - 1, err
+		}
+		if length >= 0 && req.ContentLength == 0 {
+			req.ContentLength = length
+		}
+		req.Header = header.Clone()
+		token, err := c.Tokens.Token(ctx)
+		if err != nil {
+			return nil, attempt - retry - 1, fromAuthError(err)
+		}
+		req.Header.Set("x-verkada-auth", token)
+		req.URL.RawQuery = query
+		if c.rateLimiter != nil {
+			waited, err := c.rateLimiter.wait(ctx, family)
+			if err != nil {
+				return nil, attempt - retry - 1, err
+			}
+			if waited > 0 {
+				c.logger.DebugContext(ctx, "verkada request delayed by rate limiter", logAttrs(attempt, "family", family, "delay", waited)...)
+			}
+		}
+
+		start := time.Now()
+		res, err := c.httpClient.Do(req)
+		latency := time.Since(start)
+		status := 0
+		if err == nil {
+			status = res.StatusCode
+		} else {
+			// the *url.Error carries the full URL, which is logged and returned below
+			err = redactURLError(err)
+		}
+		op.telemetry.recordAttempt(ctx, op.name, method, status, latency)
+		if err == nil && res.StatusCode == http.StatusUnauthorized && rejected == nil {
+			c.logger.InfoContext(ctx, "verkada auth token rejected, refreshing", logAttrs(attempt, "latency", latency)...)
+			body, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			rejected = newAPIError(method, redactURL(req.URL.String()), res, body)
+			if _, err := c.Tokens.Refresh(ctx, token); err != nil {
+				return nil, attempt - retry - 1, fromAuthError(err)
+			}
+			attempt--
+			continue
+		}
+		if attempt >= maxAttempts || !policy.shouldRetry(method, res, err) {
+			if err != nil {
+				c.logger.ErrorContext(ctx, "verkada request failed", logAttrs(attempt, "latency", latency, "error", err)...)
+				return nil, attempt - retry - 1, fmt.Errorf("request error: %w, request for: %s", err, redactURL(req.URL.String()))
+			}
+			if res.StatusCode < 200 || res.StatusCode > 299 {
+				defer res.Body.Close()
+				body, _ := io.ReadAll(res.Body)
+				apiErr := newAPIError(method, redactURL(req.URL.String()), res, body)
+				level := slog.LevelWarn
+				if res.StatusCode >= 500 {
+					level = slog.LevelError
 				}
+				c.logger.Log(ctx, level, "verkada request failed", logAttrs(attempt, "status", res.StatusCode, "latency", latency, "request_id", apiErr.RequestID, "error", apiErr.Message)...)
+				return nil, attempt - retry - 1, apiErr
 			}
+			c.logger.DebugContext(ctx, "verkada request", logAttrs(attempt, "status", res.StatusCode, "latency", latency, "url", redactedURL(req.URL.String()), "header", redactedHeader(req.Header))...)
+			return res, attempt - retry - 1, nil
 		}
+		delay := policy.delay(attempt-1, res)
+		event := RetryEvent{Method: method, URL: redactURL(req.URL.String()), Attempt: attempt, Err: err, Delay: delay}
+		if res != nil {
+			if res.StatusCode == http.StatusTooManyRequests && c.rateLimiter != nil {
+				c.rateLimiter.block(family, delay)
+			}
+			event.StatusCode = res.StatusCode
+			// drain so the connection can be reused by the next attempt
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		if err != nil {
+			c.logger.WarnContext(ctx, "retrying verkada request", logAttrs(attempt, "latency", latency, "delay", delay, "error", err)...)
+		} else {
+			c.logger.WarnContext(ctx, "retrying verkada request", logAttrs(attempt, "status", res.StatusCode, "latency", latency, "delay", delay)...)
+		}
+		op.telemetry.recordRetry(ctx, op.name, method)
+		if policy.OnRetry != nil {
+			policy.OnRetry(event)
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, attempt - retry, err
+		}
+	}
+}
+
+// Sleeps for the given duration, returning early with the context's error if ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
 	}
-	return strings.TrimSuffix(b.String(), "&")
 }
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Starts a server answering /token with numbered tokens ("token-1", "token-2", ...) and every other request with h,
+// and returns a Client pointed at it. options may be nil; its BaseURL and APIKey are set.
+func newTestClient(t *testing.T, h http.HandlerFunc, options *ClientOptions) (*Client, *httptest.Server) {
+	t.Helper()
+	var tokens atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			json.NewEncoder(w).Encode(map[string]string{"token": fmt.Sprintf("token-%d", tokens.Add(1))})
+			return
+		}
+		h(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	if options == nil {
+		options = &ClientOptions{}
+	}
+	options.BaseURL = srv.URL
+	options.APIKey = "test-key"
+	options.SkipTokenFetch = true
+	if options.RetryPolicy == nil {
+		options.RetryPolicy = &RetryPolicy{MaxAttempts: 1}
+	}
+	c, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, srv
+}
+
+func TestRequestErrorsRedactJWT(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		closed bool
+	}{
+		{"api error", http.StatusNotFound, false},
+		{"server error", http.StatusInternalServerError, false},
+		{"network error", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"message": "nope"}`, tt.status)
+			}, nil)
+			if tt.closed {
+				if _, err := c.Tokens.Token(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+				srv.Listener.Close()
+			}
+			params := struct {
+				Camera string `name:"camera_id"`
+				Jwt    string `name:"jwt"`
+			}{"cam-1", "secret-jwt"}
+			err := c.MakeVerkadaRequestContext(context.Background(), "GET", srv.URL+"/stream/cameras/v1/footage/stream/stream.m3u8", params, nil, nil, 0)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if strings.Contains(err.Error(), "secret-jwt") {
+				t.Errorf("error contains the JWT: %v", err)
+			}
+			if !strings.Contains(err.Error(), "camera_id=cam-1") {
+				t.Errorf("error lost the other query params: %v", err)
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && (apiErr.StatusCode != tt.status || strings.Contains(apiErr.URL, "secret-jwt")) {
+				t.Errorf("got APIError %d %s", apiErr.StatusCode, apiErr.URL)
+			}
+		})
+	}
+}
+
+func TestUnauthorizedForcesReauth(t *testing.T) {
+	tests := []struct {
+		name string
+		// tokens the server accepts
+		accept       func(token string) bool
+		wantErr      error
+		wantTokens   int32
+		wantRequests int32
+	}{
+		{"valid token", func(token string) bool { return true }, nil, 1, 1},
+		{"rejected token is replaced once", func(token string) bool { return token != "token-1" }, nil, 2, 2},
+		{"still rejected after refresh", func(token string) bool { return false }, ErrUnauthorized, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if !tt.accept(r.Header.Get("x-verkada-auth")) {
+					http.Error(w, `{"message": "invalid token"}`, http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}, nil)
+			err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", got, tt.wantRequests)
+			}
+			token, _ := c.Tokens.Token(context.Background())
+			if want := fmt.Sprintf("token-%d", tt.wantTokens); token != want {
+				t.Errorf("token is %s, want %s", token, want)
+			}
+		})
+	}
+}
+
+func TestUnauthorizedConcurrentReauth(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var reauthed sync.WaitGroup
+	reauthed.Add(20)
+	c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-verkada-auth") == "token-1" {
+			// hold every caller's first attempt until all have been rejected together
+			reauthed.Done()
+			reauthed.Wait()
+			http.Error(w, `{"message": "invalid token"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}, &ClientOptions{Middleware: []Middleware{func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/token" {
+				tokenRequests.Add(1)
+			}
+			return next.RoundTrip(req)
+		})
+	}}})
+	if _, err := c.Tokens.Token(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := tokenRequests.Load(); got != 2 {
+		t.Errorf("made %d token requests, want 2 (one refresh for all callers)", got)
+	}
+}
+
+func TestNewMalformedDotenv(t *testing.T) {
+	// a malformed optional .env does not stop a Client given its key explicitly
+	t.Setenv("API_KEY", "")
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("API_KEY=dotenv-key\nnot an entry\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(&ClientOptions{BaseURL: "http://127.0.0.1:1", APIKey: "explicit-key", SkipTokenFetch: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Key != "explicit-key" {
+		t.Errorf("got key %q", c.Key)
+	}
+	if _, err := New(&ClientOptions{BaseURL: "http://127.0.0.1:1", SkipTokenFetch: true}); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("without an explicit key got %v, want the parse error", err)
+	}
+}
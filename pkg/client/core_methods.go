@@ -1,6 +1,6 @@
 package client
 
-import "fmt"
+import "context"
 
 // Returns audit logs for an organization within a specified time range.
 //
@@ -8,50 +8,89 @@ import "fmt"
 //
 // [Verkada API Docs - Verkada API Docs - Get Audit Logs]: https://apidocs.verkada.com/reference/getauditlogsviewv1
 func (c *CoreClient) GetAuditLogs(options *GetAuditLogsOptions) (*GetAuditLogsResponse, error) {
+	return c.GetAuditLogsContext(context.Background(), options)
+}
+
+// Same as GetAuditLogs, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CoreClient) GetAuditLogsContext(ctx context.Context, options *GetAuditLogsOptions) (*GetAuditLogsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Core.GetAuditLogs")
+	defer span.End()
 	if options == nil {
 		options = &GetAuditLogsOptions{}
 	}
-	// page_size must be between 1 and 200
-	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	if err := validateGetAuditLogsOptions(options); err != nil {
+		return nil, err
 	}
 	var ret GetAuditLogsResponse
 	url := c.client.baseURL + "/core/v1/audit_log"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetAuditLogsResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Audit_logs = append(ret.Audit_logs, nextRet.Audit_logs...)
-			ret.Next_page_token = nextRet.Next_page_token
-		}
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.auditLogsPages(*options), &ret.Audit_logs)
 	}
 	return &ret, err
 }
 
+// Same as GetAuditLogs, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *CoreClient) GetAuditLogsIter(ctx context.Context, options *GetAuditLogsOptions) *Paginator[AuditLog] {
+	ctx = c.client.withOperation(ctx, "Core.GetAuditLogs")
+	if options == nil {
+		options = &GetAuditLogsOptions{}
+	}
+	if err := validateGetAuditLogsOptions(options); err != nil {
+		return errPaginator[AuditLog](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.auditLogsPages(*options))
+}
+
+// Returns the page function shared by GetAuditLogsContext and GetAuditLogsIter.
+func (c *CoreClient) auditLogsPages(options GetAuditLogsOptions) pageFunc[AuditLog] {
+	url := c.client.baseURL + "/core/v1/audit_log"
+	return func(ctx context.Context, token string) ([]AuditLog, string, error) {
+		options.Page_token = token
+		var ret GetAuditLogsResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Audit_logs, ret.Next_page_token, nil
+	}
+}
+
+func validateGetAuditLogsOptions(options *GetAuditLogsOptions) error {
+	// page_size must be between 1 and 200
+	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	}
+	return nil
+}
+
 // Deletes a user for an organization based on either provided user ID or an external ID set during creation.
 //
 // [Verkada API Docs - Verkada API Docs - Delete User]
 //
 // [Verkada API Docs - Verkada API Docs - Delete User]: https://apidocs.verkada.com/reference/deleteuserviewv1
 func (c *CoreClient) DeleteUser(options *DeleteUserOptions) (*DeleteUserResponse, error) {
+	return c.DeleteUserContext(context.Background(), options)
+}
+
+// Same as DeleteUser, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CoreClient) DeleteUserContext(ctx context.Context, options *DeleteUserOptions) (*DeleteUserResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Core.DeleteUser")
+	defer span.End()
 	if options == nil {
 		options = &DeleteUserOptions{}
 	}
 	// should not use both user_id and external_id, but need at least one
 	if (options.User_id == "") == (options.External_id == "") {
-		return nil, fmt.Errorf("should use one of user_id and external_id - received user_id: %s and external_id: %s", options.User_id, options.External_id)
+		return nil, validationErrorf("should use one of user_id and external_id - received user_id: %s and external_id: %s", options.User_id, options.External_id)
 	}
 	var ret DeleteUserResponse
 	url := c.client.baseURL + "/core/v1/user"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -61,16 +100,23 @@ func (c *CoreClient) DeleteUser(options *DeleteUserOptions) (*DeleteUserResponse
 //
 // [Verkada API Docs - Get User]: https://apidocs.verkada.com/reference/getuserviewv1
 func (c *CoreClient) GetUser(options *GetUserOptions) (*GetUserResponse, error) {
+	return c.GetUserContext(context.Background(), options)
+}
+
+// Same as GetUser, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CoreClient) GetUserContext(ctx context.Context, options *GetUserOptions) (*GetUserResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Core.GetUser")
+	defer span.End()
 	if options == nil {
 		options = &GetUserOptions{}
 	}
 	// should not use both user_id and external_id, but need at least one
 	if (options.User_id == "") == (options.External_id == "") {
-		return nil, fmt.Errorf("should only use one of user_id and external_id - received user_id: %s and external_id: %s", options.User_id, options.External_id)
+		return nil, validationErrorf("should only use one of user_id and external_id - received user_id: %s and external_id: %s", options.User_id, options.External_id)
 	}
 	var ret GetUserResponse
 	url := c.client.baseURL + "/core/v1/user"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -86,9 +132,16 @@ func (c *CoreClient) GetUser(options *GetUserOptions) (*GetUserResponse, error)
 //
 // [Verkada API Docs - Create User]: https://apidocs.verkada.com/reference/postuserviewv1
 func (c *CoreClient) CreateUser(body *CreateUserBody) (*CreateUserResponse, error) {
+	return c.CreateUserContext(context.Background(), body)
+}
+
+// Same as CreateUser, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CoreClient) CreateUserContext(ctx context.Context, body *CreateUserBody) (*CreateUserResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Core.CreateUser")
+	defer span.End()
 	var ret CreateUserResponse
 	url := c.client.baseURL + "/core/v1/user"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -98,15 +151,22 @@ func (c *CoreClient) CreateUser(body *CreateUserBody) (*CreateUserResponse, erro
 //
 // [Verkada API Docs - Update User]: https://apidocs.verkada.com/reference/putuserviewv1
 func (c *CoreClient) UpdateUser(options *UpdateUserOptions, body *UpdateUserBody) (*UpdateUserResponse, error) {
+	return c.UpdateUserContext(context.Background(), options, body)
+}
+
+// Same as UpdateUser, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CoreClient) UpdateUserContext(ctx context.Context, options *UpdateUserOptions, body *UpdateUserBody) (*UpdateUserResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Core.UpdateUser")
+	defer span.End()
 	if options == nil {
 		options = &UpdateUserOptions{}
 	}
 	// should not use both user_id and external_id, but need at least one
 	if (options.User_id == "") == (options.External_id == "") {
-		return nil, fmt.Errorf("should only use one of user_id and external_id - received user_id: %s and external_id: %s", options.User_id, options.External_id)
+		return nil, validationErrorf("should only use one of user_id and external_id - received user_id: %s and external_id: %s", options.User_id, options.External_id)
 	}
 	var ret UpdateUserResponse
 	url := c.client.baseURL + "/core/v1/user"
-	err := c.client.MakeVerkadaRequest("PUT", url, *options, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PUT", url, *options, body, &ret, 0)
 	return &ret, err
 }
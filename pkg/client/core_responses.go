@@ -1,21 +1,23 @@
 package client
 
 type GetAuditLogsResponse struct {
-	Audit_logs []struct {
-		Details             any               `json:"details"`
-		Devices             []AuditLogsDevice `json:"devices"`
-		Event_description   string            `json:"event_description"`
-		Event_name          string            `json:"event_name"`
-		Ip_address          string            `json:"ip_address"`
-		Organization_id     string            `json:"organization_id"`
-		Processed_timestamp string            `json:"processed_timestamp"`
-		Timestamp           string            `json:"timestamp"`
-		User_email          string            `json:"user_email"`
-		User_id             string            `json:"user_id"`
-		User_name           string            `json:"user_name"`
-		Verkada_support_id  string            `json:"verkada_support_id"`
-	} `json:"audit_logs"`
-	Next_page_token string `json:"next_page_token"`
+	Audit_logs      []AuditLog `json:"audit_logs"`
+	Next_page_token string     `json:"next_page_token"`
+}
+
+type AuditLog struct {
+	Details             any               `json:"details"`
+	Devices             []AuditLogsDevice `json:"devices"`
+	Event_description   string            `json:"event_description"`
+	Event_name          string            `json:"event_name"`
+	Ip_address          string            `json:"ip_address"`
+	Organization_id     string            `json:"organization_id"`
+	Processed_timestamp string            `json:"processed_timestamp"`
+	Timestamp           string            `json:"timestamp"`
+	User_email          string            `json:"user_email"`
+	User_id             string            `json:"user_id"`
+	User_name           string            `json:"user_name"`
+	Verkada_support_id  string            `json:"verkada_support_id"`
 }
 
 type AuditLogsDevice struct {
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A DecodeMode controls how response bodies are decoded into the package's response structs.
+type DecodeMode int
+
+const (
+	// Fail any response containing a field missing from its response struct. This is the default.
+	DecodeStrict DecodeMode = iota
+	// Ignore fields missing from the response structs, so that new fields added by Verkada do not break requests.
+	DecodeLenient
+	// Same as DecodeLenient, additionally collecting the paths of unknown fields per endpoint.
+	// They are passed to ClientOptions.OnUnknownFields and returned by Client.UnknownFields.
+	DecodeLenientReport
+)
+
+// Unknown fields found in responses from one endpoint, passed to ClientOptions.OnUnknownFields.
+type UnknownFieldsReport struct {
+	// HTTP method and URL path (without query) of the request, e.g. "GET" and "/cameras/v1/devices".
+	Method string
+	Path   string
+	// JSON paths of the unknown fields not previously reported for this endpoint, e.g. "cameras[].new_field".
+	Fields []string
+}
+
+// Unknown field paths seen per endpoint in DecodeLenientReport mode.
+type unknownFieldTracker struct {
+	mu     sync.Mutex
+	fields map[string]map[string]bool
+}
+
+// Returns the unknown field paths seen so far in DecodeLenientReport mode, keyed by "METHOD /path".
+// Returns nil in other modes.
+func (c *Client) UnknownFields() map[string][]string {
+	c.unknown.mu.Lock()
+	defer c.unknown.mu.Unlock()
+	if c.unknown.fields == nil {
+		return nil
+	}
+	ret := make(map[string][]string, len(c.unknown.fields))
+	for endpoint, fields := range c.unknown.fields {
+		for field := range fields {
+			ret[endpoint] = append(ret[endpoint], field)
+		}
+		slices.Sort(ret[endpoint])
+	}
+	return ret
+}
+
+// Decodes a JSON response body into target according to the Client's DecodeMode.
+func (c *Client) decodeResponse(method string, url string, res *http.Response, target any) error {
+	var buf bytes.Buffer
+	tee := io.TeeReader(res.Body, &buf)
+	decode := json.NewDecoder(tee)
+	if c.decodeMode == DecodeStrict {
+		decode.DisallowUnknownFields()
+	}
+	err := decode.Decode(target)
+	if err != nil {
+		return fmt.Errorf("%w, status: %s, response: %s", err, res.Status, buf.String())
+	}
+	if c.decodeMode == DecodeLenientReport {
+		var generic any
+		if json.Unmarshal(buf.Bytes(), &generic) == nil {
+			var fields []string
+			collectUnknownFields(generic, reflect.TypeOf(target), "", &fields)
+			if len(fields) > 0 {
+				c.reportUnknownFields(method, url, fields)
+			}
+		}
+	}
+	return nil
+}
+
+// Records fields for the endpoint and passes those not seen before to OnUnknownFields.
+func (c *Client) reportUnknownFields(method string, url string, fields []string) {
+	path := url
+	if u, err := neturl.Parse(url); err == nil {
+		path = u.Path
+	}
+	endpoint := method + " " + path
+	c.unknown.mu.Lock()
+	if c.unknown.fields == nil {
+		c.unknown.fields = map[string]map[string]bool{}
+	}
+	seen := c.unknown.fields[endpoint]
+	if seen == nil {
+		seen = map[string]bool{}
+		c.unknown.fields[endpoint] = seen
+	}
+	var fresh []string
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			fresh = append(fresh, f)
+		}
+	}
+	c.unknown.mu.Unlock()
+	if len(fresh) > 0 && c.onUnknownFields != nil {
+		slices.Sort(fresh)
+		c.onUnknownFields(UnknownFieldsReport{Method: method, Path: path, Fields: fresh})
+	}
+}
+
+// Walks a generically decoded JSON value alongside the Go type it was decoded into, appending the path of every
+// object key that does not correspond to a field. Slices are written as "name[]" and map values as "name.*".
+func collectUnknownFields(v any, t reflect.Type, path string, out *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			for key, child := range val {
+				fieldType, ok := jsonField(t, key)
+				if !ok {
+					*out = append(*out, joinPath(path, key))
+					continue
+				}
+				collectUnknownFields(child, fieldType, joinPath(path, key), out)
+			}
+		case reflect.Map:
+			for _, child := range val {
+				collectUnknownFields(child, t.Elem(), joinPath(path, "*"), out)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, child := range val {
+				collectUnknownFields(child, t.Elem(), path+"[]", out)
+			}
+		}
+	}
+	// scalars, and any values decoded into interfaces, cannot have unknown fields
+	if path == "" {
+		slices.Sort(*out)
+		*out = slices.Compact(*out)
+	}
+}
+
+// Returns the type of the struct field that encoding/json would decode key into, following embedded structs.
+func jsonField(t reflect.Type, key string) (reflect.Type, bool) {
+	var fold reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			et := f.Type
+			if et.Kind() == reflect.Pointer {
+				et = et.Elem()
+			}
+			if et.Kind() == reflect.Struct {
+				if ft, ok := jsonField(et, key); ok {
+					return ft, true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == key {
+			return f.Type, true
+		}
+		// encoding/json matches keys case-insensitively when there is no exact match
+		if fold == nil && strings.EqualFold(name, key) {
+			fold = f.Type
+		}
+	}
+	return fold, fold != nil
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
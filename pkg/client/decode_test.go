@@ -0,0 +1,85 @@
+package client
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Response of GetOTCameras with fields missing from GetOTCamerasResponse at each level.
+const unknownFieldsBody = `{
+	"cameras": [
+		{"camera_id": "cam-1", "preset_ids": ["p-1"], "model": "CD52",
+			"presets": [{"object_class": "person", "preset_id": "p-1", "zone": {"x": 1, "y": 2}}]},
+		{"Camera_ID": "cam-2", "presets": [], "site": {"name": "HQ", "tags": [{"id": 1}]}}
+	],
+	"next_page_token": null
+}`
+
+func TestDecodeModes(t *testing.T) {
+	wantFields := []string{"cameras[].model", "cameras[].presets[].zone", "cameras[].site", "next_page_token"}
+	tests := []struct {
+		name       string
+		mode       DecodeMode
+		wantErr    bool
+		wantFields []string
+	}{
+		{"strict", DecodeStrict, true, nil},
+		{"lenient", DecodeLenient, false, nil},
+		{"lenient report", DecodeLenientReport, false, wantFields},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := unknownFieldsBody
+			var reports []UnknownFieldsReport
+			c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(body))
+			}, &ClientOptions{DecodeMode: tt.mode, OnUnknownFields: func(r UnknownFieldsReport) {
+				reports = append(reports, r)
+			}})
+			res, err := c.Camera.GetOTCameras()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "unknown field") {
+					t.Fatalf("got %v, want an unknown field error", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(res.Cameras) != 2 || res.Cameras[1].Camera_id != "cam-2" || res.Cameras[0].Presets[0].Object_class != "person" {
+					t.Errorf("decoded %+v", res)
+				}
+			}
+			got := c.UnknownFields()
+			if tt.wantFields == nil {
+				if got != nil || reports != nil {
+					t.Errorf("got unknown fields %v and reports %v, want none", got, reports)
+				}
+				return
+			}
+			const endpoint = "GET /cameras/v1/occupancy_trend_enabled"
+			if len(got) != 1 || !slices.Equal(got[endpoint], tt.wantFields) {
+				t.Errorf("got unknown fields %v, want %s: %v", got, endpoint, tt.wantFields)
+			}
+			if len(reports) != 1 || reports[0].Method != "GET" || reports[0].Path != "/cameras/v1/occupancy_trend_enabled" || !slices.Equal(reports[0].Fields, tt.wantFields) {
+				t.Errorf("got reports %+v", reports)
+			}
+
+			// later responses report only fields not seen before, while UnknownFields keeps them all
+			body = strings.Replace(unknownFieldsBody, `"model"`, `"firmware": "1.0", "model"`, 1)
+			if _, err := c.Camera.GetOTCameras(); err != nil {
+				t.Fatal(err)
+			}
+			if len(reports) != 2 || !slices.Equal(reports[1].Fields, []string{"cameras[].firmware"}) {
+				t.Errorf("got reports %+v, want only the new field", reports)
+			}
+			if got := c.UnknownFields()[endpoint]; !slices.Equal(got, append([]string{"cameras[].firmware"}, tt.wantFields...)) {
+				t.Errorf("got unknown fields %v", got)
+			}
+			if _, err := c.Camera.GetOTCameras(); err != nil || len(reports) != 2 {
+				t.Errorf("got %v and reports %+v, want no new report", err, reports)
+			}
+		})
+	}
+}
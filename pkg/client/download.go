@@ -0,0 +1,212 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+// Content types accepted by the image download methods.
+var imageContentTypes = []string{"image/*"}
+
+// Content types accepted for HLS playlists.
+var playlistContentTypes = []string{"application/vnd.apple.mpegurl", "application/x-mpegurl", "audio/mpegurl", "audio/x-mpegurl", "application/octet-stream"}
+
+// A file downloaded into memory by the Bytes methods.
+type Download struct {
+	// Content type of the response, e.g. "image/jpeg".
+	ContentType string
+	Data        []byte
+}
+
+// Used by methods that stream a downloaded file (typically pictures or playlists) into an io.Writer instead of a file on disk.
+// Nothing is written unless the response is 2xx and its content type matches one of accept (e.g. "image/jpeg" or "image/*");
+// otherwise an *APIError or *ContentTypeError is returned. A nil accept allows any content type except JSON.
+// Returns the content type of the response and the number of bytes written.
+// Handles auth token refresh automatically based on the Client's API key.
+// Failed requests are retried according to the Client's RetryPolicy, with retry as the number of attempts already made.
+//
+// Exported so custom requests can be made and can also be used in case new endpoints are not reflected in the package.
+func (c *Client) MakeVerkadaRequestToWriter(method string, url string, params any, w io.Writer, accept []string, retry int) (string, int64, error) {
+	return c.MakeVerkadaRequestToWriterContext(context.Background(), method, url, params, w, accept, retry)
+}
+
+// Same as MakeVerkadaRequestToWriter, with ctx controlling cancellation and deadlines of the request, any retry backoff, and any auth token refresh.
+func (c *Client) MakeVerkadaRequestToWriterContext(ctx context.Context, method string, url string, params any, w io.Writer, accept []string, retry int) (string, int64, error) {
+	res, err := c.sendDownload(ctx, method, url, params, accept, retry)
+	if err != nil {
+		return "", 0, err
+	}
+	defer res.Body.Close()
+	written, err := io.Copy(w, res.Body)
+	return res.Header.Get("Content-Type"), written, err
+}
+
+// Downloads into memory with MakeVerkadaRequestToWriterContext.
+func (c *Client) downloadBytes(ctx context.Context, url string, params any, accept []string) (*Download, error) {
+	var buf bytes.Buffer
+	contentType, _, err := c.MakeVerkadaRequestToWriterContext(ctx, "GET", url, params, &buf, accept, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &Download{ContentType: contentType, Data: buf.Bytes()}, nil
+}
+
+// Downloads a URL returned by the API, such as a thumbnail link, into memory with sendLink.
+func (c *Client) downloadLink(ctx context.Context, link string, accept []string) (*Download, error) {
+	res, err := c.sendLink(ctx, link, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", withoutQuery(res.Request.URL), err)
+	}
+	return &Download{ContentType: res.Header.Get("Content-Type"), Data: data}, nil
+}
+
+// Downloads a URL returned by the API, such as a thumbnail link or a segment of a playlist on a CDN, into w.
+// The request is sent without the auth token or any other credential, since such links are signed and may point to
+// another host, and is retried according to the Client's RetryPolicy. The query (the signature) is left out of any
+// error. Otherwise the same as MakeVerkadaRequestToWriter.
+func (c *Client) MakeLinkRequestToWriter(link string, w io.Writer, accept []string) (string, int64, error) {
+	return c.MakeLinkRequestToWriterContext(context.Background(), link, w, accept)
+}
+
+// Same as MakeLinkRequestToWriter, with ctx controlling cancellation and deadlines of the request and any retry backoff.
+func (c *Client) MakeLinkRequestToWriterContext(ctx context.Context, link string, w io.Writer, accept []string) (string, int64, error) {
+	res, err := c.sendLink(ctx, link, accept)
+	if err != nil {
+		return "", 0, err
+	}
+	defer res.Body.Close()
+	written, err := io.Copy(w, res.Body)
+	if err != nil {
+		err = fmt.Errorf("GET %s: %w", withoutQuery(res.Request.URL), err)
+	}
+	return res.Header.Get("Content-Type"), written, err
+}
+
+// Sends a GET request for a URL returned by the API, such as a thumbnail link or a segment of a playlist on another
+// host, and returns the response once its status and content type have been checked. Unlike API requests, it is sent
+// as is, without the auth token, since such links are signed and may point to another host; failures are retried
+// according to the Client's RetryPolicy, without the rate limiter, which only covers the API's quotas.
+// The query (the signature) is left out of any error, including an *APIError or *ContentTypeError.
+// The response body must be closed by the caller.
+func (c *Client) sendLink(ctx context.Context, link string, accept []string) (*http.Response, error) {
+	policy := c.retryPolicy
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	maxAttempts := max(policy.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			return nil, err
+		}
+		redacted := withoutQuery(req.URL)
+		start := time.Now()
+		res, err := c.httpClient.Do(req)
+		latency := time.Since(start)
+		if err != nil {
+			// the *url.Error would include the signature
+			var urlErr *neturl.Error
+			if errors.As(err, &urlErr) {
+				err = urlErr.Err
+			}
+			err = fmt.Errorf("GET %s: %w", redacted, err)
+		}
+		if attempt < maxAttempts && policy.shouldRetry(http.MethodGet, res, err) {
+			delay := policy.delay(attempt-1, res)
+			event := RetryEvent{Method: http.MethodGet, URL: redacted, Attempt: attempt, Err: err, Delay: delay}
+			if res != nil {
+				event.StatusCode = res.StatusCode
+				io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+				c.logger.WarnContext(ctx, "retrying verkada link request", "url", redacted, "status", res.StatusCode, "latency", latency, "delay", delay)
+			} else {
+				c.logger.WarnContext(ctx, "retrying verkada link request", "url", redacted, "latency", latency, "delay", delay, "error", err)
+			}
+			if policy.OnRetry != nil {
+				policy.OnRetry(event)
+			}
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			defer res.Body.Close()
+			body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+			return nil, newAPIError(http.MethodGet, redacted, res, body)
+		}
+		contentType := res.Header.Get("Content-Type")
+		if !acceptsContentType(accept, contentType) {
+			defer res.Body.Close()
+			body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+			return nil, &ContentTypeError{Method: http.MethodGet, URL: redacted, ContentType: contentType, Expected: accept, Body: body}
+		}
+		return res, nil
+	}
+}
+
+// Returns u without its query and fragment.
+func withoutQuery(u *neturl.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	redacted.Fragment = ""
+	return redacted.String()
+}
+
+// Sends a download request and returns the response once its status and content type have been checked.
+// The response body must be closed by the caller.
+func (c *Client) sendDownload(ctx context.Context, method string, url string, params any, accept []string, retry int) (*http.Response, error) {
+	header := http.Header{}
+	if len(accept) > 0 {
+		header.Set("accept", strings.Join(accept, ", "))
+	}
+	res, err := c.send(ctx, method, url, params, header, nil, retry)
+	if err != nil {
+		return nil, err
+	}
+	contentType := res.Header.Get("Content-Type")
+	if !acceptsContentType(accept, contentType) {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, &ContentTypeError{Method: method, URL: redactURL(res.Request.URL.String()), ContentType: contentType, Expected: accept, Body: body}
+	}
+	return res, nil
+}
+
+// Reports whether a response's content type is one of accept, where "type/*" matches any subtype.
+// A missing content type is accepted since it cannot be checked, and JSON is refused when accept is empty.
+func acceptsContentType(accept []string, contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if len(accept) == 0 {
+		return mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")
+	}
+	for _, a := range accept {
+		a = strings.ToLower(a)
+		if a == mediaType || a == "*/*" || (strings.HasSuffix(a, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAcceptsContentType(t *testing.T) {
+	tests := []struct {
+		accept      []string
+		contentType string
+		want        bool
+	}{
+		{imageContentTypes, "image/jpeg", true},
+		{imageContentTypes, "IMAGE/PNG", true},
+		{imageContentTypes, "application/json", false},
+		{imageContentTypes, "", true},
+		{playlistContentTypes, "application/vnd.apple.mpegurl; charset=utf-8", true},
+		{playlistContentTypes, "text/html", false},
+		{nil, "text/csv", true},
+		{nil, "application/json", false},
+		{nil, "application/problem+json", false},
+		{[]string{"*/*"}, "application/json", true},
+		{nil, "not a media type;;", false},
+	}
+	for _, tt := range tests {
+		if got := acceptsContentType(tt.accept, tt.contentType); got != tt.want {
+			t.Errorf("acceptsContentType(%q, %q) = %v, want %v", tt.accept, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadContentTypeError(t *testing.T) {
+	c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message": "not a playlist"}`))
+	}, nil)
+	var buf bytes.Buffer
+	params := struct {
+		Jwt string `name:"jwt"`
+	}{"secret-jwt"}
+	_, _, err := c.MakeVerkadaRequestToWriterContext(context.Background(), "GET", srv.URL+"/stream/cameras/v1/footage/stream/stream.m3u8", params, &buf, playlistContentTypes, 0)
+	var ctErr *ContentTypeError
+	if !errors.As(err, &ctErr) || !errors.Is(err, ErrUnexpectedContentType) {
+		t.Fatalf("got %v, want a *ContentTypeError", err)
+	}
+	if strings.Contains(ctErr.URL, "secret-jwt") || !strings.Contains(ctErr.URL, "jwt=REDACTED") {
+		t.Errorf("URL %s is not redacted", ctErr.URL)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("wrote %q", buf.String())
+	}
+}
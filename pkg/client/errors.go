@@ -0,0 +1,198 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client/auth"
+)
+
+// Sentinel errors for use with errors.Is.
+// An *APIError matches the sentinel for its HTTP status, and local validation failures match ErrValidation.
+var (
+	ErrBadRequest   = errors.New("verkada: bad request")
+	ErrUnauthorized = errors.New("verkada: unauthorized")
+	ErrForbidden    = errors.New("verkada: forbidden")
+	ErrNotFound     = errors.New("verkada: not found")
+	ErrConflict     = errors.New("verkada: conflict")
+	ErrValidation   = errors.New("verkada: validation failed")
+	ErrRateLimited  = errors.New("verkada: rate limited")
+	ErrServer       = errors.New("verkada: server error")
+	// A successful response to a download was not of the expected content type, e.g. a JSON body instead of an image.
+	ErrUnexpectedContentType = errors.New("verkada: unexpected content type")
+)
+
+// An APIError is returned for any non-2xx response from the Verkada API, after any retries.
+// Use errors.As to inspect the fields, or errors.Is with the sentinel errors to check the category.
+type APIError struct {
+	// HTTP status code and status line of the response.
+	StatusCode int
+	Status     string
+	// Error code and message reported by Verkada, if the body included them.
+	Code    string
+	Message string
+	// Request identifier from the response headers or body, useful when contacting Verkada support.
+	RequestID string
+	// Method and URL (including query) of the failed request, with credentials such as the streaming JWT redacted.
+	Method string
+	URL    string
+	// Raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "verkada: %s %s: %s", e.Method, e.URL, e.Status)
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", code: %s", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ", message: %s", e.Message)
+	} else if len(e.Body) > 0 {
+		fmt.Fprintf(&b, ", response: %s", e.Body)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, ", request id: %s", e.RequestID)
+	}
+	return b.String()
+}
+
+// Matches the sentinel error corresponding to the status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// Builds an APIError from a non-2xx response and its already-read body.
+// Verkada error bodies are typically {"id": ..., "message": ..., "data": ...}, but other shapes are tolerated.
+func newAPIError(method string, url string, res *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		Method:     method,
+		URL:        url,
+		Body:       body,
+	}
+	var fields struct {
+		Id      string `json:"id"`
+		Code    any    `json:"code"`
+		Error   string `json:"error"`
+		Message string `json:"message"`
+		Detail  string `json:"detail"`
+	}
+	if json.Unmarshal(body, &fields) == nil {
+		e.Message = fields.Message
+		if e.Message == "" {
+			e.Message = fields.Detail
+		}
+		switch code := fields.Code.(type) {
+		case string:
+			e.Code = code
+		case float64:
+			e.Code = fmt.Sprint(code)
+		}
+		if e.Code == "" {
+			e.Code = fields.Error
+		}
+		e.RequestID = fields.Id
+	}
+	for _, h := range []string{"x-request-id", "x-verkada-request-id", "x-amzn-requestid"} {
+		if v := res.Header.Get(h); v != "" {
+			e.RequestID = v
+			break
+		}
+	}
+	return e
+}
+
+// A ContentTypeError is returned by downloads when a 2xx response has an unexpected content type,
+// such as a JSON body where an image was expected. Nothing is written for such a response.
+// It matches ErrUnexpectedContentType with errors.Is.
+type ContentTypeError struct {
+	// Method and URL (including query) of the request, with credentials such as the streaming JWT redacted.
+	Method string
+	URL    string
+	// Content type of the response, and the types that would have been accepted.
+	ContentType string
+	Expected    []string
+	// Start of the response body, at most 1 KiB.
+	Body []byte
+}
+
+func (e *ContentTypeError) Error() string {
+	msg := fmt.Sprintf("verkada: %s %s: unexpected content type %q", e.Method, e.URL, e.ContentType)
+	if len(e.Expected) > 0 {
+		msg += fmt.Sprintf(", expected one of %q", e.Expected)
+	}
+	if len(e.Body) > 0 {
+		msg += fmt.Sprintf(", response: %s", e.Body)
+	}
+	return msg
+}
+
+func (e *ContentTypeError) Is(target error) bool {
+	return target == ErrUnexpectedContentType
+}
+
+// A StreamingScopeError is returned when the streaming token does not grant access to a camera, as checked locally
+// by StreamingTokenSource before any link is built or footage is requested. It matches ErrForbidden with errors.Is,
+// as the Streaming API would reject the request.
+type StreamingScopeError struct {
+	Camera_id string
+	// The permission the token lacks ("live" or "historical"), or empty if the camera is not among its cameras.
+	Permission string
+	// The scope of the token.
+	AccessibleCameras []string
+	AccessibleSites   []string
+	Permissions       []string
+}
+
+func (e *StreamingScopeError) Error() string {
+	if e.Permission != "" {
+		return fmt.Sprintf("verkada: streaming token does not grant %s access to camera %s, permissions: %q", e.Permission, e.Camera_id, e.Permissions)
+	}
+	return fmt.Sprintf("verkada: camera %s is outside the scope of the streaming token (%d accessible cameras)", e.Camera_id, len(e.AccessibleCameras))
+}
+
+func (e *StreamingScopeError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// Converts an *auth.StatusError from a token endpoint into an *APIError, leaving other errors unchanged.
+func fromAuthError(err error) error {
+	var statusErr *auth.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	res := &http.Response{StatusCode: statusErr.StatusCode, Status: statusErr.Status, Header: statusErr.Header}
+	if res.Header == nil {
+		res.Header = http.Header{}
+	}
+	return newAPIError(statusErr.Method, statusErr.URL, res, statusErr.Body)
+}
+
+// Returned by methods when parameters fail local validation, before any request is made.
+// The result matches ErrValidation with errors.Is.
+func validationErrorf(format string, a ...any) error {
+	return fmt.Errorf("%w: %s", ErrValidation, fmt.Sprintf(format, a...))
+}
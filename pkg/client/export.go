@@ -0,0 +1,270 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+)
+
+// Options for ExportFootage.
+type ExportFootageOptions struct {
+	// Unix timestamps (seconds) of the footage to export, of any length. Both are required.
+	Start_time *int
+	End_time   *int
+	// "low_res" or "high_res"; the API defaults to low_res.
+	Resolution string
+	// Streaming JWT to start with, as for DownloadFootageOptions.Jwt.
+	Jwt string
+	// Number of segments downloaded at once; DefaultFootageParallelism if zero or less.
+	Parallelism int
+	// Length in seconds of the windows the range is split into, from 1 to MaxFootageWindow (the default if zero).
+	Window int
+	// Path of the checkpoint file; the output filename with ".checkpoint" appended if empty.
+	Checkpoint string
+}
+
+// The result of ExportFootage, covering the whole export including windows written before it was resumed.
+type ExportFootageResponse struct {
+	// Container of the written stream: "ts" for MPEG-TS or "mp4" for fragmented MP4.
+	Format string
+	// Number of windows downloaded by this call.
+	Windows  int
+	Segments int
+	// Size of the output file.
+	Bytes int64
+	// Total duration of the segments according to the media playlists.
+	Duration time.Duration
+	// Number of times the streaming JWT was replaced during this call.
+	TokenRefreshes int
+	// Start of the first window downloaded by this call if the export was resumed from a checkpoint, or zero.
+	ResumedFrom int
+}
+
+// Exports footage of any time range into a single file, splitting the range into windows that the stream endpoint
+// accepts (at most MaxFootageWindow seconds) and downloading them in order as DownloadFootage does.
+// Segments listed by the playlists of two consecutive windows, such as one spanning their boundary, are written once,
+// so the output is continuous, without gaps or repeated footage.
+//
+// After each window, the output is synced to disk and a checkpoint file records the progress. If the export fails or
+// is canceled, calling ExportFootage again with the same camera, range, resolution, and filename resumes after the last
+// completed window, discarding anything written after it. The checkpoint is removed once the export completes; remove
+// it to start over instead of resuming. A checkpoint for a different export is an error.
+//
+// If an error is returned, the response reports the progress recorded by the last checkpoint.
+func (c *CameraClient) ExportFootage(org_id string, camera_id string, options *ExportFootageOptions, filename string) (*ExportFootageResponse, error) {
+	return c.ExportFootageContext(context.Background(), org_id, camera_id, options, filename)
+}
+
+// Same as ExportFootage, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) ExportFootageContext(ctx context.Context, org_id string, camera_id string, options *ExportFootageOptions, filename string) (*ExportFootageResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.ExportFootage")
+	defer span.End()
+	if options == nil {
+		options = &ExportFootageOptions{}
+	}
+	if options.Start_time == nil || options.End_time == nil {
+		return nil, validationErrorf("start_time and end_time are required to export footage")
+	}
+	if *options.End_time <= *options.Start_time {
+		return nil, validationErrorf("end_time must be after start_time - received %d - %d", *options.End_time, *options.Start_time)
+	}
+	window := options.Window
+	if window == 0 {
+		window = MaxFootageWindow
+	}
+	if window < 0 || window > MaxFootageWindow {
+		return nil, validationErrorf("window must be between 1 and %d seconds - received %d", MaxFootageWindow, options.Window)
+	}
+	if filename == "" {
+		return nil, validationErrorf("filename is required to export footage")
+	}
+	// checks the camera, resolution, and token scope before touching any file
+	if _, err := c.footagePlaylistURL(org_id, camera_id, *options.Start_time, min(*options.Start_time+window, *options.End_time), options.Resolution); err != nil {
+		return nil, err
+	}
+	if options.Jwt == "" {
+		if _, err := c.client.StreamingTokens.JWTForCamera(ctx, camera_id, StreamingPermissionHistorical); err != nil {
+			return nil, err
+		}
+	}
+	checkpointPath := options.Checkpoint
+	if checkpointPath == "" {
+		checkpointPath = filename + ".checkpoint"
+	}
+
+	cp := &footageCheckpoint{
+		Org_id:     org_id,
+		Camera_id:  camera_id,
+		Start_time: *options.Start_time,
+		End_time:   *options.End_time,
+		Resolution: options.Resolution,
+		Next_time:  *options.Start_time,
+	}
+	ret := &ExportFootageResponse{}
+	resume := false
+	saved, err := readFootageCheckpoint(checkpointPath)
+	switch {
+	case err == nil:
+		if !saved.sameExport(cp) {
+			return nil, fmt.Errorf("checkpoint %s is for a different export - remove it to start over", checkpointPath)
+		}
+		cp, resume = saved, true
+		ret.ResumedFrom = cp.Next_time
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+	f, err := openExportFile(filename, cp, resume)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d := c.newFootageDownload(options.Jwt, options.Parallelism)
+	cp.restore(d)
+	result := func() *ExportFootageResponse {
+		ret.Format, ret.Segments, ret.Bytes, ret.Duration = cp.Format, cp.Segments, cp.Bytes, time.Duration(cp.Duration_ms)*time.Millisecond
+		ret.TokenRefreshes = d.jwt.replaced()
+		return ret
+	}
+	for cp.Next_time < cp.End_time {
+		start, end := cp.Next_time, min(cp.Next_time+window, cp.End_time)
+		u, err := c.footagePlaylistURL(org_id, camera_id, start, end, cp.Resolution)
+		if err != nil {
+			return result(), err
+		}
+		media, err := d.mediaPlaylist(ctx, u)
+		if err != nil {
+			return result(), fmt.Errorf("window %d - %d: %w", start, end, err)
+		}
+		var written DownloadFootageResponse
+		if err := d.writeSegments(ctx, media, f, &written); err != nil {
+			return result(), fmt.Errorf("window %d - %d: %w", start, end, err)
+		}
+		if err := f.Sync(); err != nil {
+			return result(), err
+		}
+		if cp.Format == "" && len(media.segments) > 0 {
+			cp.Format = media.format()
+		}
+		cp.Next_time = end
+		cp.Bytes += written.Bytes
+		cp.Segments += written.Segments
+		cp.Duration_ms += written.Duration.Milliseconds()
+		cp.save(d)
+		if err := writeFootageCheckpoint(checkpointPath, cp); err != nil {
+			return result(), err
+		}
+		ret.Windows++
+		c.client.logger.DebugContext(ctx, "verkada footage window exported", "camera_id", camera_id, "start_time", start, "end_time", end, "segments", written.Segments)
+	}
+	if err := f.Close(); err != nil {
+		return result(), err
+	}
+	if err := os.Remove(checkpointPath); err != nil {
+		return result(), err
+	}
+	return result(), nil
+}
+
+// Opens the output of an export: truncated to the size recorded by the checkpoint when resuming, or created empty.
+func openExportFile(filename string, cp *footageCheckpoint, resume bool) (*os.File, error) {
+	if !resume {
+		return os.Create(filename)
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resume export: %w", err)
+	}
+	info, err := f.Stat()
+	if err == nil && info.Size() < cp.Bytes {
+		err = fmt.Errorf("cannot resume export: %s has %d bytes but the checkpoint recorded %d", filename, info.Size(), cp.Bytes)
+	}
+	if err == nil {
+		err = f.Truncate(cp.Bytes)
+	}
+	if err == nil {
+		_, err = f.Seek(cp.Bytes, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// The progress of ExportFootage, saved after each window.
+type footageCheckpoint struct {
+	Org_id     string `json:"org_id"`
+	Camera_id  string `json:"camera_id"`
+	Start_time int    `json:"start_time"`
+	End_time   int    `json:"end_time"`
+	Resolution string `json:"resolution"`
+	// start of the next window to download
+	Next_time int `json:"next_time"`
+	// totals after the last completed window; Bytes is the size of the output
+	Bytes       int64  `json:"bytes"`
+	Segments    int    `json:"segments"`
+	Duration_ms int64  `json:"duration_ms"`
+	Format      string `json:"format"`
+	// what footageDownload needs to skip segments already written
+	Last_end  time.Time `json:"last_end"`
+	Last_keys []string  `json:"last_keys"`
+	Map_uri   string    `json:"map_uri"`
+	Init_sum  string    `json:"init_sum"`
+}
+
+// Reports whether a saved checkpoint belongs to the same export as cp.
+func (cp *footageCheckpoint) sameExport(other *footageCheckpoint) bool {
+	return cp.Org_id == other.Org_id && cp.Camera_id == other.Camera_id && cp.Start_time == other.Start_time &&
+		cp.End_time == other.End_time && cp.Resolution == other.Resolution
+}
+
+// Records the deduplication state of d.
+func (cp *footageCheckpoint) save(d *footageDownload) {
+	cp.Last_end, cp.Map_uri, cp.Init_sum = d.lastEnd, d.mapURI, d.initSum
+	cp.Last_keys = cp.Last_keys[:0]
+	for key := range d.lastKeys {
+		cp.Last_keys = append(cp.Last_keys, key)
+	}
+	slices.Sort(cp.Last_keys)
+}
+
+// Restores the deduplication state of d.
+func (cp *footageCheckpoint) restore(d *footageDownload) {
+	d.lastEnd, d.mapURI, d.initSum = cp.Last_end, cp.Map_uri, cp.Init_sum
+	d.lastKeys = map[string]bool{}
+	for _, key := range cp.Last_keys {
+		d.lastKeys[key] = true
+	}
+}
+
+func readFootageCheckpoint(path string) (*footageCheckpoint, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cp footageCheckpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// Writes a checkpoint to a temporary file renamed over path, so that a crash never leaves a partial checkpoint.
+func writeFootageCheckpoint(path string, cp *footageCheckpoint) error {
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
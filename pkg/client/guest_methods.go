@@ -1,6 +1,9 @@
 package client
 
-import "fmt"
+import (
+	"context"
+	"io"
+)
 
 // Deletes all deny list entries, including the CSV, POI entries, and photos from the specified site.
 //
@@ -8,10 +11,17 @@ import "fmt"
 //
 // [Verkada API Docs - Delete a Guest Deny List]: https://apidocs.verkada.com/reference/deletedenylistview
 func (c *GuestClient) DeleteDenyList(site_id string) (*DeleteDenyListResponse, error) {
+	return c.DeleteDenyListContext(context.Background(), site_id)
+}
+
+// Same as DeleteDenyList, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *GuestClient) DeleteDenyListContext(ctx context.Context, site_id string) (*DeleteDenyListResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Guest.DeleteDenyList")
+	defer span.End()
 	options := &DeleteDenyListOptions{site_id: site_id}
 	var ret DeleteDenyListResponse
 	url := c.client.baseURL + "/guest/v1/deny_list"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -22,10 +32,38 @@ func (c *GuestClient) DeleteDenyList(site_id string) (*DeleteDenyListResponse, e
 //
 // [Verkada API Docs - Post Guest Deny List]: https://apidocs.verkada.com/reference/postdenylistview
 func (c *GuestClient) PostDenyList(site_id string, uploadFilename string) (*PostDenyListResponse, error) {
+	return c.PostDenyListContext(context.Background(), site_id, uploadFilename)
+}
+
+// Same as PostDenyList, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *GuestClient) PostDenyListContext(ctx context.Context, site_id string, uploadFilename string) (*PostDenyListResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Guest.PostDenyList")
+	defer span.End()
 	options := &PostDenyListOptions{site_id: site_id}
 	var ret PostDenyListResponse
 	url := c.client.baseURL + "/guest/v1/deny_list"
-	err := c.client.MakeVerkadaRequestWithFile("POST", url, *options, uploadFilename, "text/csv", &ret, 0)
+	err := c.client.MakeVerkadaRequestWithFileContext(ctx, "POST", url, *options, uploadFilename, "text/csv", &ret, 0)
+	return &ret, err
+}
+
+// Same as PostDenyList, uploading the csv deny list from r instead of a file on disk.
+// options can set a size limit and progress callback, and may be nil.
+func (c *GuestClient) PostDenyListFromReader(site_id string, r io.Reader, options *UploadOptions) (*PostDenyListResponse, error) {
+	return c.PostDenyListFromReaderContext(context.Background(), site_id, r, options)
+}
+
+// Same as PostDenyListFromReader, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *GuestClient) PostDenyListFromReaderContext(ctx context.Context, site_id string, r io.Reader, options *UploadOptions) (*PostDenyListResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Guest.PostDenyListFromReader")
+	defer span.End()
+	params := &PostDenyListOptions{site_id: site_id}
+	upload := Upload{Filename: "deny_list.csv", ContentType: "text/csv", Body: r}
+	if options != nil {
+		upload.UploadOptions = *options
+	}
+	var ret PostDenyListResponse
+	url := c.client.baseURL + "/guest/v1/deny_list"
+	err := c.client.MakeVerkadaRequestWithReaderContext(ctx, "POST", url, *params, upload, &ret, 0)
 	return &ret, err
 }
 
@@ -35,9 +73,16 @@ func (c *GuestClient) PostDenyList(site_id string, uploadFilename string) (*Post
 //
 // [Verkada API Docs - Get Guest Sites]: https://apidocs.verkada.com/reference/getguestsiteview
 func (c *GuestClient) GetGuestSites() (*GetGuestSitesResponse, error) {
+	return c.GetGuestSitesContext(context.Background())
+}
+
+// Same as GetGuestSites, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *GuestClient) GetGuestSitesContext(ctx context.Context) (*GetGuestSitesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Guest.GetGuestSites")
+	defer span.End()
 	var ret GetGuestSitesResponse
 	url := c.client.baseURL + "/guest/v1/sites"
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -48,67 +93,191 @@ func (c *GuestClient) GetGuestSites() (*GetGuestSitesResponse, error) {
 //
 // [Verkada API Docs - Get Guest Visits]: https://apidocs.verkada.com/reference/getvisitview
 func (c *GuestClient) GetGuestVisits(site_id string, start_time *int, end_time *int, options *GetGuestVisitsOptions) (*GetGuestVisitsResponse, error) {
+	return c.GetGuestVisitsContext(context.Background(), site_id, start_time, end_time, options)
+}
+
+// Same as GetGuestVisits, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *GuestClient) GetGuestVisitsContext(ctx context.Context, site_id string, start_time *int, end_time *int, options *GetGuestVisitsOptions) (*GetGuestVisitsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Guest.GetGuestVisits")
+	defer span.End()
 	if options == nil {
 		options = &GetGuestVisitsOptions{}
 	}
 	options.site_id, options.start_time, options.end_time = site_id, start_time, end_time
-	// max timeframe of one day (86,400 seconds)
-	if *options.end_time-*options.start_time > 86400 {
-		return nil, fmt.Errorf("difference between start_time and end_time is too large: %d - %d = %d", *options.end_time, *options.start_time, (*options.end_time - *options.start_time))
-	}
-	// page_size must be between 1 and 200
-	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	if err := validateGetGuestVisitsOptions(options); err != nil {
+		return nil, err
 	}
 	var ret GetGuestVisitsResponse
 	url := c.client.baseURL + "/guest/v1/visits"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	if err != nil {
 		return nil, err
 	}
 	if c.client.AutoPaginate {
-		for len(ret.Visits) > 0 && ret.Visits[len(ret.Visits)-1].Next_page_token != "" {
-			var nextRet GetGuestVisitsResponse
-			options.Page_token = ret.Visits[len(ret.Visits)-1].Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
+		if n := len(ret.Visits); n > 0 {
+			var next string
+			next, err = collectPages(ctx, ret.Visits[n-1].Next_page_token, c.guestVisitsPages(*options), &ret.Visits)
+			// the page tokens are carried by the last visit of each page; only the last visit of the combined response
+			// keeps one, from which a failed request can be resumed
+			for i := n - 1; i < len(ret.Visits); i++ {
+				ret.Visits[i].Next_page_token = ""
 			}
-			ret.Visits = append(ret.Visits, nextRet.Visits...)
-			ret.Visits[len(ret.Visits)-1].Next_page_token = nextRet.Visits[len(nextRet.Visits)-1].Next_page_token
+			ret.Visits[len(ret.Visits)-1].Next_page_token = next
 		}
 	}
 	return &ret, err
 }
 
+// Same as GetGuestVisits, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *GuestClient) GetGuestVisitsIter(ctx context.Context, site_id string, start_time *int, end_time *int, options *GetGuestVisitsOptions) *Paginator[GuestVisit] {
+	ctx = c.client.withOperation(ctx, "Guest.GetGuestVisits")
+	if options == nil {
+		options = &GetGuestVisitsOptions{}
+	}
+	options.site_id, options.start_time, options.end_time = site_id, start_time, end_time
+	if err := validateGetGuestVisitsOptions(options); err != nil {
+		return errPaginator[GuestVisit](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.guestVisitsPages(*options))
+}
+
+// Returns the page function shared by GetGuestVisitsContext and GetGuestVisitsIter.
+func (c *GuestClient) guestVisitsPages(options GetGuestVisitsOptions) pageFunc[GuestVisit] {
+	url := c.client.baseURL + "/guest/v1/visits"
+	return func(ctx context.Context, token string) ([]GuestVisit, string, error) {
+		options.Page_token = token
+		var ret GetGuestVisitsResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(ret.Visits) == 0 {
+			return nil, "", nil
+		}
+		return ret.Visits, ret.Visits[len(ret.Visits)-1].Next_page_token, nil
+	}
+}
+
+func validateGetGuestVisitsOptions(options *GetGuestVisitsOptions) error {
+	if options.start_time == nil || options.end_time == nil {
+		return validationErrorf("parameters start_time and end_time are required")
+	}
+	// max timeframe of one day (86,400 seconds)
+	if *options.end_time-*options.start_time > 86400 {
+		return validationErrorf("difference between start_time and end_time is too large: %d - %d = %d", *options.end_time, *options.start_time, (*options.end_time - *options.start_time))
+	}
+	// page_size must be between 1 and 200
+	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+	}
+	return nil
+}
+
 // Returns a list of Guest types applied to a site.
 //
 // [Verkada API Docs - Get Guest Types]
 //
 // [Verkada API Docs - Get Guest Types]: https://apidocs.verkada.com/reference/getguesttypeviewv2
 func (c *GuestClient) GetGuestTypes(site_id string, options *GetGuestTypesOptions) (*GetGuestTypesResponse, error) {
+	return c.GetGuestTypesContext(context.Background(), site_id, options)
+}
+
+// Same as GetGuestTypes, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *GuestClient) GetGuestTypesContext(ctx context.Context, site_id string, options *GetGuestTypesOptions) (*GetGuestTypesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Guest.GetGuestTypes")
+	defer span.End()
 	if options == nil {
 		options = &GetGuestTypesOptions{}
 	}
 	options.site_id = site_id
 	var ret GetGuestTypesResponse
 	url := c.client.baseURL + "/v2/guest/guest_types"
-	err := c.client.MakeVerkadaRequest("GET", url, options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c.client.AutoPaginate {
+		ret.Cursor, err = collectPages(ctx, ret.Cursor, c.guestTypesPages(*options), &ret.Items)
+	}
 	return &ret, err
 }
 
+// Same as GetGuestTypes, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Cursor if set, and is unaffected by Client.AutoPaginate.
+func (c *GuestClient) GetGuestTypesIter(ctx context.Context, site_id string, options *GetGuestTypesOptions) *Paginator[GuestType] {
+	ctx = c.client.withOperation(ctx, "Guest.GetGuestTypes")
+	if options == nil {
+		options = &GetGuestTypesOptions{}
+	}
+	options.site_id = site_id
+	return newPaginator(ctx, options.Cursor, c.guestTypesPages(*options))
+}
+
+// Returns the page function shared by GetGuestTypesContext and GetGuestTypesIter.
+func (c *GuestClient) guestTypesPages(options GetGuestTypesOptions) pageFunc[GuestType] {
+	url := c.client.baseURL + "/v2/guest/guest_types"
+	return func(ctx context.Context, token string) ([]GuestType, string, error) {
+		options.Cursor = token
+		var ret GetGuestTypesResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Items, ret.Cursor, nil
+	}
+}
+
 // Returns a list of hosts in a site.
 //
 // [Verkada API Docs - Get Hosts]
 //
 // [Verkada API Docs - Get Hosts]: https://apidocs.verkada.com/reference/gethostviewv2
 func (c *GuestClient) GetHosts(site_id string, options *GetHostsOptions) (*GetHostsResponse, error) {
+	return c.GetHostsContext(context.Background(), site_id, options)
+}
+
+// Same as GetHosts, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *GuestClient) GetHostsContext(ctx context.Context, site_id string, options *GetHostsOptions) (*GetHostsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Guest.GetHosts")
+	defer span.End()
 	if options == nil {
 		options = &GetHostsOptions{}
 	}
 	options.site_id = site_id
 	var ret GetHostsResponse
 	url := c.client.baseURL + "/v2/guest/hosts"
-	err := c.client.MakeVerkadaRequest("GET", url, options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c.client.AutoPaginate {
+		ret.Cursor, err = collectPages(ctx, ret.Cursor, c.hostsPages(*options), &ret.Items)
+	}
 	return &ret, err
 }
+
+// Same as GetHosts, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Cursor if set, and is unaffected by Client.AutoPaginate.
+func (c *GuestClient) GetHostsIter(ctx context.Context, site_id string, options *GetHostsOptions) *Paginator[Host] {
+	ctx = c.client.withOperation(ctx, "Guest.GetHosts")
+	if options == nil {
+		options = &GetHostsOptions{}
+	}
+	options.site_id = site_id
+	return newPaginator(ctx, options.Cursor, c.hostsPages(*options))
+}
+
+// Returns the page function shared by GetHostsContext and GetHostsIter.
+func (c *GuestClient) hostsPages(options GetHostsOptions) pageFunc[Host] {
+	url := c.client.baseURL + "/v2/guest/hosts"
+	return func(ctx context.Context, token string) ([]Host, string, error) {
+		options.Cursor = token
+		var ret GetHostsResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Items, ret.Cursor, nil
+	}
+}
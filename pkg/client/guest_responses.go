@@ -18,50 +18,56 @@ type GetGuestSitesResponse struct {
 }
 
 type GetGuestVisitsResponse struct {
-	Visits []struct {
-		Approval_status        string              `json:"approval_status"`
-		Check_in_time          int                 `json:"check_in_time"`
-		Deleted                bool                `json:"deleted"`
-		Device_name            string              `json:"device_name"`
-		Guest                  guestInfo           `json:"guest"`
-		Host                   hostInfo            `json:"host"`
-		Host_approval_status   string              `json:"host_approval_status"`
-		Hosts                  []hostInfo          `json:"hosts"`
-		Is_contactless         bool                `json:"is_contactless"`
-		Next_page_token        string              `json:"next_page_token"`
-		Open_ended_responses   []openEndedResponse `json:"open_ended_responses"`
-		Questionnaires         []questionnaire     `json:"questionnaires"`
-		Security_screen_status string              `json:"security_screen_status"`
-		Sign_out_time          int                 `json:"sign_out_time"`
-		Signatures             []signature         `json:"signatures"`
-		Site_id                string              `json:"site_id"`
-		Visit_id               string              `json:"visit_id"`
-		Visit_type             string              `json:"visit_type"`
-	} `json:"visits"`
+	Visits []GuestVisit `json:"visits"`
+}
+
+type GuestVisit struct {
+	Approval_status        string              `json:"approval_status"`
+	Check_in_time          int                 `json:"check_in_time"`
+	Deleted                bool                `json:"deleted"`
+	Device_name            string              `json:"device_name"`
+	Guest                  guestInfo           `json:"guest"`
+	Host                   hostInfo            `json:"host"`
+	Host_approval_status   string              `json:"host_approval_status"`
+	Hosts                  []hostInfo          `json:"hosts"`
+	Is_contactless         bool                `json:"is_contactless"`
+	Next_page_token        string              `json:"next_page_token"`
+	Open_ended_responses   []openEndedResponse `json:"open_ended_responses"`
+	Questionnaires         []questionnaire     `json:"questionnaires"`
+	Security_screen_status string              `json:"security_screen_status"`
+	Sign_out_time          int                 `json:"sign_out_time"`
+	Signatures             []signature         `json:"signatures"`
+	Site_id                string              `json:"site_id"`
+	Visit_id               string              `json:"visit_id"`
+	Visit_type             string              `json:"visit_type"`
 }
 
 type GetGuestTypesResponse struct {
-	Cursor string `json:"cursor"`
-	Items  []struct {
-		Enabled_for_invites bool   `json:"enabled_for_invites"`
-		Guest_type_id       string `json:"guest_type_id"`
-		Name                string `json:"name"`
-	} `json:"items"`
+	Cursor string      `json:"cursor"`
+	Items  []GuestType `json:"items"`
+}
+
+type GuestType struct {
+	Enabled_for_invites bool   `json:"enabled_for_invites"`
+	Guest_type_id       string `json:"guest_type_id"`
+	Name                string `json:"name"`
 }
 
 type GetHostsResponse struct {
 	Cursor string `json:"cursor"`
-	Items  []struct {
-		Email                  string `json:"email"`
-		First_name             string `json:"first_name"`
-		Full_name              string `json:"full_name"`
-		Has_delegate           bool   `json:"has_delegate"`
-		Host_id                string `json:"host_id"`
-		Last_name              string `json:"last_name"`
-		Original_first_name    string `json:"original_first_name"`
-		Phone_number           string `json:"phone_number"`
-		Requires_host_approval bool   `json:"requires_host_approval"`
-	}
+	Items  []Host
+}
+
+type Host struct {
+	Email                  string `json:"email"`
+	First_name             string `json:"first_name"`
+	Full_name              string `json:"full_name"`
+	Has_delegate           bool   `json:"has_delegate"`
+	Host_id                string `json:"host_id"`
+	Last_name              string `json:"last_name"`
+	Original_first_name    string `json:"original_first_name"`
+	Phone_number           string `json:"phone_number"`
+	Requires_host_approval bool   `json:"requires_host_approval"`
 }
 
 type guestInfo struct {
@@ -1,6 +1,6 @@
 package client
 
-import "fmt"
+import "context"
 
 // This method can be used to delete a Helix event from Command.
 // The required parameters to successfully delete a Helix event are the associated Camera ID, Event Type UID, and the exact event epoch time in milliseconds.
@@ -9,10 +9,17 @@ import "fmt"
 //
 // [Verkada API Docs - Delete a Helix Event]: https://apidocs.verkada.com/reference/deletevideotaggingeventviewv1
 func (c *HelixClient) DeleteHelixEvent(camera_id string, time_ms int64, event_type_uid string) (*DeleteHelixEventResponse, error) {
+	return c.DeleteHelixEventContext(context.Background(), camera_id, time_ms, event_type_uid)
+}
+
+// Same as DeleteHelixEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) DeleteHelixEventContext(ctx context.Context, camera_id string, time_ms int64, event_type_uid string) (*DeleteHelixEventResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.DeleteHelixEvent")
+	defer span.End()
 	options := &DeleteHelixEventOptions{camera_id: camera_id, time_ms: Ptr(time_ms), event_type_uid: event_type_uid}
 	var ret DeleteHelixEventResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -24,10 +31,17 @@ func (c *HelixClient) DeleteHelixEvent(camera_id string, time_ms int64, event_ty
 //
 // [Verkada API Docs - Get a Helix Event]: https://apidocs.verkada.com/reference/getvideotaggingeventviewv1
 func (c *HelixClient) GetHelixEvent(camera_id string, time_ms int64, event_type_uid string) (*GetHelixEventResponse, error) {
+	return c.GetHelixEventContext(context.Background(), camera_id, time_ms, event_type_uid)
+}
+
+// Same as GetHelixEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) GetHelixEventContext(ctx context.Context, camera_id string, time_ms int64, event_type_uid string) (*GetHelixEventResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.GetHelixEvent")
+	defer span.End()
 	options := &GetHelixEventOptions{camera_id: camera_id, time_ms: Ptr(time_ms), event_type_uid: event_type_uid}
 	var ret GetHelixEventResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -39,6 +53,13 @@ func (c *HelixClient) GetHelixEvent(camera_id string, time_ms int64, event_type_
 //
 // [Verkada API Docs - Update a Helix Event]: https://apidocs.verkada.com/reference/patchvideotaggingeventviewv1
 func (c *HelixClient) UpdateHelixEvent(camera_id string, time_ms int64, event_type_uid string, body *UpdateHelixEventBody) (*UpdateHelixEventResponse, error) {
+	return c.UpdateHelixEventContext(context.Background(), camera_id, time_ms, event_type_uid, body)
+}
+
+// Same as UpdateHelixEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) UpdateHelixEventContext(ctx context.Context, camera_id string, time_ms int64, event_type_uid string, body *UpdateHelixEventBody) (*UpdateHelixEventResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.UpdateHelixEvent")
+	defer span.End()
 	attributes := make(map[string]any, len(body.Attributes))
 	for _, item := range body.Attributes {
 		attributes[item.Key] = item.Value
@@ -53,7 +74,7 @@ func (c *HelixClient) UpdateHelixEvent(camera_id string, time_ms int64, event_ty
 	options := &UpdateHelixEventOptions{camera_id: camera_id, time_ms: Ptr(time_ms), event_type_uid: event_type_uid}
 	var ret UpdateHelixEventResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event"
-	err := c.client.MakeVerkadaRequest("PATCH", url, *options, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PATCH", url, *options, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -68,6 +89,13 @@ func (c *HelixClient) UpdateHelixEvent(camera_id string, time_ms int64, event_ty
 //
 // [Verkada API Docs - Create a Helix Event]: https://apidocs.verkada.com/reference/postvideotaggingeventviewv1
 func (c *HelixClient) CreateHelixEvent(camera_id string, time_ms int64, event_type_uid string, body *CreateHelixEventBody) (*CreateHelixEventResponse, error) {
+	return c.CreateHelixEventContext(context.Background(), camera_id, time_ms, event_type_uid, body)
+}
+
+// Same as CreateHelixEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) CreateHelixEventContext(ctx context.Context, camera_id string, time_ms int64, event_type_uid string, body *CreateHelixEventBody) (*CreateHelixEventResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.CreateHelixEvent")
+	defer span.End()
 	attributes := make(map[string]any, len(body.Attributes))
 	for _, item := range body.Attributes {
 		attributes[item.Key] = item.Value
@@ -87,7 +115,7 @@ func (c *HelixClient) CreateHelixEvent(camera_id string, time_ms int64, event_ty
 	}
 	var ret CreateHelixEventResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -106,9 +134,16 @@ func (c *HelixClient) CreateHelixEvent(camera_id string, time_ms int64, event_ty
 //
 // [Verkada API Docs - Search a Helix Event]: https://apidocs.verkada.com/reference/postvideotaggingeventsearchviewv1
 func (c *HelixClient) SearchHelixEvent(body *SearchHelixEventBody) (*SearchHelixEventResponse, error) {
+	return c.SearchHelixEventContext(context.Background(), body)
+}
+
+// Same as SearchHelixEvent, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) SearchHelixEventContext(ctx context.Context, body *SearchHelixEventBody) (*SearchHelixEventResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.SearchHelixEvent")
+	defer span.End()
 	var ret SearchHelixEventResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, body, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, body, &ret, 0)
 	return &ret, err
 }
 
@@ -122,10 +157,17 @@ func (c *HelixClient) SearchHelixEvent(body *SearchHelixEventBody) (*SearchHelix
 //
 // [Verkada API Docs - Delete a Helix Event Type]: https://apidocs.verkada.com/reference/deletevideotaggingeventtypeviewv1
 func (c *HelixClient) DeleteHelixEventType(event_type_uid string) (*DeleteHelixEventTypeResponse, error) {
+	return c.DeleteHelixEventTypeContext(context.Background(), event_type_uid)
+}
+
+// Same as DeleteHelixEventType, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) DeleteHelixEventTypeContext(ctx context.Context, event_type_uid string) (*DeleteHelixEventTypeResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.DeleteHelixEventType")
+	defer span.End()
 	options := &DeleteHelixEventTypeOptions{event_type_uid: event_type_uid}
 	var ret DeleteHelixEventTypeResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event_type"
-	err := c.client.MakeVerkadaRequest("DELETE", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "DELETE", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -136,16 +178,23 @@ func (c *HelixClient) DeleteHelixEventType(event_type_uid string) (*DeleteHelixE
 //
 // [Verkada API Docs - Get List of Helix Event Types]: https://apidocs.verkada.com/reference/getvideotaggingeventtypeviewv1
 func (c *HelixClient) GetHelixEventTypes(options *GetHelixEventTypesOptions) (*GetHelixEventTypesResponse, error) {
+	return c.GetHelixEventTypesContext(context.Background(), options)
+}
+
+// Same as GetHelixEventTypes, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) GetHelixEventTypesContext(ctx context.Context, options *GetHelixEventTypesOptions) (*GetHelixEventTypesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.GetHelixEventTypes")
+	defer span.End()
 	if options == nil {
 		options = &GetHelixEventTypesOptions{}
 	}
 	// shouldn't filter by both uid and name
 	if options.Event_type_uid != "" && options.Name != "" {
-		return nil, fmt.Errorf("should not filter by both uid and name - received uid %s and name %s", options.Event_type_uid, options.Name)
+		return nil, validationErrorf("should not filter by both uid and name - received uid %s and name %s", options.Event_type_uid, options.Name)
 	}
 	var ret GetHelixEventTypesResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event_type"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
 	return &ret, err
 }
 
@@ -166,6 +215,13 @@ func (c *HelixClient) GetHelixEventTypes(options *GetHelixEventTypesOptions) (*G
 //
 // [Verkada API Docs - Update a Helix Event Type]: https://apidocs.verkada.com/reference/patchvideotaggingeventtypeviewv1
 func (c *HelixClient) UpdateHelixEventType(event_type_uid string, event_schema map[string]string, name string) (*UpdateHelixEventTypeResponse, error) {
+	return c.UpdateHelixEventTypeContext(context.Background(), event_type_uid, event_schema, name)
+}
+
+// Same as UpdateHelixEventType, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) UpdateHelixEventTypeContext(ctx context.Context, event_type_uid string, event_schema map[string]string, name string) (*UpdateHelixEventTypeResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.UpdateHelixEventType")
+	defer span.End()
 	// validate data types in event_schema
 	data_type_validation := map[string]bool{
 		"string":  true,
@@ -175,7 +231,7 @@ func (c *HelixClient) UpdateHelixEventType(event_type_uid string, event_schema m
 	}
 	for key, value := range event_schema {
 		if ok := data_type_validation[value]; !ok {
-			return nil, fmt.Errorf("could not validate field type - received key: %s and value: %s", key, value)
+			return nil, validationErrorf("could not validate field type - received key: %s and value: %s", key, value)
 		}
 	}
 	options := &UpdateHelixEventTypeOptions{event_type_uid: event_type_uid}
@@ -188,7 +244,7 @@ func (c *HelixClient) UpdateHelixEventType(event_type_uid string, event_schema m
 	}
 	var ret UpdateHelixEventTypeResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event_type"
-	err := c.client.MakeVerkadaRequest("PATCH", url, *options, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "PATCH", url, *options, fullBody, &ret, 0)
 	return &ret, err
 }
 
@@ -203,6 +259,13 @@ func (c *HelixClient) UpdateHelixEventType(event_type_uid string, event_schema m
 //
 // [Verkada API Docs - Create a Helix Event Type]: https://apidocs.verkada.com/reference/postvideotaggingeventtypeviewv1
 func (c *HelixClient) CreateHelixEventType(event_schema map[string]string, name string) (*CreateHelixEventTypeResponse, error) {
+	return c.CreateHelixEventTypeContext(context.Background(), event_schema, name)
+}
+
+// Same as CreateHelixEventType, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *HelixClient) CreateHelixEventTypeContext(ctx context.Context, event_schema map[string]string, name string) (*CreateHelixEventTypeResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Helix.CreateHelixEventType")
+	defer span.End()
 	// validate data types in event_schema
 	data_type_validation := map[string]bool{
 		"string":  true,
@@ -212,7 +275,7 @@ func (c *HelixClient) CreateHelixEventType(event_schema map[string]string, name
 	}
 	for key, value := range event_schema {
 		if ok := data_type_validation[value]; !ok {
-			return nil, fmt.Errorf("could not validate field type - received key: %s and value: %s", key, value)
+			return nil, validationErrorf("could not validate field type - received key: %s and value: %s", key, value)
 		}
 	}
 	fullBody := struct {
@@ -224,6 +287,6 @@ func (c *HelixClient) CreateHelixEventType(event_schema map[string]string, name
 	}
 	var ret CreateHelixEventTypeResponse
 	url := c.client.baseURL + "/cameras/v1/video_tagging/event_type"
-	err := c.client.MakeVerkadaRequest("POST", url, nil, fullBody, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "POST", url, nil, fullBody, &ret, 0)
 	return &ret, err
 }
@@ -0,0 +1,598 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Longest time range, in seconds, of a single footage stream (StreamFootage and DownloadFootage).
+// ExportFootage splits longer ranges into windows of at most this length.
+const MaxFootageWindow = 3600
+
+// Number of segments DownloadFootage fetches at once when DownloadFootageOptions.Parallelism is not set.
+const DefaultFootageParallelism = 4
+
+// Size limit of a playlist, which only lists segments.
+const maxPlaylistSize = 16 << 20
+
+// Options for DownloadFootage.
+type DownloadFootageOptions struct {
+	// Unix timestamps (seconds) of the footage to download, at most an hour apart. Both are required.
+	Start_time *int
+	End_time   *int
+	// "low_res" or "high_res"; the API defaults to low_res.
+	Resolution string
+	// Streaming JWT to start with, e.g. from GetStreamingToken. If empty, that of the Client's StreamingTokens is used,
+	// after checking that it grants historical access to the camera. Either way, the JWT is replaced with a new one from
+	// StreamingTokens when it expires or is rejected.
+	Jwt string
+	// Number of segments downloaded at once; DefaultFootageParallelism if zero or less.
+	// At most this many segments are held in memory while waiting to be written in order.
+	Parallelism int
+}
+
+// The result of DownloadFootage.
+type DownloadFootageResponse struct {
+	// Container of the written stream: "ts" for MPEG-TS or "mp4" for fragmented MP4.
+	Format string
+	// Number of media segments written, not counting fragmented MP4 initialization sections.
+	Segments int
+	// Number of bytes written.
+	Bytes int64
+	// Total duration of the segments according to the media playlist.
+	Duration time.Duration
+	// Number of times the streaming JWT was replaced during the download.
+	TokenRefreshes int
+}
+
+// Downloads historical footage over HLS and writes it to w as a single contiguous stream.
+// The master playlist (if any) is resolved to its highest-bandwidth variant, the media playlist's segments are
+// downloaded concurrently, and they are written to w in playlist order, preceded by their initialization section
+// for fragmented MP4. The result is an MPEG-TS stream (e.g. for a .ts file) or a fragmented MP4 stream (e.g. for an
+// .mp4 file), depending on what the playlist serves.
+//
+// Requests for playlists and segments are retried according to the Client's RetryPolicy. Those on the streaming host
+// carry the streaming JWT as their jwt query parameter; URIs on other hosts, such as a CDN, are fetched as listed,
+// without credentials. The JWT is replaced with one from the Client's StreamingTokens shortly before it
+// expires (streaming JWTs last 30 minutes) or if a request is rejected with 401 or 403, so that long downloads do not
+// fail midway.
+//
+// If an error is returned, w may hold a partial stream; DownloadFootageResponse reports what was written.
+// Encrypted playlists and byte-range segments are not supported.
+func (c *CameraClient) DownloadFootage(org_id string, camera_id string, options *DownloadFootageOptions, w io.Writer) (*DownloadFootageResponse, error) {
+	return c.DownloadFootageContext(context.Background(), org_id, camera_id, options, w)
+}
+
+// Same as DownloadFootage, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) DownloadFootageContext(ctx context.Context, org_id string, camera_id string, options *DownloadFootageOptions, w io.Writer) (*DownloadFootageResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.DownloadFootage")
+	defer span.End()
+	if options == nil {
+		options = &DownloadFootageOptions{}
+	}
+	if options.Start_time == nil || options.End_time == nil {
+		return nil, validationErrorf("start_time and end_time are required to download footage")
+	}
+	playlistURL, err := c.footagePlaylistURL(org_id, camera_id, *options.Start_time, *options.End_time, options.Resolution)
+	if err != nil {
+		return nil, err
+	}
+	if options.Jwt == "" {
+		if _, err := c.client.StreamingTokens.JWTForCamera(ctx, camera_id, StreamingPermissionHistorical); err != nil {
+			return nil, err
+		}
+	}
+	d := c.newFootageDownload(options.Jwt, options.Parallelism)
+	ret := &DownloadFootageResponse{}
+	media, err := d.mediaPlaylist(ctx, playlistURL)
+	if err == nil {
+		ret.Format = media.format()
+		err = d.writeSegments(ctx, media, w, ret)
+	}
+	ret.TokenRefreshes = d.jwt.replaced()
+	return ret, err
+}
+
+// Returns the URL of the footage playlist of a time range of at most MaxFootageWindow seconds.
+func (c *CameraClient) footagePlaylistURL(org_id string, camera_id string, start int, end int, resolution string) (*neturl.URL, error) {
+	footage := &GetFootageOptions{org_id: org_id, camera_id: camera_id, Start_time: &start, End_time: &end, Resolution: resolution}
+	if err := validateGetFootageOptions(footage); err != nil {
+		return nil, err
+	}
+	if end <= start {
+		return nil, validationErrorf("end_time must be after start_time - received %d - %d", end, start)
+	}
+	values, err := encodeQuery(footage)
+	if err != nil {
+		return nil, validationErrorf("cannot encode query params: %v", err)
+	}
+	u, err := neturl.Parse(c.client.streamingURL + "/stream/cameras/v1/footage/stream/stream.m3u8")
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = values.Encode()
+	return u, nil
+}
+
+func (c *CameraClient) newFootageDownload(jwt string, parallelism int) *footageDownload {
+	if parallelism <= 0 {
+		parallelism = DefaultFootageParallelism
+	}
+	d := &footageDownload{
+		client:      c.client,
+		jwt:         &streamingJWT{source: c.client.StreamingTokens, given: jwt, last: jwt},
+		parallelism: parallelism,
+	}
+	// footagePlaylistURL has already checked that the streaming URL parses
+	if u, err := neturl.Parse(c.client.streamingURL); err == nil {
+		d.host = u.Host
+	}
+	return d
+}
+
+// The streaming JWT of a footage download: the JWT given by the caller until it is rejected, then that of the
+// Client's StreamingTokens, which replaces it when it is about to expire or is rejected.
+type streamingJWT struct {
+	source *StreamingTokenSource
+
+	mu    sync.Mutex
+	given string
+	// last JWT handed out, to count replacements
+	last      string
+	refreshes int
+}
+
+// Returns a JWT that is not about to expire.
+func (t *streamingJWT) get(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	given := t.given
+	t.mu.Unlock()
+	if given != "" {
+		return given, nil
+	}
+	jwt, err := t.source.JWT(ctx)
+	if err != nil {
+		return "", err
+	}
+	t.observe(ctx, jwt)
+	return jwt, nil
+}
+
+// Replaces a rejected JWT, unless a concurrent request has already replaced it.
+func (t *streamingJWT) replace(ctx context.Context, rejected string) error {
+	t.mu.Lock()
+	if t.given != "" && t.given == rejected {
+		t.given = ""
+		t.mu.Unlock()
+		_, err := t.get(ctx)
+		return err
+	}
+	t.mu.Unlock()
+	jwt, err := t.source.Refresh(ctx, rejected)
+	if err != nil {
+		return err
+	}
+	t.observe(ctx, jwt)
+	return nil
+}
+
+// Counts a replacement if jwt differs from the last JWT handed out.
+func (t *streamingJWT) observe(ctx context.Context, jwt string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.last != "" && t.last != jwt {
+		t.refreshes++
+		t.source.camera.client.logger.InfoContext(ctx, "verkada streaming token replaced", "refreshes", t.refreshes)
+	}
+	t.last = jwt
+}
+
+// Returns the number of times the JWT was replaced.
+func (t *streamingJWT) replaced() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.refreshes
+}
+
+// The state of a DownloadFootage call.
+type footageDownload struct {
+	client *Client
+	jwt    *streamingJWT
+	// host of the streaming URL; only requests to it are sent the JWT
+	host        string
+	parallelism int
+
+	// what has been written, so that segments listed by consecutive playlists (as at the boundary of two export
+	// windows) are written once
+	// end of the last segment written, if the playlist has EXT-X-PROGRAM-DATE-TIME tags
+	lastEnd time.Time
+	// segments of the previous playlist without a program date-time, by URI without the jwt parameter
+	lastKeys map[string]bool
+	// URI and SHA-256 of the last initialization section written
+	mapURI  string
+	initSum string
+}
+
+// Fetches a playlist or segment, setting the jwt query parameter for the streaming host and replacing the JWT once
+// if it is rejected. URIs on other hosts, such as a CDN, are fetched as is with sendLink, without the auth token or
+// the JWT. accept is passed to sendDownload or sendLink; the body is limited to limit bytes if limit is positive.
+func (d *footageDownload) fetch(ctx context.Context, u *neturl.URL, accept []string, limit int64) ([]byte, error) {
+	values := u.Query()
+	base := *u
+	base.RawQuery = ""
+	base.Fragment = ""
+	replaced := false
+	for {
+		jwt := ""
+		var res *http.Response
+		var err error
+		if u.Host == d.host {
+			if jwt, err = d.jwt.get(ctx); err != nil {
+				return nil, err
+			}
+			values.Set("jwt", jwt)
+			res, err = d.client.sendDownload(ctx, http.MethodGet, base.String(), values, accept, 0)
+		} else {
+			res, err = d.client.sendLink(ctx, u.String(), accept)
+		}
+		var apiErr *APIError
+		if jwt != "" && !replaced && errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			// most likely an expired JWT: replace it and try again once
+			replaced = true
+			if err := d.jwt.replace(ctx, jwt); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var body io.Reader = res.Body
+		if limit > 0 {
+			body = io.LimitReader(res.Body, limit+1)
+		}
+		data, err := io.ReadAll(body)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", base.String(), err)
+		}
+		if limit > 0 && int64(len(data)) > limit {
+			return nil, fmt.Errorf("%s is larger than %d bytes", base.String(), limit)
+		}
+		return data, nil
+	}
+}
+
+// Fetches the playlist at u and follows master playlists to the highest-bandwidth variant's media playlist.
+func (d *footageDownload) mediaPlaylist(ctx context.Context, u *neturl.URL) (*hlsPlaylist, error) {
+	for range 4 {
+		data, err := d.fetch(ctx, u, playlistContentTypes, maxPlaylistSize)
+		if err != nil {
+			return nil, err
+		}
+		p, err := parsePlaylist(data, u)
+		if err != nil {
+			return nil, err
+		}
+		if len(p.variants) == 0 {
+			return p, nil
+		}
+		best := p.variants[0]
+		for _, v := range p.variants[1:] {
+			if v.bandwidth > best.bandwidth {
+				best = v
+			}
+		}
+		u = best.uri
+	}
+	return nil, fmt.Errorf("master playlists are nested too deeply")
+}
+
+// A downloaded segment, or the error downloading it.
+type segmentResult struct {
+	data []byte
+	err  error
+}
+
+// Downloads the segments of a media playlist concurrently and writes them to w in order, skipping segments already
+// written from the previous playlist.
+func (d *footageDownload) writeSegments(ctx context.Context, p *hlsPlaylist, w io.Writer, ret *DownloadFootageResponse) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	segments := d.newSegments(p)
+	// segments with a program date-time are compared by time instead
+	keys := map[string]bool{}
+	for _, seg := range p.segments {
+		if seg.start.IsZero() {
+			keys[segmentKey(seg.uri)] = true
+		}
+	}
+	results := make([]chan segmentResult, len(segments))
+	for i := range results {
+		results[i] = make(chan segmentResult, 1)
+	}
+	// a slot is taken before fetching a segment and given back once the segment is written, bounding memory
+	slots := make(chan struct{}, d.parallelism)
+	go func() {
+		for i, seg := range segments {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				data, err := d.fetch(ctx, seg.uri, nil, 0)
+				if err != nil {
+					err = fmt.Errorf("segment %d of %d: %w", i+1, len(segments), err)
+				}
+				results[i] <- segmentResult{data, err}
+			}()
+		}
+	}()
+
+	for i, seg := range segments {
+		var r segmentResult
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-slots
+		if r.err != nil {
+			return r.err
+		}
+		if seg.mapURI != nil && seg.mapURI.String() != d.mapURI {
+			if err := d.writeInit(ctx, seg.mapURI, w, ret); err != nil {
+				return err
+			}
+		}
+		n, err := w.Write(r.data)
+		ret.Bytes += int64(n)
+		if err != nil {
+			return err
+		}
+		ret.Segments++
+		ret.Duration += seg.duration
+		if !seg.start.IsZero() {
+			d.lastEnd = seg.start.Add(seg.duration)
+		}
+	}
+	d.lastKeys = keys
+	return nil
+}
+
+// Writes a fragmented MP4 initialization section before the first segment that uses it, unless it is identical to
+// the last one written (as when consecutive playlists name the same section differently).
+func (d *footageDownload) writeInit(ctx context.Context, u *neturl.URL, w io.Writer, ret *DownloadFootageResponse) error {
+	data, err := d.fetch(ctx, u, nil, 0)
+	if err != nil {
+		return fmt.Errorf("initialization section: %w", err)
+	}
+	d.mapURI = u.String()
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) == d.initSum {
+		return nil
+	}
+	n, err := w.Write(data)
+	ret.Bytes += int64(n)
+	if err != nil {
+		return err
+	}
+	d.initSum = hex.EncodeToString(sum[:])
+	return nil
+}
+
+// Returns the segments of p that were not written from the previous playlist. Segments are compared by their
+// EXT-X-PROGRAM-DATE-TIME when the playlist has one, so that a segment spanning the boundary of two windows is only
+// written once, and otherwise by URI.
+func (d *footageDownload) newSegments(p *hlsPlaylist) []hlsSegment {
+	var segments []hlsSegment
+	for _, seg := range p.segments {
+		switch {
+		case !seg.start.IsZero() && !d.lastEnd.IsZero():
+			if seg.start.Before(d.lastEnd.Add(-segmentTimeTolerance)) {
+				continue
+			}
+		case d.lastKeys[segmentKey(seg.uri)]:
+			continue
+		}
+		segments = append(segments, seg)
+	}
+	return segments
+}
+
+// Slack allowed when comparing segment times, which playlists round to milliseconds or less.
+const segmentTimeTolerance = 100 * time.Millisecond
+
+// Returns the URI of a segment without its jwt parameter, which changes when the JWT is replaced.
+func segmentKey(u *neturl.URL) string {
+	key := *u
+	values := key.Query()
+	values.Del("jwt")
+	key.RawQuery = values.Encode()
+	key.Fragment = ""
+	return key.String()
+}
+
+// A parsed HLS playlist: a master playlist has variants, a media playlist has segments.
+type hlsPlaylist struct {
+	variants []hlsVariant
+	segments []hlsSegment
+}
+
+type hlsVariant struct {
+	uri       *neturl.URL
+	bandwidth int
+}
+
+type hlsSegment struct {
+	uri      *neturl.URL
+	duration time.Duration
+	// start of the segment from EXT-X-PROGRAM-DATE-TIME, zero if the playlist has none
+	start time.Time
+	// initialization section (EXT-X-MAP) of fragmented MP4 segments
+	mapURI *neturl.URL
+}
+
+// Returns "mp4" if the segments are fragmented MP4 and "ts" otherwise.
+func (p *hlsPlaylist) format() string {
+	for _, seg := range p.segments {
+		switch path.Ext(seg.uri.Path) {
+		case ".mp4", ".m4s", ".m4v", ".cmfv":
+			return "mp4"
+		}
+		if seg.mapURI != nil {
+			return "mp4"
+		}
+	}
+	return "ts"
+}
+
+// Parses a master or media playlist, resolving its URIs against base.
+func parsePlaylist(data []byte, base *neturl.URL) (*hlsPlaylist, error) {
+	p := &hlsPlaylist{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPlaylistSize)
+	first := true
+	var variant *hlsVariant
+	var segment *hlsSegment
+	var mapURI *neturl.URL
+	// program date-time of the next segment, carried forward by segment durations
+	var next time.Time
+	resolve := func(ref string) (*neturl.URL, error) {
+		u, err := neturl.Parse(ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid playlist URI %q: %w", ref, err)
+		}
+		return base.ResolveReference(u), nil
+	}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if first {
+			if !strings.HasPrefix(line, "#EXTM3U") {
+				return nil, fmt.Errorf("not an HLS playlist: %q", truncate(line, 64))
+			}
+			first = false
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		tag, value, _ := strings.Cut(line, ":")
+		switch {
+		case tag == "#EXT-X-STREAM-INF":
+			variant = &hlsVariant{}
+			variant.bandwidth, _ = strconv.Atoi(playlistAttributes(value)["BANDWIDTH"])
+		case tag == "#EXTINF":
+			seconds, _, _ := strings.Cut(value, ",")
+			f, err := strconv.ParseFloat(strings.TrimSpace(seconds), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid #EXTINF duration %q", seconds)
+			}
+			segment = &hlsSegment{duration: time.Duration(f * float64(time.Second)), mapURI: mapURI}
+		case tag == "#EXT-X-MAP":
+			attrs := playlistAttributes(value)
+			if attrs["BYTERANGE"] != "" {
+				return nil, fmt.Errorf("byte-range initialization sections are not supported")
+			}
+			u, err := resolve(attrs["URI"])
+			if err != nil {
+				return nil, err
+			}
+			mapURI = u
+		case tag == "#EXT-X-PROGRAM-DATE-TIME":
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				t, err = time.Parse("2006-01-02T15:04:05.999999999Z0700", value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid #EXT-X-PROGRAM-DATE-TIME %q", value)
+			}
+			next = t
+		case tag == "#EXT-X-KEY":
+			if method := playlistAttributes(value)["METHOD"]; method != "NONE" {
+				return nil, fmt.Errorf("encrypted playlists are not supported (METHOD=%s)", method)
+			}
+		case tag == "#EXT-X-BYTERANGE":
+			return nil, fmt.Errorf("byte-range segments are not supported")
+		case strings.HasPrefix(line, "#"):
+			// other tags and comments do not affect the download
+		case variant != nil:
+			u, err := resolve(line)
+			if err != nil {
+				return nil, err
+			}
+			variant.uri = u
+			p.variants = append(p.variants, *variant)
+			variant = nil
+		default:
+			u, err := resolve(line)
+			if err != nil {
+				return nil, err
+			}
+			if segment == nil {
+				segment = &hlsSegment{mapURI: mapURI}
+			}
+			segment.uri = u
+			if !next.IsZero() {
+				segment.start = next
+				next = next.Add(segment.duration)
+			}
+			p.segments = append(p.segments, *segment)
+			segment = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if first {
+		return nil, fmt.Errorf("empty HLS playlist")
+	}
+	return p, nil
+}
+
+// Parses an attribute list such as BANDWIDTH=1280000,CODECS="avc1.4d401f,mp4a.40.2", unquoting quoted values.
+func playlistAttributes(s string) map[string]string {
+	attrs := map[string]string{}
+	for s != "" {
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, "\"") {
+			end := strings.Index(rest[1:], "\"")
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+			rest = strings.TrimPrefix(rest, ",")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		attrs[strings.TrimSpace(name)] = value
+		s = rest
+	}
+	return attrs
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n] + "..."
+	}
+	return s
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownloadFootageForeignHost(t *testing.T) {
+	var mu sync.Mutex
+	var cdnRequests []*http.Request
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cdnRequests = append(cdnRequests, r)
+		mu.Unlock()
+		if r.URL.Query().Get("sig") != "cdn-signature" {
+			http.Error(w, "bad signature", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "video/mp2t")
+		fmt.Fprintf(w, "[cdn %s]", r.URL.Path)
+	}))
+	t.Cleanup(cdn.Close)
+	tests := []struct {
+		name string
+		// signature of the CDN segment
+		sig     string
+		want    string
+		wantErr string
+	}{
+		{"signed", "cdn-signature", "[api segment_0.ts][cdn /segment_1.ts]", ""},
+		// the CDN's signature is left out of the error
+		{"rejected", "wrong", "[api segment_0.ts]", "GET " + cdn.URL + "/segment_1.ts:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdnRequests = nil
+			var apiRequests []*http.Request
+			c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				apiRequests = append(apiRequests, r)
+				if r.URL.Query().Get("jwt") != "streaming-jwt" {
+					http.Error(w, `{"message": "bad jwt"}`, http.StatusUnauthorized)
+					return
+				}
+				switch path := r.URL.Path; {
+				case strings.HasSuffix(path, ".m3u8"):
+					w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+					fmt.Fprintf(w, "#EXTM3U\n#EXTINF:2.0,\nsegment_0.ts\n#EXTINF:2.0,\n%s/segment_1.ts?sig=%s\n#EXT-X-ENDLIST\n", cdn.URL, tt.sig)
+				default:
+					w.Header().Set("Content-Type", "video/mp2t")
+					fmt.Fprintf(w, "[api %s]", path[strings.LastIndex(path, "/")+1:])
+				}
+			}, nil)
+			var buf bytes.Buffer
+			_, err := c.Camera.DownloadFootage("org-1", "cam-1", &DownloadFootageOptions{Start_time: Int(1000), End_time: Int(1060), Jwt: "streaming-jwt", Parallelism: 1}, &buf)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("got %v, want %q", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), tt.sig) {
+				t.Errorf("error %q includes the signature", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("wrote %q, want %q", buf.String(), tt.want)
+			}
+			if len(apiRequests) != 2 || len(cdnRequests) != 1 {
+				t.Fatalf("sent %d requests to the API and %d to the CDN", len(apiRequests), len(cdnRequests))
+			}
+			for _, r := range apiRequests {
+				if r.Header.Get("x-verkada-auth") == "" {
+					t.Errorf("API request %s has no auth token", r.URL.Path)
+				}
+			}
+			r := cdnRequests[0]
+			if r.Header.Get("x-verkada-auth") != "" || r.Header.Get("x-api-key") != "" || r.URL.Query().Has("jwt") {
+				t.Errorf("CDN request %s was sent credentials: %v", r.URL, r.Header)
+			}
+		})
+	}
+}
+
+// Returns a handler serving footage as 10 second segments numbered by their start (seg_100.ts starts at 1000), listing
+// every segment that overlaps the requested range, so that consecutive windows share the segment at their boundary.
+// Segments in fail return 500 once.
+func footageHandler(programDateTime bool, fail ...string) http.HandlerFunc {
+	var mu sync.Mutex
+	failing := map[string]bool{}
+	for _, name := range fail {
+		failing[name] = true
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if name == "stream.m3u8" {
+			start, _ := strconv.Atoi(r.URL.Query().Get("start_time"))
+			end, _ := strconv.Atoi(r.URL.Query().Get("end_time"))
+			w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+			fmt.Fprint(w, "#EXTM3U\n#EXT-X-TARGETDURATION:10\n")
+			for n := start / 10; n*10 < end; n++ {
+				if programDateTime {
+					fmt.Fprintf(w, "#EXT-X-PROGRAM-DATE-TIME:%s\n", time.Unix(int64(n*10), 0).UTC().Format(time.RFC3339))
+				}
+				fmt.Fprintf(w, "#EXTINF:10.0,\nseg_%d.ts\n", n)
+			}
+			fmt.Fprint(w, "#EXT-X-ENDLIST\n")
+			return
+		}
+		mu.Lock()
+		failed := failing[name]
+		delete(failing, name)
+		mu.Unlock()
+		if failed {
+			http.Error(w, `{"message": "segment unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "video/mp2t")
+		fmt.Fprintf(w, "[%s]", strings.TrimSuffix(name, ".ts"))
+	}
+}
+
+func TestExportFootageResume(t *testing.T) {
+	// segments 100 to 109, each written once
+	var want strings.Builder
+	for n := 100; n < 110; n++ {
+		fmt.Fprintf(&want, "[seg_%d]", n)
+	}
+	tests := []struct {
+		name            string
+		programDateTime bool
+	}{
+		{"segments compared by program date-time", true},
+		{"segments compared by URI", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// windows of 25s: 1000-1025 lists segments 100-102, 1025-1050 102-104, 1050-1075 105-107, and 1075-1100
+			// 107-109; seg_108 fails once, after seg_107 of the last window was skipped and nothing else written
+			c, _ := newTestClient(t, footageHandler(tt.programDateTime, "seg_108.ts"), nil)
+			filename := filepath.Join(t.TempDir(), "export.ts")
+			options := &ExportFootageOptions{Start_time: Int(1000), End_time: Int(1100), Window: 25, Jwt: "streaming-jwt", Parallelism: 1}
+
+			ret, err := c.Camera.ExportFootage("org-1", "cam-1", options, filename)
+			if !errors.Is(err, ErrServer) {
+				t.Fatalf("got %v, want the failure of seg_108", err)
+			}
+			if ret.Windows != 3 || ret.Segments != 8 || ret.Format != "ts" {
+				t.Errorf("first run reported %d windows and %d segments (%s)", ret.Windows, ret.Segments, ret.Format)
+			}
+			if _, err := os.Stat(filename + ".checkpoint"); err != nil {
+				t.Fatalf("no checkpoint: %v", err)
+			}
+
+			ret, err = c.Camera.ExportFootage("org-1", "cam-1", options, filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ret.ResumedFrom != 1075 || ret.Windows != 1 || ret.Segments != 10 || ret.Bytes != int64(want.Len()) || ret.Duration != 100*time.Second {
+				t.Errorf("resumed run reported %+v", ret)
+			}
+			got, _ := os.ReadFile(filename)
+			if string(got) != want.String() {
+				t.Errorf("exported %s\nwant     %s", got, want.String())
+			}
+			if _, err := os.Stat(filename + ".checkpoint"); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("checkpoint was not removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestExportFootageResumeDiscardsPartialWindow(t *testing.T) {
+	// seg_106 fails after seg_105 of the same window was written; resuming truncates it and writes the window again
+	c, _ := newTestClient(t, footageHandler(true, "seg_106.ts"), nil)
+	filename := filepath.Join(t.TempDir(), "export.ts")
+	options := &ExportFootageOptions{Start_time: Int(1000), End_time: Int(1100), Window: 25, Jwt: "streaming-jwt", Parallelism: 1}
+	if _, err := c.Camera.ExportFootage("org-1", "cam-1", options, filename); !errors.Is(err, ErrServer) {
+		t.Fatalf("got %v, want the failure of seg_106", err)
+	}
+	if got, _ := os.ReadFile(filename); !strings.HasSuffix(string(got), "[seg_104][seg_105]") {
+		t.Fatalf("first run wrote %s", got)
+	}
+	ret, err := c.Camera.ExportFootage("org-1", "cam-1", options, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filename)
+	if want := "[seg_100][seg_101][seg_102][seg_103][seg_104][seg_105][seg_106][seg_107][seg_108][seg_109]"; string(got) != want {
+		t.Errorf("exported %s\nwant     %s", got, want)
+	}
+	if ret.ResumedFrom != 1050 || ret.Windows != 2 {
+		t.Errorf("resumed run reported %+v", ret)
+	}
+}
+
+func TestExportFootageCheckpointMismatch(t *testing.T) {
+	c, _ := newTestClient(t, footageHandler(true, "seg_104.ts"), nil)
+	filename := filepath.Join(t.TempDir(), "export.ts")
+	options := &ExportFootageOptions{Start_time: Int(1000), End_time: Int(1100), Window: 25, Jwt: "streaming-jwt"}
+	c.Camera.ExportFootage("org-1", "cam-1", options, filename)
+	if _, err := c.Camera.ExportFootage("org-1", "cam-2", options, filename); err == nil || !strings.Contains(err.Error(), "different export") {
+		t.Errorf("got %v for a checkpoint of another camera", err)
+	}
+}
@@ -0,0 +1,429 @@
+// Package hlsproxy relays live HLS streams of Verkada cameras to viewers that cannot hold a streaming JWT, such as a
+// video wall on an internal network.
+//
+// A Proxy is an http.Handler serving {camera_id}/live.m3u8 relative to the path it is mounted on. It makes every
+// request through a client.Client, taking streaming JWTs from its StreamingTokens, which replaces them before they
+// expire or as soon as they are rejected, so viewers never see one.
+// Playlists are rewritten so that variant playlists, segments, and init sections are requested through the proxy too,
+// and segments are cached, so that any number of viewers of a camera cost a single download from Verkada.
+// Every request is checked with the Authorize callback before anything is fetched.
+//
+//	c, err := client.New(&client.ClientOptions{Region: "prod1", APIKey: key})
+//	p, err := hlsproxy.New(&hlsproxy.Options{
+//		Client: c,
+//		OrgID:  orgID,
+//		Authorize: func(r *http.Request, camera_id string) error {
+//			return checkWallAccess(r, camera_id)
+//		},
+//	})
+//	http.Handle("/live/", http.StripPrefix("/live", p))
+//
+// A player then opens /live/{camera_id}/live.m3u8. To test against verkadatest, pass a Client from its NewClient.
+package hlsproxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client"
+)
+
+const (
+	// Number of segments and playlists kept by the client.LRUCache used when Options.Cache is nil.
+	DefaultCacheEntries = 200
+	// How long segments are cached when Options.SegmentTTL is zero, long enough for every viewer of a live stream.
+	DefaultSegmentTTL = 2 * time.Minute
+	// How long rewritten playlists are cached when Options.PlaylistTTL is zero, shorter than a segment so that viewers
+	// polling a live playlist see new segments promptly.
+	DefaultPlaylistTTL = time.Second
+	// Time limit of each request to the Streaming API, including retries, when Options.UpstreamTimeout is zero.
+	DefaultUpstreamTimeout = 30 * time.Second
+)
+
+const (
+	// paths served below {camera_id}/
+	livePlaylist    = "live.m3u8"
+	variantPlaylist = "playlist.m3u8"
+	segmentPath     = "segment"
+	maxPlaylistSize = 16 << 20
+	maxSegmentSize  = 256 << 20
+)
+
+// Options for a Proxy.
+type Options struct {
+	// Client used for every request to Verkada, with its RetryPolicy, RateLimiter, Middleware, and Logger. Streaming
+	// JWTs come from its StreamingTokens. Required.
+	Client *client.Client
+	// Organization the cameras belong to. Required.
+	OrgID string
+	// "low_res" or "high_res"; the API defaults to low_res.
+	Resolution string
+	// Decides whether the viewer making r may watch camera_id. It is called for every playlist and segment request,
+	// and a non-nil error is answered with 403 Forbidden. Required.
+	Authorize func(r *http.Request, camera_id string) error
+	// Where segments and rewritten playlists are kept; a client.LRUCache of DefaultCacheEntries if nil.
+	Cache client.CacheStore
+	// How long segments and playlists are cached; DefaultSegmentTTL and DefaultPlaylistTTL if zero.
+	SegmentTTL  time.Duration
+	PlaylistTTL time.Duration
+	// Time limit of each request to the Streaming API; DefaultUpstreamTimeout if zero.
+	UpstreamTimeout time.Duration
+	// Logger for denied viewers and failed requests; nothing is logged if nil.
+	Logger *slog.Logger
+}
+
+// A Proxy serves live HLS streams of the cameras of one organization. It is safe for concurrent use.
+type Proxy struct {
+	client      *client.Client
+	streaming   *neturl.URL
+	orgID       string
+	resolution  string
+	authorize   func(r *http.Request, camera_id string) error
+	cache       client.CacheStore
+	segmentTTL  time.Duration
+	playlistTTL time.Duration
+	timeout     time.Duration
+	logger      *slog.Logger
+	// signs the references to upstream URIs in rewritten playlists, so that viewers can only request URIs the proxy
+	// listed for a camera they are authorized for
+	key []byte
+
+	flightMu sync.Mutex
+	flights  map[string]*flight
+}
+
+// An upstream request shared by every viewer asking for the same URI while it is in progress.
+type flight struct {
+	done  chan struct{}
+	entry *client.CacheEntry
+	err   error
+}
+
+// Returns a Proxy, or an error if options are missing.
+func New(options *Options) (*Proxy, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	if options.Client == nil {
+		return nil, errors.New("hlsproxy: Client is required")
+	}
+	if options.OrgID == "" {
+		return nil, errors.New("hlsproxy: OrgID is required")
+	}
+	if options.Authorize == nil {
+		return nil, errors.New("hlsproxy: Authorize is required")
+	}
+	streaming, err := neturl.Parse(options.Client.StreamingBaseURL())
+	if err != nil {
+		return nil, fmt.Errorf("hlsproxy: invalid streaming base URL: %w", err)
+	}
+	p := &Proxy{
+		client:      options.Client,
+		streaming:   streaming,
+		orgID:       options.OrgID,
+		resolution:  options.Resolution,
+		authorize:   options.Authorize,
+		cache:       options.Cache,
+		segmentTTL:  options.SegmentTTL,
+		playlistTTL: options.PlaylistTTL,
+		timeout:     options.UpstreamTimeout,
+		logger:      options.Logger,
+		key:         make([]byte, 32),
+		flights:     map[string]*flight{},
+	}
+	if p.cache == nil {
+		p.cache = client.NewLRUCache(DefaultCacheEntries)
+	}
+	if p.segmentTTL == 0 {
+		p.segmentTTL = DefaultSegmentTTL
+	}
+	if p.playlistTTL == 0 {
+		p.playlistTTL = DefaultPlaylistTTL
+	}
+	if p.timeout == 0 {
+		p.timeout = DefaultUpstreamTimeout
+	}
+	if p.logger == nil {
+		p.logger = slog.New(slog.DiscardHandler)
+	}
+	rand.Read(p.key)
+	return p, nil
+}
+
+// Serves GET and HEAD requests for {camera_id}/live.m3u8 and the playlists and segments it refers to.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	camera_id, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if !ok || camera_id == "" || (name != livePlaylist && name != variantPlaylist && name != segmentPath) {
+		http.NotFound(w, r)
+		return
+	}
+	if err := p.authorize(r, camera_id); err != nil {
+		p.logger.DebugContext(r.Context(), "hlsproxy viewer not authorized", "camera_id", camera_id, "error", err)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	var upstream *neturl.URL
+	if name == livePlaylist {
+		upstream = p.liveURL(camera_id)
+	} else {
+		var err error
+		if upstream, err = p.verify(camera_id, r.URL.Query()); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+	}
+	playlist := name != segmentPath
+	entry, err := p.get(r.Context(), camera_id, upstream, playlist)
+	if err != nil {
+		p.writeError(w, r, camera_id, err)
+		return
+	}
+	w.Header().Set("Content-Type", entry.Header.Get("Content-Type"))
+	w.Header().Set("Content-Length", strconv.Itoa(len(entry.Body)))
+	if playlist {
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(p.segmentTTL/time.Second)))
+	}
+	w.Write(entry.Body)
+}
+
+// Returns the URL of the live playlist of a camera, without the streaming JWT.
+func (p *Proxy) liveURL(camera_id string) *neturl.URL {
+	u := p.streaming.JoinPath("/stream/cameras/v1/footage/stream/stream.m3u8")
+	query := neturl.Values{"org_id": {p.orgID}, "camera_id": {camera_id}}
+	if p.resolution != "" {
+		query.Set("resolution", p.resolution)
+	}
+	u.RawQuery = query.Encode()
+	return u
+}
+
+// Returns the upstream URI referred to by the query of a rewritten URI, if its signature is valid for camera_id.
+func (p *Proxy) verify(camera_id string, query neturl.Values) (*neturl.URL, error) {
+	ref, sig := query.Get("u"), query.Get("s")
+	if ref == "" || !hmac.Equal([]byte(sig), []byte(p.sign(camera_id, ref))) {
+		return nil, errors.New("invalid reference")
+	}
+	return neturl.Parse(ref)
+}
+
+func (p *Proxy) sign(camera_id string, ref string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(camera_id))
+	mac.Write([]byte{0})
+	mac.Write([]byte(ref))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Returns a cached response for an upstream URI, or fetches it once for every viewer waiting on it.
+func (p *Proxy) get(ctx context.Context, camera_id string, upstream *neturl.URL, playlist bool) (*client.CacheEntry, error) {
+	key := "hlsproxy " + camera_id + " " + upstream.String()
+	if entry, ok := p.cache.Get(key); ok && time.Now().Before(entry.Expires) {
+		return entry, nil
+	}
+	p.flightMu.Lock()
+	f, ok := p.flights[key]
+	if !ok {
+		f = &flight{done: make(chan struct{})}
+		p.flights[key] = f
+		// detached from ctx: other viewers may be waiting even if this one goes away
+		go func() {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.timeout)
+			defer cancel()
+			f.entry, f.err = p.fetch(ctx, camera_id, upstream, playlist)
+			if f.err == nil {
+				p.cache.Set(key, f.entry)
+			}
+			p.flightMu.Lock()
+			delete(p.flights, key)
+			p.flightMu.Unlock()
+			close(f.done)
+		}()
+	}
+	p.flightMu.Unlock()
+	select {
+	case <-f.done:
+		return f.entry, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Downloads an upstream URI, rewriting it if it is a playlist.
+func (p *Proxy) fetch(ctx context.Context, camera_id string, upstream *neturl.URL, playlist bool) (*client.CacheEntry, error) {
+	limit, ttl := int64(maxSegmentSize), p.segmentTTL
+	if playlist {
+		limit, ttl = maxPlaylistSize, p.playlistTTL
+	}
+	body, contentType, err := p.download(ctx, camera_id, upstream, limit)
+	if err != nil {
+		return nil, err
+	}
+	if playlist {
+		if body, err = p.rewrite(camera_id, upstream, body); err != nil {
+			return nil, err
+		}
+		contentType = "application/vnd.apple.mpegurl"
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	now := time.Now()
+	return &client.CacheEntry{Body: body, Header: http.Header{"Content-Type": {contentType}}, Stored: now, Expires: now.Add(ttl)}, nil
+}
+
+// A buffer that fails writes beyond limit bytes.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int64
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if int64(b.Len()+len(data)) > b.limit {
+		return 0, fmt.Errorf("response larger than %d bytes", b.limit)
+	}
+	return b.Buffer.Write(data)
+}
+
+// Downloads an upstream URI. URIs on the Streaming API host are requested through the Client with the streaming JWT
+// of camera_id, which is replaced once if it is rejected; URIs on other hosts, such as a CDN, are fetched as listed
+// with MakeLinkRequestToWriterContext, without the JWT or the auth token.
+func (p *Proxy) download(ctx context.Context, camera_id string, upstream *neturl.URL, limit int64) ([]byte, string, error) {
+	if upstream.Host != p.streaming.Host {
+		buf := &limitedBuffer{limit: limit}
+		contentType, _, err := p.client.MakeLinkRequestToWriterContext(ctx, upstream.String(), buf, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), contentType, nil
+	}
+	values := upstream.Query()
+	base := *upstream
+	base.RawQuery = ""
+	base.Fragment = ""
+	jwt, err := p.client.StreamingTokens.JWTForCamera(ctx, camera_id, client.StreamingPermissionLive)
+	if err != nil {
+		return nil, "", err
+	}
+	for replaced := false; ; replaced = true {
+		values.Set("jwt", jwt)
+		buf := &limitedBuffer{limit: limit}
+		contentType, _, err := p.client.MakeVerkadaRequestToWriterContext(ctx, http.MethodGet, base.String(), values, buf, nil, 0)
+		var apiErr *client.APIError
+		if !replaced && errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			// most likely an expired JWT: replace it and try again once
+			if jwt, err = p.client.StreamingTokens.Refresh(ctx, jwt); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), contentType, nil
+	}
+}
+
+// Answers a failed upstream request: 404s are passed on, cameras outside the scope of the streaming JWT are 403
+// Forbidden, and anything else is a 502 Bad Gateway (504 on timeouts).
+func (p *Proxy) writeError(w http.ResponseWriter, r *http.Request, camera_id string, err error) {
+	if r.Context().Err() != nil {
+		// the viewer went away
+		return
+	}
+	p.logger.WarnContext(r.Context(), "hlsproxy upstream request failed", "camera_id", camera_id, "error", err)
+	var scope *client.StreamingScopeError
+	switch {
+	case errors.As(err, &scope):
+		// the API key cannot stream this camera
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, client.ErrNotFound):
+		http.NotFound(w, r)
+	case errors.Is(err, context.DeadlineExceeded):
+		http.Error(w, "upstream timeout", http.StatusGatewayTimeout)
+	default:
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}
+}
+
+// Rewrites every URI of a playlist to a signed reference served by the proxy: variant and rendition playlists to
+// playlist.m3u8, and segments, init sections, and keys to segment. The references are relative, so they resolve
+// against {camera_id}/ wherever the proxy is mounted.
+func (p *Proxy) rewrite(camera_id string, base *neturl.URL, body []byte) ([]byte, error) {
+	master := bytes.Contains(body, []byte("#EXT-X-STREAM-INF"))
+	lines := strings.Split(string(body), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#"):
+			start := strings.Index(line, `URI="`)
+			if start < 0 {
+				break
+			}
+			start += len(`URI="`)
+			end := strings.IndexByte(line[start:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("hlsproxy: unterminated URI attribute in playlist: %q", line)
+			}
+			tag, _, _ := strings.Cut(line, ":")
+			ref, err := p.reference(camera_id, base, line[start:start+end], playlistTags[tag])
+			if err != nil {
+				return nil, err
+			}
+			line = line[:start] + ref + line[start+end:]
+		default:
+			ref, err := p.reference(camera_id, base, line, master)
+			if err != nil {
+				return nil, err
+			}
+			line = ref
+		}
+		lines[i] = line
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// Tags whose URI attribute is a playlist rather than media.
+var playlistTags = map[string]bool{
+	"#EXT-X-MEDIA":              true,
+	"#EXT-X-I-FRAME-STREAM-INF": true,
+	"#EXT-X-RENDITION-REPORT":   true,
+}
+
+// Returns the proxy reference to a URI of a playlist, resolved against the playlist's URL without any JWT.
+func (p *Proxy) reference(camera_id string, base *neturl.URL, uri string, playlist bool) (string, error) {
+	u, err := base.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("hlsproxy: invalid URI in playlist: %w", err)
+	}
+	if query := u.Query(); query.Has("jwt") {
+		query.Del("jwt")
+		u.RawQuery = query.Encode()
+	}
+	ref := u.String()
+	name := segmentPath
+	if playlist {
+		name = variantPlaylist
+	}
+	return name + "?" + neturl.Values{"u": {ref}, "s": {p.sign(camera_id, ref)}}.Encode(), nil
+}
@@ -0,0 +1,232 @@
+package hlsproxy_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strings"
+	"testing"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client"
+	"github.com/GDRCode/verkada-api-go/pkg/client/hlsproxy"
+	"github.com/GDRCode/verkada-api-go/pkg/client/verkadatest"
+)
+
+const segmentPath = "/stream/cameras/v1/footage/stream/segment_0.ts"
+
+// Returns a verkadatest Server and a Proxy for it that authorizes every camera but cam-denied.
+func newProxy(t *testing.T) (*hlsproxy.Proxy, *verkadatest.Server) {
+	t.Helper()
+	srv := verkadatest.NewServer()
+	t.Cleanup(srv.Close)
+	c, err := srv.NewClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := hlsproxy.New(&hlsproxy.Options{
+		Client: c,
+		OrgID:  "org-1",
+		Authorize: func(r *http.Request, camera_id string) error {
+			if camera_id == "cam-denied" {
+				return errors.New("not on this wall")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, srv
+}
+
+// Makes a viewer's request to p for path, relative to where it is mounted.
+func get(p *hlsproxy.Proxy, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+// Returns the URIs of a playlist.
+func uris(playlist string) []string {
+	var ret []string
+	for _, line := range strings.Split(playlist, "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			ret = append(ret, line)
+		}
+	}
+	return ret
+}
+
+// Counts the requests received by srv for path.
+func requestsTo(srv *verkadatest.Server, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRewrite(t *testing.T) {
+	p, srv := newProxy(t)
+	w := get(p, "/cam-1/live.m3u8")
+	if w.Code != http.StatusOK {
+		t.Fatalf("live playlist: %d %s", w.Code, w.Body)
+	}
+	playlist := w.Body.String()
+	if strings.Contains(playlist, "jwt") {
+		t.Errorf("rewritten playlist includes the streaming JWT:\n%s", playlist)
+	}
+	refs := uris(playlist)
+	if len(refs) != 2 {
+		t.Fatalf("rewritten playlist lists %d URIs:\n%s", len(refs), playlist)
+	}
+	for i, ref := range refs {
+		u, err := neturl.Parse(ref)
+		if err != nil || u.Path != "segment" || !strings.HasSuffix(u.Query().Get("u"), fmt.Sprintf("/segment_%d.ts", i)) {
+			t.Errorf("URI %d is %q, want a signed reference to segment_%d.ts", i, ref, i)
+			continue
+		}
+		w := get(p, "/cam-1/"+ref)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "video/mp2t" || w.Body.Len() != 188 {
+			t.Errorf("segment %d: %d %s, %d bytes", i, w.Code, w.Header().Get("Content-Type"), w.Body.Len())
+		}
+	}
+	// the JWT was sent upstream, and the live playlist was requested for the camera and org
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r.Path, "/stream/") && r.Query.Get("jwt") == "" {
+			t.Errorf("request for %s has no streaming JWT", r.Path)
+		}
+		if strings.HasSuffix(r.Path, "stream.m3u8") && (r.Query.Get("camera_id") != "cam-1" || r.Query.Get("org_id") != "org-1") {
+			t.Errorf("live playlist requested with %v", r.Query)
+		}
+	}
+}
+
+func TestRejectedRequests(t *testing.T) {
+	p, srv := newProxy(t)
+	srv.SetStreamingScope([]string{"cam-1", "cam-2", "cam-denied"}, nil, []string{client.StreamingPermissionLive})
+	ref := uris(get(p, "/cam-1/live.m3u8").Body.String())[0]
+	query, _ := neturl.ParseQuery(strings.TrimPrefix(ref, "segment?"))
+	tampered := func(u string, s string) string {
+		return "segment?" + neturl.Values{"u": {u}, "s": {s}}.Encode()
+	}
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"signed reference", "/cam-1/" + ref, http.StatusOK},
+		{"tampered signature", "/cam-1/" + tampered(query.Get("u"), query.Get("s")+"A"), http.StatusNotFound},
+		{"tampered URI", "/cam-1/" + tampered(strings.Replace(query.Get("u"), "segment_0", "segment_1", 1), query.Get("s")), http.StatusNotFound},
+		{"unsigned URI", "/cam-1/" + tampered(query.Get("u"), ""), http.StatusNotFound},
+		{"reference of another camera", "/cam-2/" + ref, http.StatusNotFound},
+		{"denied by Authorize", "/cam-denied/live.m3u8", http.StatusForbidden},
+		{"denied by Authorize with a signed reference", "/cam-denied/" + ref, http.StatusForbidden},
+		{"outside the scope of the streaming JWT", "/cam-3/live.m3u8", http.StatusForbidden},
+		{"unknown path", "/cam-1/other.m3u8", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(srv.Requests())
+			w := get(p, tt.path)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("got %d %s, want %d", w.Code, w.Body, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				for _, r := range srv.Requests()[before:] {
+					if strings.HasPrefix(r.Path, "/stream/") {
+						t.Errorf("rejected request was relayed to %s", r.Path)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSharedCache(t *testing.T) {
+	p, srv := newProxy(t)
+	for viewer := range 2 {
+		w := get(p, "/cam-1/live.m3u8")
+		if w.Code != http.StatusOK {
+			t.Fatalf("viewer %d: live playlist: %d %s", viewer, w.Code, w.Body)
+		}
+		if w := get(p, "/cam-1/"+uris(w.Body.String())[0]); w.Code != http.StatusOK {
+			t.Fatalf("viewer %d: segment: %d %s", viewer, w.Code, w.Body)
+		}
+	}
+	if n := requestsTo(srv, segmentPath); n != 1 {
+		t.Errorf("segment requested %d times from upstream, want 1", n)
+	}
+	if n := requestsTo(srv, "/stream/cameras/v1/footage/stream/stream.m3u8"); n != 1 {
+		t.Errorf("live playlist requested %d times from upstream, want 1", n)
+	}
+	if n := srv.StreamingTokenRequests(); n != 1 {
+		t.Errorf("requested %d streaming tokens, want 1", n)
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	p, srv := newProxy(t)
+	ref := uris(get(p, "/cam-1/live.m3u8").Body.String())[0]
+	srv.ExpireStreamingTokens()
+	w := get(p, "/cam-1/"+ref)
+	if w.Code != http.StatusOK {
+		t.Fatalf("segment after the JWT expired: %d %s", w.Code, w.Body)
+	}
+	if n := srv.StreamingTokenRequests(); n != 2 {
+		t.Errorf("requested %d streaming tokens, want 2", n)
+	}
+	var jwts []string
+	for _, r := range srv.Requests() {
+		if r.Path == segmentPath {
+			jwts = append(jwts, r.Query.Get("jwt"))
+		}
+	}
+	// the expired JWT until the Client's own retry of the 401 fails too, then the new one
+	if len(jwts) < 2 || jwts[0] != "verkadatest-streaming-jwt-1" || jwts[len(jwts)-1] != "verkadatest-streaming-jwt-2" {
+		t.Errorf("segment requested with JWTs %q", jwts)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	// upstream requests go through the Client, so its RetryPolicy applies
+	p, srv := newProxy(t)
+	srv.InjectFault(verkadatest.Fault{Path: segmentPath, StatusCode: http.StatusServiceUnavailable, Times: 1})
+	ref := uris(get(p, "/cam-1/live.m3u8").Body.String())[0]
+	if w := get(p, "/cam-1/"+ref); w.Code != http.StatusOK {
+		t.Fatalf("segment: %d %s", w.Code, w.Body)
+	}
+	srv.InjectFault(verkadatest.Fault{Path: "/stream/cameras/v1/footage/stream/segment_1.ts", StatusCode: http.StatusNotFound})
+	ref = uris(get(p, "/cam-1/live.m3u8").Body.String())[1]
+	if w := get(p, "/cam-1/"+ref); w.Code != http.StatusNotFound {
+		t.Errorf("missing segment: got %d, want 404", w.Code)
+	}
+}
+
+func TestCDNSegments(t *testing.T) {
+	// segments on another host are fetched with the Client's link requests: retried, and without credentials
+	p, srv := newProxy(t)
+	srv.ServeSegmentsFromCDN(true)
+	srv.InjectFault(verkadatest.Fault{Path: "/cdn/segment_0.ts", StatusCode: http.StatusBadGateway, Times: 1})
+	refs := uris(get(p, "/cam-1/live.m3u8").Body.String())
+	if len(refs) != 2 {
+		t.Fatalf("rewritten playlist lists %d URIs", len(refs))
+	}
+	for i, ref := range refs {
+		if w := get(p, "/cam-1/"+ref); w.Code != http.StatusOK || w.Body.Len() != 188 {
+			t.Errorf("segment %d: %d %s", i, w.Code, w.Body)
+		}
+	}
+	if n := requestsTo(srv, "/cdn/segment_0.ts"); n != 2 {
+		t.Errorf("requested the failing CDN segment %d times, want 2", n)
+	}
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r.Path, "/cdn/") && (r.Header.Get("x-verkada-auth") != "" || r.Query.Has("jwt") || r.Query.Get("sig") == "") {
+			t.Errorf("CDN request %s was sent with %v %v", r.Path, r.Query, r.Header)
+		}
+	}
+}
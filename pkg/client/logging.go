@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	neturl "net/url"
+	"strings"
+)
+
+// Placeholder logged in place of API keys, tokens, and other credentials.
+const redacted = "REDACTED"
+
+// Headers whose values are never logged.
+var sensitiveHeaders = map[string]bool{
+	"X-Api-Key":       true,
+	"X-Verkada-Auth":  true,
+	"Authorization":   true,
+	"Cookie":          true,
+	"Set-Cookie":      true,
+	"X-Verkada-Token": true,
+}
+
+// Query parameters whose values are never logged, such as the streaming JWT in footage links.
+var sensitiveParams = map[string]bool{
+	"jwt":     true,
+	"token":   true,
+	"api_key": true,
+}
+
+// Headers that log with sensitive values replaced by "REDACTED".
+type redactedHeader http.Header
+
+func (h redactedHeader) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for name, values := range h {
+		v := strings.Join(values, ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			v = redacted
+		}
+		attrs = append(attrs, slog.String(name, v))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// A URL that logs with sensitive query parameter values replaced by "REDACTED".
+type redactedURL string
+
+func (u redactedURL) LogValue() slog.Value {
+	return slog.StringValue(redactURL(string(u)))
+}
+
+func redactURL(raw string) string {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name := range query {
+			if sensitiveParams[strings.ToLower(name)] {
+				query.Set(name, redacted)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// Returns err with the URL of a *url.Error redacted, since the error would otherwise carry credentials such as the
+// streaming JWT into logs.
+func redactURLError(err error) error {
+	var urlErr *neturl.Error
+	if errors.As(err, &urlErr) {
+		return &neturl.Error{Op: urlErr.Op, URL: redactURL(urlErr.URL), Err: urlErr.Err}
+	}
+	return err
+}
+
+// Returns the URL path without its query, identifying the endpoint in logs.
+func endpointOf(raw string) string {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return u.Path
+}
+
+type pageKey struct{}
+
+// Marks requests made with ctx as fetching page n of a paginated list, so that it appears in their logs.
+func withPage(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, pageKey{}, n)
+}
+
+// Returns the page number set by withPage, or 0 if the request is not part of a paginated list.
+func pageFromContext(ctx context.Context) int {
+	n, _ := ctx.Value(pageKey{}).(int)
+	return n
+}
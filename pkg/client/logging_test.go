@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLogsRedactCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w io.Writer) slog.Handler
+	}{
+		{"text", func(w io.Writer) slog.Handler {
+			return slog.NewTextHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug})
+		}},
+		{"json", func(w io.Writer) slog.Handler {
+			return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: slog.LevelDebug})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var alerts atomic.Int32
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/cameras/v1/alerts":
+					// the first token is rejected, then the first request with the new one fails with a 503
+					switch {
+					case r.Header.Get("x-verkada-auth") == "token-1":
+						http.Error(w, `{"message": "invalid token"}`, http.StatusUnauthorized)
+					case alerts.Add(1) == 1:
+						http.Error(w, `{"message": "unavailable"}`, http.StatusServiceUnavailable)
+					default:
+						w.Write([]byte(`{}`))
+					}
+				case "/stream/cameras/v1/footage/stream/stream.m3u8":
+					http.Error(w, `{"message": "nope"}`, http.StatusNotFound)
+				case "/link":
+					http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				}
+			}, &ClientOptions{
+				Logger:      slog.New(tt.handler(&buf)),
+				RetryPolicy: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryServerErrors: true, RetryNetworkErrors: true},
+				Middleware: []Middleware{func(next http.RoundTripper) http.RoundTripper {
+					return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+						if req.URL.Path == "/reset" {
+							return nil, syscall.ECONNRESET
+						}
+						return next.RoundTrip(req)
+					})
+				}},
+			})
+			jwt := struct {
+				Jwt string `name:"jwt"`
+			}{"secret-jwt"}
+			if err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.MakeVerkadaRequest("GET", srv.URL+"/stream/cameras/v1/footage/stream/stream.m3u8", jwt, nil, nil, 0); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("got %v", err)
+			}
+			if err := c.MakeVerkadaRequest("GET", srv.URL+"/reset", jwt, nil, nil, 0); err == nil {
+				t.Fatal("expected an error")
+			}
+			if _, _, err := c.MakeLinkRequestToWriter(srv.URL+"/link?jwt=secret-jwt", io.Discard, nil); !errors.Is(err, ErrServer) {
+				t.Fatalf("got %v", err)
+			}
+
+			logs := buf.String()
+			for _, secret := range []string{"test-key", "token-1", "token-2", "secret-jwt"} {
+				if strings.Contains(logs, secret) {
+					t.Errorf("logs contain %q:\n%s", secret, logs)
+				}
+			}
+			for _, want := range []string{"verkada auth token rejected", "retrying verkada request", "verkada request failed", "connection reset", "retrying verkada link request", "REDACTED"} {
+				if !strings.Contains(logs, want) {
+					t.Errorf("logs lack %q:\n%s", want, logs)
+				}
+			}
+		})
+	}
+}
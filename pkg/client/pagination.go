@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strconv"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Fetches a single page given its page token (empty for the first page).
+// Returns the page's items and the token of the following page, which is empty on the last page.
+type pageFunc[T any] func(ctx context.Context, token string) (items []T, next string, err error)
+
+// A Paginator lazily walks the pages of a list endpoint, one request per page.
+// Pages are only requested as the iterators returned by All and Pages are ranged over,
+// so breaking out of a loop early stops any further requests.
+//
+// A Paginator is single-use and not safe for concurrent use.
+// Token reports where iteration left off so that it can be resumed later with a new Paginator.
+type Paginator[T any] struct {
+	ctx   context.Context
+	fetch pageFunc[T]
+	// token used to request the page currently being yielded
+	current string
+	// token of the next page to request
+	next string
+	// number of pages fetched so far, used to label requests in logs
+	page int
+	// true while All has yielded some, but not all, items of the current page
+	partial bool
+	done    bool
+	err     error
+}
+
+// Returns a Paginator that starts at the page identified by token (empty for the first page).
+func newPaginator[T any](ctx context.Context, token string, fetch pageFunc[T]) *Paginator[T] {
+	return &Paginator[T]{ctx: ctx, fetch: fetch, next: token}
+}
+
+// Returns a Paginator that yields err without making any requests, used when options fail validation.
+func errPaginator[T any](err error) *Paginator[T] {
+	return &Paginator[T]{err: err}
+}
+
+// Returns an iterator over every item of every page.
+// An error ends iteration after being yielded with the zero value of T.
+func (p *Paginator[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range p.Pages() {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for i, item := range page {
+				p.partial = i+1 < len(page)
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Returns an iterator over whole pages.
+// An error ends iteration after being yielded with a nil page.
+func (p *Paginator[T]) Pages() iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		for !p.done {
+			if p.err != nil {
+				yield(nil, p.err)
+				return
+			}
+			ctx, span := startPageSpan(withPage(p.ctx, p.page+1), p.page+1)
+			items, next, err := p.fetch(ctx, p.next)
+			if err == nil && next != "" && next == p.next {
+				err = fmt.Errorf("pagination did not advance past page token %s", next)
+			}
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+			if err != nil {
+				p.err = err
+				continue
+			}
+			p.current, p.next, p.partial = p.next, next, false
+			p.page++
+			p.done = next == ""
+			if !yield(items, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Returns the page token from which iteration can be resumed, for use as the Page_token (or Cursor) option of a later call.
+// If iteration stopped partway through a page, the token is that of the partially consumed page,
+// so resuming repeats the items of that page already seen rather than skipping the rest of them.
+//
+// Returns an empty string once every item has been consumed.
+func (p *Paginator[T]) Token() string {
+	if p.partial {
+		return p.current
+	}
+	return p.next
+}
+
+// Reports whether the last page has been fetched.
+func (p *Paginator[T]) Done() bool {
+	return p.done
+}
+
+// Returns the error that ended iteration, if any.
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// Requests every page after the one that returned token and appends their items to items.
+// Used to implement Client.AutoPaginate on top of the same page functions as the iterators.
+// Returns the token of the last page requested, which is empty unless an error occurred.
+func collectPages[T any](ctx context.Context, token string, fetch pageFunc[T], items *[]T) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	p := newPaginator(ctx, token, fetch)
+	// the caller already requested the first page
+	p.page = 1
+	for page, err := range p.Pages() {
+		if err != nil {
+			return p.next, err
+		}
+		*items = append(*items, page...)
+	}
+	return "", nil
+}
+
+// Converts the integer page tokens of the license plate endpoints, where 0 means no further pages, to a page token string.
+func intToken(token int) string {
+	if token == 0 {
+		return ""
+	}
+	return strconv.Itoa(token)
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// Returns a page function over pages of items, whose tokens are the page indexes ("" for the first page),
+// failing with errPage when asked for page failAt. Requested tokens are recorded in requests.
+func testPages(pages [][]int, failAt int, requests *[]string) pageFunc[int] {
+	return func(ctx context.Context, token string) ([]int, string, error) {
+		*requests = append(*requests, token)
+		i := 0
+		if token != "" {
+			i, _ = strconv.Atoi(token)
+		}
+		if i == failAt {
+			return nil, "", errPage
+		}
+		next := ""
+		if i+1 < len(pages) {
+			next = strconv.Itoa(i + 1)
+		}
+		return pages[i], next, nil
+	}
+}
+
+var errPage = errors.New("page failed")
+
+func TestPaginatorAll(t *testing.T) {
+	pages := [][]int{{1, 2, 3}, {4, 5}, {6}}
+	tests := []struct {
+		name string
+		// number of items consumed before breaking out of the loop, or -1 for all
+		take         int
+		failAt       int
+		want         []int
+		wantRequests []string
+		wantToken    string
+		wantDone     bool
+		wantErr      error
+	}{
+		{"all", -1, -1, []int{1, 2, 3, 4, 5, 6}, []string{"", "1", "2"}, "", true, nil},
+		{"stop partway through the first page", 2, -1, []int{1, 2}, []string{""}, "", false, nil},
+		{"stop at the end of the first page", 3, -1, []int{1, 2, 3}, []string{""}, "1", false, nil},
+		{"stop partway through the second page", 4, -1, []int{1, 2, 3, 4}, []string{"", "1"}, "1", false, nil},
+		{"stop at the last item", 6, -1, []int{1, 2, 3, 4, 5, 6}, []string{"", "1", "2"}, "", true, nil},
+		{"error on the second page", -1, 1, []int{1, 2, 3}, []string{"", "1"}, "1", false, errPage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			p := newPaginator(context.Background(), "", testPages(pages, tt.failAt, &requests))
+			var got []int
+			for item, err := range p.All() {
+				if err != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("got error %v", err)
+					}
+					break
+				}
+				got = append(got, item)
+				if len(got) == tt.take {
+					break
+				}
+			}
+			if !slices.Equal(got, tt.want) || !slices.Equal(requests, tt.wantRequests) {
+				t.Errorf("got %v with requests %q, want %v with %q", got, requests, tt.want, tt.wantRequests)
+			}
+			if p.Token() != tt.wantToken || p.Done() != tt.wantDone || !errors.Is(p.Err(), tt.wantErr) {
+				t.Errorf("got token %q, done %v, error %v", p.Token(), p.Done(), p.Err())
+			}
+		})
+	}
+}
+
+func TestPaginatorResume(t *testing.T) {
+	pages := [][]int{{1, 2, 3}, {4, 5}, {6}}
+	for take := 1; take <= 6; take++ {
+		var requests []string
+		first := newPaginator(context.Background(), "", testPages(pages, -1, &requests))
+		var seen []int
+		for item := range first.All() {
+			seen = append(seen, item)
+			if len(seen) == take {
+				break
+			}
+		}
+		// resuming repeats the rest of a partially consumed page, but never skips an item
+		resumed := newPaginator(context.Background(), first.Token(), testPages(pages, -1, &requests))
+		var rest []int
+		if !first.Done() {
+			for item, err := range resumed.All() {
+				if err != nil {
+					t.Fatal(err)
+				}
+				rest = append(rest, item)
+			}
+		}
+		all := slices.Compact(slices.Sorted(slices.Values(append(seen, rest...))))
+		if !slices.Equal(all, []int{1, 2, 3, 4, 5, 6}) {
+			t.Errorf("after %d items, resumed from %q and got %v then %v", take, first.Token(), seen, rest)
+		}
+	}
+}
+
+func TestPaginatorPages(t *testing.T) {
+	var requests []string
+	p := newPaginator(context.Background(), "1", testPages([][]int{{1}, {2}, {3}}, -1, &requests))
+	var got [][]int
+	for page, err := range p.Pages() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page)
+		break
+	}
+	if len(got) != 1 || got[0][0] != 2 || p.Token() != "2" || !slices.Equal(requests, []string{"1"}) {
+		t.Errorf("got %v, token %q, requests %q", got, p.Token(), requests)
+	}
+}
+
+func TestPaginatorStuckToken(t *testing.T) {
+	p := newPaginator(context.Background(), "", func(ctx context.Context, token string) ([]int, string, error) {
+		return []int{1}, "same", nil
+	})
+	n := 0
+	for _, err := range p.All() {
+		if err != nil {
+			break
+		}
+		n++
+	}
+	if n != 1 || p.Err() == nil {
+		t.Errorf("got %d items and error %v, want 1 item then an error", n, p.Err())
+	}
+}
+
+func TestCollectPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		failAt    int
+		want      []int
+		wantToken string
+		wantErr   error
+	}{
+		{"all pages", -1, []int{0, 4, 5, 6}, "", nil},
+		{"error", 2, []int{0, 4, 5}, "2", errPage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			items := []int{0}
+			token, err := collectPages(context.Background(), "1", testPages([][]int{{1, 2, 3}, {4, 5}, {6}}, tt.failAt, &requests), &items)
+			if !slices.Equal(items, tt.want) || token != tt.wantToken || !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, token %q, error %v", items, token, err)
+			}
+		})
+	}
+}
+
+func TestGetGuestVisitsAutoPaginateToken(t *testing.T) {
+	// pages of visit IDs; the last visit of each page carries the token of the next
+	pages := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	tests := []struct {
+		name      string
+		failAt    int
+		want      []string
+		wantToken string
+	}{
+		{"all pages", -1, []string{"a", "b", "c", "d", "e"}, ""},
+		{"error on the last page", 2, []string{"a", "b", "c", "d"}, "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				i, _ := strconv.Atoi(r.URL.Query().Get("page_token"))
+				if i == tt.failAt {
+					http.Error(w, `{"message": "bad page"}`, http.StatusBadRequest)
+					return
+				}
+				var ret GetGuestVisitsResponse
+				for _, id := range pages[i] {
+					ret.Visits = append(ret.Visits, GuestVisit{Visit_id: id})
+				}
+				if i+1 < len(pages) {
+					ret.Visits[len(ret.Visits)-1].Next_page_token = strconv.Itoa(i + 1)
+				}
+				json.NewEncoder(w).Encode(ret)
+			}, &ClientOptions{AutoPaginate: true, DecodeMode: DecodeLenient})
+			res, err := c.Guest.GetGuestVisits("site-1", Int(0), Int(3600), nil)
+			if (err != nil) != (tt.failAt >= 0) {
+				t.Fatalf("got error %v", err)
+			}
+			var got []string
+			for i, v := range res.Visits {
+				got = append(got, v.Visit_id)
+				if want := ""; i < len(res.Visits)-1 && v.Next_page_token != want {
+					t.Errorf("visit %s, which is not the last, has token %q", v.Visit_id, v.Next_page_token)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got visits %v, want %v", got, tt.want)
+			}
+			if token := res.Visits[len(res.Visits)-1].Next_page_token; token != tt.wantToken {
+				t.Errorf("last visit has token %q, want %q", token, tt.wantToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client/auth"
+)
+
+// Number of orgs FanOut calls at once when its parallelism is zero or less.
+const DefaultPoolParallelism = 8
+
+// An OrgProfile configures the Client of one Verkada organization in a ClientPool.
+type OrgProfile struct {
+	// Name the org's Client is looked up by, unique within the pool.
+	Name string `json:"name"`
+	// API key of the org. When empty, it is read from the environment variable named by APIKeyEnv or else from the file
+	// at APIKeyFile (e.g. a Kubernetes secret), so that keys can be kept out of profile files.
+	// Keys read from the environment or a file are read again on each token request, so they can be rotated.
+	APIKey     string `json:"api_key,omitempty"`
+	APIKeyEnv  string `json:"api_key_env,omitempty"`
+	APIKeyFile string `json:"api_key_file,omitempty"`
+	// Region of the org, e.g. "prod1", "prod2", or "au". BaseURL replaces the region's base URL when set.
+	Region  string `json:"region,omitempty"`
+	BaseURL string `json:"base_url,omitempty"`
+	// Limits of a RateLimiter for the org's key; requests are only limited by the API's 429 responses if empty.
+	// Profiles with the same key share one RateLimiter, using the lowest of their limits for each family.
+	RateLimits map[EndpointFamily]Rate `json:"rate_limits,omitempty"`
+}
+
+// Loads org profiles from a JSON file holding an array of OrgProfile, e.g.
+//
+//	[{"name": "hq", "api_key_env": "HQ_API_KEY", "region": "prod1", "rate_limits": {"cameras": {"per_second": 10, "burst": 20}}}]
+func LoadOrgProfiles(path string) ([]OrgProfile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var profiles []OrgProfile
+	if err := json.Unmarshal(b, &profiles); err != nil {
+		return nil, fmt.Errorf("error parsing org profiles %s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// A ClientPool holds a Client per named Verkada organization, each with its own API key, region, and rate limits.
+// It is safe for concurrent use.
+type ClientPool struct {
+	clients map[string]*Client
+	orgs    []string
+}
+
+// Returns a ClientPool with a Client for each profile.
+// options, which may be nil, is shared by every Client except for APIKey, Credentials, Region, BaseURL, StreamingBaseURL,
+// and RateLimiter, which come from the profile; the API_KEY environment variable and .env file are not used.
+// Keys are read here to find the profiles sharing one, so that together they stay under its quota.
+// Auth tokens are fetched on each Client's first request rather than here, and a non-nil Logger has an "org" attribute added.
+func NewClientPool(profiles []OrgProfile, options *ClientOptions) (*ClientPool, error) {
+	p := &ClientPool{clients: map[string]*Client{}}
+	// limits of the profiles using each key
+	limits := map[string]map[EndpointFamily]Rate{}
+	for _, profile := range profiles {
+		if profile.Name == "" {
+			return nil, fmt.Errorf("error: org profile without a name")
+		}
+		if _, ok := p.clients[profile.Name]; ok {
+			return nil, fmt.Errorf("error: duplicate org profile %q", profile.Name)
+		}
+		var credentials auth.ChainProvider
+		if profile.APIKey != "" {
+			credentials = append(credentials, auth.StaticProvider(profile.APIKey))
+		}
+		if profile.APIKeyEnv != "" {
+			credentials = append(credentials, auth.EnvProvider{Name: profile.APIKeyEnv})
+		}
+		if profile.APIKeyFile != "" {
+			credentials = append(credentials, auth.FileProvider{Path: profile.APIKeyFile})
+		}
+		if len(credentials) == 0 {
+			return nil, fmt.Errorf("error: org profile %q has no API key", profile.Name)
+		}
+		var orgOptions ClientOptions
+		if options != nil {
+			orgOptions = *options
+		}
+		orgOptions.Region = profile.Region
+		orgOptions.BaseURL = profile.BaseURL
+		orgOptions.StreamingBaseURL = ""
+		orgOptions.SkipTokenFetch = true
+		orgOptions.RateLimiter = nil
+		if orgOptions.Logger != nil {
+			orgOptions.Logger = orgOptions.Logger.With("org", profile.Name)
+		}
+		c, err := newClient(&orgOptions, credentials)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client for org %q: %w", profile.Name, err)
+		}
+		p.clients[profile.Name] = c
+		p.orgs = append(p.orgs, profile.Name)
+		if limits[c.Key] == nil {
+			limits[c.Key] = map[EndpointFamily]Rate{}
+		}
+		for family, rate := range profile.RateLimits {
+			if current, ok := limits[c.Key][family]; !ok || rate.PerSecond < current.PerSecond {
+				limits[c.Key][family] = rate
+			}
+		}
+	}
+	limiters := map[string]*RateLimiter{}
+	for key, keyLimits := range limits {
+		if len(keyLimits) > 0 {
+			limiters[key] = NewRateLimiter(keyLimits)
+		}
+	}
+	for _, c := range p.clients {
+		c.rateLimiter = limiters[c.Key]
+	}
+	slices.Sort(p.orgs)
+	return p, nil
+}
+
+// Returns the Client of an org, and whether the pool has it.
+func (p *ClientPool) Client(org string) (*Client, bool) {
+	c, ok := p.clients[org]
+	return c, ok
+}
+
+// Returns the names of all orgs in the pool, sorted.
+func (p *ClientPool) Orgs() []string {
+	return slices.Clone(p.orgs)
+}
+
+// The outcome of a FanOut call for one org.
+type OrgResult[T any] struct {
+	Org   string
+	Value T
+	// Error returned for the org, or the context's error if ctx was done before its call started.
+	Err error
+}
+
+// Results of FanOut, one per org sorted by org name.
+type OrgResults[T any] []OrgResult[T]
+
+// Returns the values of the orgs that succeeded, by org name.
+func (r OrgResults[T]) Values() map[string]T {
+	values := map[string]T{}
+	for _, result := range r {
+		if result.Err == nil {
+			values[result.Org] = result.Value
+		}
+	}
+	return values
+}
+
+// Returns the errors of the orgs that failed joined as *OrgError values, or nil if every org succeeded.
+func (r OrgResults[T]) Err() error {
+	var errs []error
+	for _, result := range r {
+		if result.Err != nil {
+			errs = append(errs, &OrgError{Org: result.Org, Err: result.Err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// An OrgError is the error of one org in a fan-out operation.
+type OrgError struct {
+	Org string
+	Err error
+}
+
+func (e *OrgError) Error() string {
+	return fmt.Sprintf("org %s: %v", e.Org, e.Err)
+}
+
+func (e *OrgError) Unwrap() error {
+	return e.Err
+}
+
+// Calls fn with the Client of every org in the pool, running at most parallelism calls at once
+// (DefaultPoolParallelism if zero or less), and returns the results tagged by org.
+// An error for one org does not stop the others; orgs not yet started when ctx is done fail with the context's error.
+//
+//	results := client.FanOut(ctx, pool, 4, func(ctx context.Context, org string, c *client.Client) (*client.GetCameraDevicesResponse, error) {
+//		return c.Camera.GetCameraDevicesContext(ctx, nil)
+//	})
+func FanOut[T any](ctx context.Context, p *ClientPool, parallelism int, fn func(ctx context.Context, org string, c *Client) (T, error)) OrgResults[T] {
+	if parallelism <= 0 {
+		parallelism = DefaultPoolParallelism
+	}
+	results := make(OrgResults[T], len(p.orgs))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, org := range p.orgs {
+		results[i].Org = org
+		select {
+		case sem <- struct{}{}:
+			// a slot may be freed as ctx is done, and select picks either at random
+			if err := ctx.Err(); err != nil {
+				<-sem
+				results[i].Err = err
+				continue
+			}
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Value, results[i].Err = fn(ctx, org, p.clients[org])
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encodes the query parameters of a request from an options struct (or a pointer to one), or returns url.Values as is.
+// nil and nil pointers encode no parameters.
+//
+// Each exported or unexported field with a name tag becomes a parameter, following the grammar
+//
+//	name:"param[,option]..."
+//
+// where the options are
+//
+//   - omitempty: also omit zero numbers, false bools, and zero times, which are otherwise sent
+//   - explode: send slices and arrays as one param=value pair per element instead of a comma-joined list
+//   - unix, unixmilli, rfc3339: format time.Time fields as Unix seconds (the default), Unix milliseconds, or RFC 3339
+//
+// Empty strings, nil pointers, empty slices, and zero times are always omitted, so that pointer fields can send zero values.
+// Fields tagged name:"-" or without a name tag are skipped, except that untagged struct fields (embedded or not) have
+// their own fields encoded as if they belonged to the outer struct. Values are escaped by url.Values.Encode.
+//
+// Strings, bools, integers, floats, time.Time, encoding.TextMarshaler implementations, pointers to these,
+// and slices or arrays of these are supported; any other field type is an error.
+func encodeQuery(params any) (url.Values, error) {
+	values := url.Values{}
+	if params == nil {
+		return values, nil
+	}
+	if v, ok := params.(url.Values); ok {
+		return v, nil
+	}
+	val := reflect.ValueOf(params)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return values, nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query params must be a struct, a pointer to a struct, or url.Values - received %T", params)
+	}
+	if err := encodeQueryStruct(values, val); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Options parsed from a name tag.
+type queryTag struct {
+	name      string
+	omitEmpty bool
+	explode   bool
+	// time layout option: "unix", "unixmilli", or "rfc3339"
+	timeFormat string
+}
+
+func parseQueryTag(tag string) (queryTag, error) {
+	name, rest, _ := strings.Cut(tag, ",")
+	t := queryTag{name: name, timeFormat: "unix"}
+	for rest != "" {
+		var option string
+		option, rest, _ = strings.Cut(rest, ",")
+		switch option {
+		case "omitempty":
+			t.omitEmpty = true
+		case "explode":
+			t.explode = true
+		case "unix", "unixmilli", "rfc3339":
+			t.timeFormat = option
+		default:
+			return t, fmt.Errorf("unknown option %q in name tag %q", option, tag)
+		}
+	}
+	return t, nil
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func encodeQueryStruct(values url.Values, val reflect.Value) error {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		fld := typ.Field(i)
+		tag, tagged := fld.Tag.Lookup("name")
+		if tag == "-" {
+			continue
+		}
+		if !tagged {
+			// flatten untagged structs, such as embedded options
+			fv := val.Field(i)
+			if fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+				if err := encodeQueryStruct(values, fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		t, err := parseQueryTag(tag)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", typ.Name(), fld.Name, err)
+		}
+		if t.name == "" {
+			return fmt.Errorf("%s.%s: name tag has no parameter name", typ.Name(), fld.Name)
+		}
+		if err := encodeQueryField(values, t, val.Field(i)); err != nil {
+			return fmt.Errorf("%s.%s: %w", typ.Name(), fld.Name, err)
+		}
+	}
+	return nil
+}
+
+func encodeQueryField(values url.Values, t queryTag, v reflect.Value) error {
+	v, ok := derefQueryValue(v)
+	if !ok {
+		return nil
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && !isTextMarshaler(v) {
+		var items []string
+		for j := 0; j < v.Len(); j++ {
+			item, ok := derefQueryValue(v.Index(j))
+			if !ok {
+				continue
+			}
+			s, ok, err := formatQueryValue(t, item)
+			if err != nil {
+				return err
+			}
+			if ok {
+				items = append(items, s)
+			}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		if t.explode {
+			values[t.name] = append(values[t.name], items...)
+		} else {
+			values.Add(t.name, strings.Join(items, ","))
+		}
+		return nil
+	}
+	s, ok, err := formatQueryValue(t, v)
+	if err != nil || !ok {
+		return err
+	}
+	values.Add(t.name, s)
+	return nil
+}
+
+// Dereferences pointers and interfaces, reporting false if one is nil.
+func derefQueryValue(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+func isTextMarshaler(v reflect.Value) bool {
+	return v.Type() != timeType && v.CanInterface() && v.Type().Implements(textMarshalerType)
+}
+
+// Formats a single value, reporting false if it should be omitted.
+func formatQueryValue(t queryTag, v reflect.Value) (string, bool, error) {
+	if v.Type() == timeType {
+		if !v.CanInterface() {
+			return "", false, fmt.Errorf("unexported time.Time fields are not supported")
+		}
+		tm := v.Interface().(time.Time)
+		if tm.IsZero() {
+			return "", false, nil
+		}
+		switch t.timeFormat {
+		case "unixmilli":
+			return strconv.FormatInt(tm.UnixMilli(), 10), true, nil
+		case "rfc3339":
+			return tm.Format(time.RFC3339), true, nil
+		}
+		return strconv.FormatInt(tm.Unix(), 10), true, nil
+	}
+	if isTextMarshaler(v) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return "", false, err
+		}
+		return string(b), len(b) > 0, nil
+	}
+	if t.omitEmpty && v.IsZero() {
+		return "", false, nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), v.Len() > 0, nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), true, nil
+	}
+	return "", false, fmt.Errorf("unsupported query parameter type %s", v.Type())
+}
@@ -0,0 +1,283 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type queryInner struct {
+	Site string `name:"site_id"`
+}
+
+type queryText struct{ v string }
+
+func (t queryText) MarshalText() ([]byte, error) {
+	return []byte("text:" + t.v), nil
+}
+
+func TestEncodeQuery(t *testing.T) {
+	when := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	tests := []struct {
+		name   string
+		params any
+		want   string
+	}{
+		{"nil", nil, ""},
+		{"nil pointer", (*GetAlertsOptions)(nil), ""},
+		{"empty struct", GetAlertsOptions{}, ""},
+		{"url.Values", url.Values{"a": {"1", "2"}}, "a=1&a=2"},
+		{"struct", GetAlertsOptions{Page_token: "abc"}, "page_token=abc"},
+		{"pointer to struct", &GetAlertsOptions{Page_token: "abc"}, "page_token=abc"},
+		{"escaping", struct {
+			Email string `name:"email"`
+			Plate string `name:"plate"`
+		}{"a+b@example.com", "AB 12&3=#"}, "email=a%2Bb%40example.com&plate=AB+12%263%3D%23"},
+		{"unicode", struct {
+			Name string `name:"name"`
+		}{"Zoë"}, "name=Zo%C3%AB"},
+		{"int pointers send zero", struct {
+			Start *int   `name:"start_time"`
+			End   *int64 `name:"end_time"`
+			Size  *int   `name:"page_size"`
+		}{Ptr(0), Ptr(int64(1700000000)), nil}, "end_time=1700000000&start_time=0"},
+		{"bool and float pointers", struct {
+			Flag  *bool    `name:"flag"`
+			Other *bool    `name:"other"`
+			Ratio *float64 `name:"ratio"`
+		}{Bool(false), nil, Ptr(0.25)}, "flag=false&ratio=0.25"},
+		{"non-pointer scalars", struct {
+			N    int     `name:"n"`
+			U    uint8   `name:"u"`
+			B    bool    `name:"b"`
+			F    float32 `name:"f"`
+			Zero int     `name:"zero"`
+		}{N: -3, U: 7, B: true, F: 1.5}, "b=true&f=1.5&n=-3&u=7&zero=0"},
+		{"omitempty", struct {
+			N     int       `name:"n,omitempty"`
+			B     bool      `name:"b,omitempty"`
+			F     float64   `name:"f,omitempty"`
+			Kept  int       `name:"kept,omitempty"`
+			Empty time.Time `name:"t,omitempty"`
+		}{Kept: 1}, "kept=1"},
+		{"comma-joined slice", struct {
+			IDs []string `name:"ids"`
+		}{[]string{"a", "b,c", "d e"}}, "ids=a%2Cb%2Cc%2Cd+e"},
+		{"exploded slice", struct {
+			IDs []string `name:"ids,explode"`
+		}{[]string{"a", "b"}}, "ids=a&ids=b"},
+		{"empty and nil slices", struct {
+			Nil   []string `name:"nil"`
+			Empty []int    `name:"empty"`
+		}{Empty: []int{}}, ""},
+		{"array and pointer elements", struct {
+			Arr [2]int `name:"arr"`
+			Ptr []*int `name:"ptr"`
+		}{[2]int{1, 2}, []*int{Ptr(3), nil, Ptr(4)}}, "arr=1%2C2&ptr=3%2C4"},
+		{"empty strings in slices are dropped", struct {
+			IDs []string `name:"ids"`
+		}{[]string{"", "a", ""}}, "ids=a"},
+		{"time unix by default", struct {
+			T time.Time `name:"t"`
+		}{when}, "t=1714979289"},
+		{"time unixmilli", struct {
+			T *time.Time `name:"t,unixmilli"`
+		}{&when}, "t=1714979289000"},
+		{"time rfc3339", struct {
+			T time.Time `name:"t,rfc3339"`
+		}{when}, "t=2024-05-06T07%3A08%3A09Z"},
+		{"zero time omitted", struct {
+			T time.Time `name:"t"`
+		}{}, ""},
+		{"time slice", struct {
+			T []time.Time `name:"t,explode"`
+		}{[]time.Time{when, when.Add(time.Second)}}, "t=1714979289&t=1714979290"},
+		{"text marshaler", struct {
+			T queryText   `name:"t"`
+			S []queryText `name:"s"`
+		}{queryText{"a"}, []queryText{{"b"}, {"c"}}}, "s=text%3Ab%2Ctext%3Ac&t=text%3Aa"},
+		{"unexported fields", GetFootageOptions{org_id: "org", camera_id: "cam", jwt: "j.w.t", Start_time: Ptr(5)}, "camera_id=cam&jwt=j.w.t&org_id=org&start_time=5"},
+		{"skipped fields", struct {
+			Skipped  string `name:"-"`
+			Untagged string
+			Body     string `json:"body"`
+			Kept     string `name:"kept"`
+		}{"a", "b", "c", "d"}, "kept=d"},
+		{"embedded struct is flattened", struct {
+			queryInner
+			Page string `name:"page"`
+		}{queryInner{"s1"}, "p"}, "page=p&site_id=s1"},
+		{"nested struct and pointer are flattened", struct {
+			Inner  queryInner
+			Nested *queryInner
+			Nil    *queryInner
+		}{queryInner{"a"}, &queryInner{"b"}, nil}, "site_id=a&site_id=b"},
+		{"repeated names are kept", struct {
+			A string `name:"x"`
+			B string `name:"x"`
+		}{"1", "2"}, "x=1&x=2"},
+		{"search zones", GetMaxCountsOptions{camera_id: "cam", Search_zones: [][]int64{{1, 2}}, search_zones: "1.2"}, "camera_id=cam&search_zones=1.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := encodeQuery(tt.params)
+			if err != nil {
+				t.Fatalf("encodeQuery(%#v) returned error: %v", tt.params, err)
+			}
+			if got := values.Encode(); got != tt.want {
+				t.Errorf("encodeQuery(%#v) = %q, want %q", tt.params, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeQueryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params any
+		want   string
+	}{
+		{"string params", "a=b", "must be a struct"},
+		{"map params", map[string]string{"a": "b"}, "must be a struct"},
+		{"unknown option", struct {
+			A string `name:"a,bogus"`
+		}{"x"}, `unknown option "bogus"`},
+		{"empty name", struct {
+			A string `name:",omitempty"`
+		}{"x"}, "no parameter name"},
+		{"map field", struct {
+			M map[string]string `name:"m"`
+		}{map[string]string{"a": "b"}}, "unsupported query parameter type map[string]string"},
+		{"nested slice field", struct {
+			Z [][]int64 `name:"z"`
+		}{[][]int64{{1, 2}}}, "unsupported query parameter type []int64"},
+		{"tagged struct field", struct {
+			Inner queryInner `name:"inner"`
+		}{queryInner{"a"}}, "unsupported query parameter type client.queryInner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := encodeQuery(tt.params)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("encodeQuery(%#v) error = %v, want error containing %q", tt.params, err, tt.want)
+			}
+		})
+	}
+}
+
+// Every options struct with query parameters encodes without error, omits unset fields, and sends every set field.
+func TestEncodeQueryOptionsStructs(t *testing.T) {
+	options := []any{
+		&ActivateAccessCardOptions{}, &ActivateLicensePlateOptions{}, &ActivateUserBLEOptions{}, &ActivateUserRemoteUnlockOptions{},
+		&AddAccessCardOptions{}, &AddMFACodeOptions{}, &AddUserLicensePlateOptions{}, &AddUserToAccessGroupOptions{},
+		&DeactivateAccessCardOptions{}, &DeactivateLicensePlateOptions{}, &DeactivateUserBLEOptions{}, &DeactivateUserRemoteUnlockOptions{},
+		&DeleteAccessCardOptions{}, &DeleteAccessGroupOptions{}, &DeleteDenyListOptions{}, &DeleteHelixEventOptions{},
+		&DeleteHelixEventTypeOptions{}, &DeleteLPOIOptions{}, &DeleteMFACodeOptions{}, &DeletePOIOptions{},
+		&DeleteProfilePhotoOptions{}, &DeleteUserLicensePlateOptions{}, &DeleteUserOptions{}, &GetAccessEventsOptions{},
+		&GetAccessGroupOptions{}, &GetAccessInformationObjectOptions{}, &GetAlarmDevicesOptions{}, &GetAlarmSitesOptions{},
+		&GetAlertsOptions{}, &GetAllAccessScenariosOptions{}, &GetAllDoorExceptionCalendarsOptions{}, &GetAllLPOIOptions{},
+		&GetAllPOIOptions{}, &GetAuditLogsOptions{}, &GetCBSettingsOptions{}, &GetCameraAudioStatusOptions{},
+		&GetCameraDevicesOptions{}, &GetDashboardOTDataOptions{}, &GetDoorsOptions{}, &GetFootageOptions{},
+		&GetGuestTypesOptions{}, &GetGuestVisitsOptions{}, &GetHelixEventOptions{}, &GetHelixEventTypesOptions{},
+		&GetHostsOptions{}, &GetLatestThumbnailImageOptions{}, &GetLicensePlateTSOptions{}, &GetLinkToFootageOptions{},
+		&GetMaxCountsOptions{}, &GetOTDataOptions{}, &GetObjectCountsOptions{}, &GetProfilePhotoOptions{},
+		&GetSeenPlatesOptions{}, &GetSensorAlertsOptions{}, &GetSensorDataOptions{}, &GetThumbnailImageOptions{},
+		&GetThumbnailLinkOptions{}, &GetUserOptions{}, &PostDenyListOptions{}, &RemoveUserEntryCodeOptions{},
+		&RemoveUserFromAccessGroupOptions{}, &SendPassInviteOptions{}, &SetStartDateOptions{}, &SetUserEndDateOptions{},
+		&SetUserEntryCodeOptions{}, &UpdateHelixEventOptions{}, &UpdateHelixEventTypeOptions{}, &UpdateLPOIOptions{},
+		&UpdatePOIOptions{}, &UpdateUserOptions{}, &UploadProfilePhotoOptions{}, &UserUnlockDoorOptions{},
+	}
+	for _, opts := range options {
+		typ := reflect.TypeOf(opts).Elem()
+		t.Run(typ.Name(), func(t *testing.T) {
+			values, err := encodeQuery(opts)
+			if err != nil {
+				t.Fatalf("zero value returned error: %v", err)
+			}
+			if len(values) != 0 {
+				t.Errorf("zero value encoded %q, want no params", values.Encode())
+			}
+			val := reflect.ValueOf(opts).Elem()
+			want := map[string]string{}
+			for i := 0; i < typ.NumField(); i++ {
+				fld := typ.Field(i)
+				tag, ok := fld.Tag.Lookup("name")
+				if !ok || !fld.IsExported() {
+					continue
+				}
+				name, _, _ := strings.Cut(tag, ",")
+				switch f := val.Field(i); f.Interface().(type) {
+				case string:
+					f.SetString("a b+c")
+					want[name] = "a b+c"
+				case *int:
+					f.Set(reflect.ValueOf(Ptr(0)))
+					want[name] = "0"
+				case *int64:
+					f.Set(reflect.ValueOf(Ptr(int64(42))))
+					want[name] = "42"
+				case *bool:
+					f.Set(reflect.ValueOf(Bool(false)))
+					want[name] = "false"
+				case []string:
+					f.Set(reflect.ValueOf([]string{"x", "y"}))
+					want[name] = "x,y"
+				default:
+					t.Fatalf("field %s has untested type %s", fld.Name, fld.Type)
+				}
+			}
+			values, err = encodeQuery(opts)
+			if err != nil {
+				t.Fatalf("populated value returned error: %v", err)
+			}
+			for name, v := range want {
+				if got := values.Get(name); got != v {
+					t.Errorf("param %s = %q, want %q", name, got, v)
+				}
+			}
+			if len(values) != len(want) {
+				t.Errorf("encoded %q, want only the params %v", values.Encode(), want)
+			}
+		})
+	}
+}
+
+func TestGetMaxCountsSearchZones(t *testing.T) {
+	tests := []struct {
+		name    string
+		zones   [][]int64
+		want    string
+		wantErr error
+	}{
+		{"none", nil, "", nil},
+		{"one zone", [][]int64{{10, 20}}, "10.20", nil},
+		{"several zones", [][]int64{{10, 20}, {30, 40}, {5, 5}}, "10.20.30.40.5.5", nil},
+		{"not a pair", [][]int64{{10, 20}, {30}}, "", ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				w.Write([]byte(`{}`))
+			}, &ClientOptions{DecodeMode: DecodeLenient})
+			_, err := c.Camera.GetMaxCounts("cam-1", &GetMaxCountsOptions{Search_zones: tt.zones})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if query != nil {
+					t.Errorf("request was sent")
+				}
+				return
+			}
+			if got := query.Get("search_zones"); got != tt.want || query.Get("camera_id") != "cam-1" {
+				t.Errorf("sent %q, want search_zones=%s", query.Encode(), tt.want)
+			}
+		})
+	}
+}
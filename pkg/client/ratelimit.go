@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An EndpointFamily groups endpoints that share a rate limit, identified from the request path.
+type EndpointFamily string
+
+// Endpoint families recognized by RateLimiter.
+const (
+	FamilyCameras        EndpointFamily = "cameras"         // /cameras/..., except Helix
+	FamilyHelix          EndpointFamily = "helix"           // /cameras/v1/video_tagging/...
+	FamilyStreaming      EndpointFamily = "streaming"       // /stream/...
+	FamilyCore           EndpointFamily = "core"            // /core/...
+	FamilyAccess         EndpointFamily = "access"          // /access/...
+	FamilyEvents         EndpointFamily = "events"          // /events/...
+	FamilySensors        EndpointFamily = "sensors"         // /environment/...
+	FamilyGuest          EndpointFamily = "guest"           // /guest/... and /v2/guest/...
+	FamilyAlarms         EndpointFamily = "alarms"          // /alarms/...
+	FamilyViewingStation EndpointFamily = "viewing_station" // /viewing_station/...
+	// Used for any family without a limit of its own, including endpoints not listed above.
+	FamilyDefault EndpointFamily = "default"
+)
+
+// Returns the EndpointFamily of a request URL or path.
+func endpointFamily(url string) EndpointFamily {
+	path := endpointOf(url)
+	switch {
+	case strings.HasPrefix(path, "/cameras/v1/video_tagging"):
+		return FamilyHelix
+	case strings.HasPrefix(path, "/cameras/"):
+		return FamilyCameras
+	case strings.HasPrefix(path, "/stream/"):
+		return FamilyStreaming
+	case strings.HasPrefix(path, "/core/"):
+		return FamilyCore
+	case strings.HasPrefix(path, "/access/"):
+		return FamilyAccess
+	case strings.HasPrefix(path, "/events/"):
+		return FamilyEvents
+	case strings.HasPrefix(path, "/environment/"):
+		return FamilySensors
+	case strings.HasPrefix(path, "/guest/"), strings.HasPrefix(path, "/v2/guest/"):
+		return FamilyGuest
+	case strings.HasPrefix(path, "/alarms/"):
+		return FamilyAlarms
+	case strings.HasPrefix(path, "/viewing_station/"):
+		return FamilyViewingStation
+	}
+	return FamilyDefault
+}
+
+// A Rate is the sustained number of requests per second allowed for an endpoint family,
+// with up to Burst requests allowed at once after a quiet period.
+type Rate struct {
+	PerSecond float64 `json:"per_second"`
+	// Values below 1 are treated as 1.
+	Burst int `json:"burst"`
+}
+
+// A RateLimiter keeps requests under per-key quotas with a token bucket per endpoint family.
+// It is safe for concurrent use, and a single RateLimiter can be set on the ClientOptions of several Clients
+// using the same API key so that they stay under the quota together.
+//
+// When a request is rate limited (429) anyway, every request of its family waits out the response's Retry-After delay,
+// not just the request that received it.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  map[EndpointFamily]Rate
+	buckets map[EndpointFamily]*bucket
+}
+
+type bucket struct {
+	rate   Rate
+	tokens float64
+	last   time.Time
+	// no request of the family is allowed before this time, set after a 429 response
+	blockedUntil time.Time
+}
+
+// Returns a RateLimiter enforcing limits per endpoint family.
+// Families without an entry use the FamilyDefault entry if there is one and are unlimited otherwise;
+// a Rate with PerSecond of zero or less is also unlimited.
+func NewRateLimiter(limits map[EndpointFamily]Rate) *RateLimiter {
+	l := &RateLimiter{limits: map[EndpointFamily]Rate{}, buckets: map[EndpointFamily]*bucket{}}
+	for family, rate := range limits {
+		l.limits[family] = rate
+	}
+	return l
+}
+
+// Replaces the limit of a family, keeping any delay from a 429 response in place.
+func (l *RateLimiter) SetLimit(family EndpointFamily, rate Rate) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits[family] = rate
+	if b, ok := l.buckets[family]; ok {
+		b.rate = rate
+		b.tokens = math.Min(b.tokens, float64(max(rate.Burst, 1)))
+	}
+}
+
+// Blocks until a request to the endpoint family is allowed, returning early with the context's error if ctx is done first.
+func (l *RateLimiter) Wait(ctx context.Context, family EndpointFamily) error {
+	_, err := l.wait(ctx, family)
+	return err
+}
+
+// Same as Wait, also returning how long the request was delayed.
+func (l *RateLimiter) wait(ctx context.Context, family EndpointFamily) (time.Duration, error) {
+	l.mu.Lock()
+	b := l.bucketLocked(family)
+	if b == nil {
+		l.mu.Unlock()
+		return 0, ctx.Err()
+	}
+	now := time.Now()
+	delay := b.reserve(now)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return 0, ctx.Err()
+	}
+	if err := sleepContext(ctx, delay); err != nil {
+		// give back the reserved token so that a cancelled request does not delay the others
+		l.mu.Lock()
+		b.tokens = math.Min(b.tokens+1, float64(max(b.rate.Burst, 1)))
+		l.mu.Unlock()
+		return 0, err
+	}
+	return delay, nil
+}
+
+// Delays every request of the family until d from now, used after a 429 response.
+func (l *RateLimiter) block(family EndpointFamily, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucketLocked(family)
+	if b == nil {
+		return
+	}
+	if until := time.Now().Add(d); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// Returns the bucket of a family, creating it if needed, or nil if the family is unlimited. l.mu must be held.
+// Families without their own limit share the FamilyDefault bucket.
+func (l *RateLimiter) bucketLocked(family EndpointFamily) *bucket {
+	if _, ok := l.limits[family]; !ok {
+		family = FamilyDefault
+	}
+	rate, ok := l.limits[family]
+	if !ok || rate.PerSecond <= 0 {
+		return nil
+	}
+	b, ok := l.buckets[family]
+	if !ok {
+		b = &bucket{rate: rate, tokens: float64(max(rate.Burst, 1)), last: time.Now()}
+		l.buckets[family] = b
+	}
+	return b
+}
+
+// Takes a token, letting the balance go negative, and returns how long the caller must wait before using it.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	burst := float64(max(b.rate.Burst, 1))
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*b.rate.PerSecond)
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate.PerSecond * float64(time.Second))
+	}
+	if blocked := b.blockedUntil.Sub(now); blocked > delay {
+		delay = blocked
+	}
+	return delay
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEndpointFamily(t *testing.T) {
+	tests := []struct {
+		url  string
+		want EndpointFamily
+	}{
+		{"https://api.verkada.com/cameras/v1/devices", FamilyCameras},
+		{"https://api.verkada.com/cameras/v1/video_tagging/event_type", FamilyHelix},
+		{"https://api.verkada.com/stream/cameras/v1/footage/stream/stream.m3u8?jwt=x", FamilyStreaming},
+		{"/core/v1/audit_log", FamilyCore},
+		{"/access/v1/doors", FamilyAccess},
+		{"/events/v1/access", FamilyEvents},
+		{"/environment/v1/data", FamilySensors},
+		{"/guest/v1/visits", FamilyGuest},
+		{"/v2/guest/hosts", FamilyGuest},
+		{"/alarms/v1/sites", FamilyAlarms},
+		{"/viewing_station/v1/devices", FamilyViewingStation},
+		{"/token", FamilyDefault},
+		{"/v2/analytics/operational_dashboard", FamilyDefault},
+	}
+	for _, tt := range tests {
+		if got := endpointFamily(tt.url); got != tt.want {
+			t.Errorf("endpointFamily(%s) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestBucketReserve(t *testing.T) {
+	start := time.Now()
+	b := &bucket{rate: Rate{PerSecond: 10, Burst: 3}, tokens: 3, last: start}
+	tests := []struct {
+		at   time.Duration
+		want time.Duration
+	}{
+		// the burst is allowed at once
+		{0, 0},
+		{0, 0},
+		{0, 0},
+		// then one request per 100ms, queued behind each other
+		{0, 100 * time.Millisecond},
+		{0, 200 * time.Millisecond},
+		// after a quiet period the balance recovers, up to the burst
+		{time.Second, 0},
+		{time.Second, 0},
+		{time.Second, 0},
+		{time.Second, 100 * time.Millisecond},
+	}
+	for i, tt := range tests {
+		got := b.reserve(start.Add(tt.at))
+		if diff := got - tt.want; diff < -time.Millisecond || diff > time.Millisecond {
+			t.Errorf("request %d at %s waits %s, want %s", i, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterBlocks(t *testing.T) {
+	l := NewRateLimiter(map[EndpointFamily]Rate{FamilyCameras: {PerSecond: 50, Burst: 1}})
+	ctx := context.Background()
+	start := time.Now()
+	for range 6 {
+		if err := l.Wait(ctx, FamilyCameras); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// one request at once, then five at 20ms intervals
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("6 requests at 50/s took %s", elapsed)
+	}
+	// families without a limit, and no default, are not delayed
+	start = time.Now()
+	for range 100 {
+		l.Wait(ctx, FamilyAccess)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("unlimited family took %s", elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(map[EndpointFamily]Rate{FamilyDefault: {PerSecond: 1, Burst: 1}})
+	l.Wait(context.Background(), FamilyCore)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Wait(ctx, FamilyGuest); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("cancelled wait took %s", elapsed)
+	}
+}
+
+func TestRateLimiterBlockAfter429(t *testing.T) {
+	l := NewRateLimiter(map[EndpointFamily]Rate{FamilyCameras: {PerSecond: 1000, Burst: 100}, FamilyDefault: {PerSecond: 1000, Burst: 100}})
+	var requests []time.Time
+	c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, time.Now())
+		if len(requests) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}, &ClientOptions{RateLimiter: l, RetryPolicy: &RetryPolicy{MaxAttempts: 2, RespectRetryAfter: true, MaxDelay: 100 * time.Millisecond}})
+	if err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/devices", nil, nil, &map[string]any{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	// the family stays blocked for the Retry-After delay (capped by MaxDelay), also for other callers
+	start := time.Now()
+	if err := l.Wait(context.Background(), FamilyCameras); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || requests[1].Sub(requests[0]) < 90*time.Millisecond {
+		t.Errorf("retried %d requests after %s", len(requests), requests[len(requests)-1].Sub(requests[0]))
+	}
+	if waited := time.Since(start); waited > 100*time.Millisecond {
+		t.Errorf("waited %s after the block expired", waited)
+	}
+	// a family with a bucket of its own is not blocked by it
+	l.block(FamilyCameras, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.Wait(ctx, FamilyAccess); err != nil {
+		t.Errorf("other family: %v", err)
+	}
+}
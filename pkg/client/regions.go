@@ -0,0 +1,82 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A Region describes the endpoints of a Verkada region (shard), selected by name with ClientOptions.Region.
+type Region struct {
+	// Base URL of the public API, e.g. "https://api.verkada.com".
+	BaseURL string
+	// Base URL of the Streaming API, used for the HLS links built by StreamFootage; BaseURL is used if empty.
+	StreamingBaseURL string
+}
+
+var (
+	regionsMu sync.RWMutex
+	regions   = map[string]Region{
+		"prod1": {BaseURL: "https://api.verkada.com"},
+		"prod2": {BaseURL: "https://api.eu.verkada.com"},
+		"au":    {BaseURL: "https://api.au.verkada.com"},
+	}
+)
+
+// Adds or replaces a region that can then be selected by name with ClientOptions.Region,
+// e.g. for a new Verkada shard or a gateway that proxies the API.
+// The built-in regions are "prod1" (US), "prod2" (EU), and "au" (Australia).
+func RegisterRegion(name string, region Region) error {
+	if name == "" {
+		return validationErrorf("region name is empty")
+	}
+	if region.BaseURL == "" {
+		return validationErrorf("region %s has no base URL", name)
+	}
+	regionsMu.Lock()
+	defer regionsMu.Unlock()
+	regions[name] = region
+	return nil
+}
+
+// Returns the region registered under name.
+func LookupRegion(name string) (Region, bool) {
+	regionsMu.RLock()
+	defer regionsMu.RUnlock()
+	region, ok := regions[name]
+	return region, ok
+}
+
+// Returns the names of all registered regions, sorted.
+func Regions() []string {
+	regionsMu.RLock()
+	defer regionsMu.RUnlock()
+	names := make([]string, 0, len(regions))
+	for name := range regions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Resolves the API and streaming base URLs from ClientOptions, where BaseURL and StreamingBaseURL take precedence over Region.
+func resolveBaseURLs(options *ClientOptions) (string, string, error) {
+	var region Region
+	if options.BaseURL != "" {
+		region.BaseURL = options.BaseURL
+	} else {
+		var ok bool
+		region, ok = LookupRegion(options.Region)
+		if !ok {
+			return "", "", fmt.Errorf("error: invalid region/shard, must be one of %q or set BaseURL - received %s", Regions(), options.Region)
+		}
+	}
+	if options.StreamingBaseURL != "" {
+		region.StreamingBaseURL = options.StreamingBaseURL
+	}
+	if region.StreamingBaseURL == "" {
+		region.StreamingBaseURL = region.BaseURL
+	}
+	return strings.TrimSuffix(region.BaseURL, "/"), strings.TrimSuffix(region.StreamingBaseURL, "/"), nil
+}
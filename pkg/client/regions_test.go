@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// Starts a server answering /token and any other request with an empty object, counting the requests for each.
+func newRegionServer(t *testing.T) (srv *httptest.Server, tokens *atomic.Int32, requests *atomic.Int32) {
+	t.Helper()
+	tokens, requests = &atomic.Int32{}, &atomic.Int32{}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokens.Add(1)
+			json.NewEncoder(w).Encode(map[string]string{"token": "token-1"})
+			return
+		}
+		requests.Add(1)
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, tokens, requests
+}
+
+func TestRegisterRegion(t *testing.T) {
+	srv, _, requests := newRegionServer(t)
+	t.Cleanup(func() {
+		regionsMu.Lock()
+		defer regionsMu.Unlock()
+		delete(regions, "gateway")
+	})
+	if err := RegisterRegion("gateway", Region{BaseURL: srv.URL + "/", StreamingBaseURL: "https://stream.example.com/"}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(Regions(), "gateway") {
+		t.Errorf("Regions() = %v, missing gateway", Regions())
+	}
+	c, err := New(&ClientOptions{Region: "gateway", APIKey: "test-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.BaseURL() != srv.URL || c.StreamingBaseURL() != "https://stream.example.com" {
+		t.Errorf("got base URLs %s and %s", c.BaseURL(), c.StreamingBaseURL())
+	}
+	if err := c.MakeVerkadaRequest("GET", c.BaseURL()+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0); err != nil || requests.Load() != 1 {
+		t.Errorf("got %v after %d requests", err, requests.Load())
+	}
+
+	for name, region := range map[string]Region{"": {BaseURL: srv.URL}, "no-url": {StreamingBaseURL: srv.URL}} {
+		if err := RegisterRegion(name, region); !errors.Is(err, ErrValidation) {
+			t.Errorf("RegisterRegion(%q) got %v, want ErrValidation", name, err)
+		}
+		if _, ok := LookupRegion(name); ok {
+			t.Errorf("region %q was registered", name)
+		}
+	}
+}
+
+func TestUnknownRegion(t *testing.T) {
+	_, err := New(&ClientOptions{Region: "mars1", APIKey: "test-key", SkipTokenFetch: true})
+	if err == nil || !strings.Contains(err.Error(), "mars1") || !strings.Contains(err.Error(), `"prod2"`) {
+		t.Errorf("got %v, want an error naming the region and the valid ones", err)
+	}
+}
+
+func TestBaseURLOverridesRegion(t *testing.T) {
+	srv, tokens, requests := newRegionServer(t)
+	c, err := New(&ClientOptions{Region: "prod2", BaseURL: srv.URL, APIKey: "test-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// streaming links follow BaseURL rather than the region
+	if c.BaseURL() != srv.URL || c.StreamingBaseURL() != srv.URL {
+		t.Errorf("got base URLs %s and %s, want %s", c.BaseURL(), c.StreamingBaseURL(), srv.URL)
+	}
+	if err := c.MakeVerkadaRequest("GET", c.BaseURL()+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if tokens.Load() != 1 || requests.Load() != 1 {
+		t.Errorf("server got %d token requests and %d requests, want 1 each", tokens.Load(), requests.Load())
+	}
+	// an invalid region is not used either
+	if _, err := New(&ClientOptions{Region: "mars1", BaseURL: srv.URL, APIKey: "test-key", SkipTokenFetch: true}); err != nil {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestSkipTokenFetch(t *testing.T) {
+	srv, tokens, _ := newRegionServer(t)
+	if _, err := New(&ClientOptions{BaseURL: srv.URL, APIKey: "test-key"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := tokens.Load(); n != 1 {
+		t.Fatalf("New made %d token requests, want 1", n)
+	}
+	c, err := New(&ClientOptions{BaseURL: srv.URL, APIKey: "test-key", SkipTokenFetch: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := tokens.Load(); n != 1 {
+		t.Errorf("New with SkipTokenFetch made %d token requests, want none", n-1)
+	}
+	// nothing is fetched offline either
+	if _, err := New(&ClientOptions{BaseURL: "http://127.0.0.1:1", APIKey: "test-key", SkipTokenFetch: true}); err != nil {
+		t.Errorf("got %v", err)
+	}
+	if err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := tokens.Load(); n != 2 {
+		t.Errorf("first request made %d token requests, want 1", n-1)
+	}
+}
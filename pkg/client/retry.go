@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// A RetryPolicy controls how MakeVerkadaRequest, MakeVerkadaRequestWithFile, and MakeVerkadaRequestForFile retry failed requests.
+// Set on ClientOptions; DefaultRetryPolicy is used if none is provided.
+//
+// Rate limited (429) responses are retried for every HTTP method since the request was never processed.
+// Server errors (5xx) and transient network errors are only retried for idempotent methods (GET, HEAD, OPTIONS, PUT, DELETE).
+type RetryPolicy struct {
+	// Total number of attempts, including the first. Values below 1 are treated as 1 (no retries).
+	MaxAttempts int
+	// Delay before the first retry, doubled for every following retry.
+	BaseDelay time.Duration
+	// Upper bound for any single delay, including one requested by a Retry-After header. Zero means no bound.
+	MaxDelay time.Duration
+	// Fraction (0 to 1) of each delay that is randomized to spread out retries from concurrent callers.
+	Jitter float64
+	// Use the delay from a Retry-After header when the response includes one.
+	RespectRetryAfter bool
+	// Retry 5xx responses for idempotent methods.
+	RetryServerErrors bool
+	// Retry transient network errors (timeouts, resets, refused connections) for idempotent methods.
+	RetryNetworkErrors bool
+	// Optional replacement for the exponential schedule, given the number of retries already made.
+	Backoff func(retry int) time.Duration
+	// Optional hook called before each retry with details of the failed attempt and the chosen delay.
+	OnRetry func(RetryEvent)
+}
+
+// Details of a failed attempt passed to RetryPolicy.OnRetry.
+type RetryEvent struct {
+	// Method and URL of the request, with credentials such as the streaming JWT redacted.
+	Method string
+	URL    string
+	// Number of the attempt that failed, starting at 1.
+	Attempt int
+	// HTTP status of the failed attempt, or 0 if no response was received.
+	StatusCode int
+	// Network error of the failed attempt (with its URL redacted likewise), or nil if a response was received.
+	Err error
+	// Delay before the next attempt.
+	Delay time.Duration
+}
+
+// Returns the RetryPolicy used when ClientOptions.RetryPolicy is nil.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:        5,
+		BaseDelay:          500 * time.Millisecond,
+		MaxDelay:           30 * time.Second,
+		Jitter:             0.2,
+		RespectRetryAfter:  true,
+		RetryServerErrors:  true,
+		RetryNetworkErrors: true,
+	}
+}
+
+// Reports whether another attempt should be made after a response (res) or a network error (err).
+func (p *RetryPolicy) shouldRetry(method string, res *http.Response, err error) bool {
+	if err != nil {
+		return p.RetryNetworkErrors && isIdempotent(method) && isTransient(err)
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return p.RetryServerErrors && isIdempotent(method) && res.StatusCode >= 500 && res.StatusCode != http.StatusNotImplemented
+}
+
+// Delay before the next attempt, given the number of retries already made and the failed response (if any).
+func (p *RetryPolicy) delay(retry int, res *http.Response) time.Duration {
+	if p.RespectRetryAfter && res != nil {
+		if d, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			if p.MaxDelay > 0 && d > p.MaxDelay {
+				d = p.MaxDelay
+			}
+			return d
+		}
+	}
+	var d time.Duration
+	if p.Backoff != nil {
+		d = p.Backoff(retry)
+	} else {
+		d = time.Duration(float64(p.BaseDelay) * math.Pow(2, float64(retry)))
+	}
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d < 0) {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 && d > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		d = time.Duration(float64(d) * (1 - jitter + 2*jitter*rand.Float64()))
+	}
+	return d
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Network errors worth retrying; context cancellation and deadlines are never retried.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// Parses a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
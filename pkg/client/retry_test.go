@@ -0,0 +1,149 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statuses     []int
+		maxAttempts  int
+		wantAttempts int
+		wantErr      error
+	}{
+		{"success", "GET", []int{200}, 5, 1, nil},
+		{"server errors then success", "GET", []int{503, 502, 200}, 5, 3, nil},
+		{"server errors exhaust attempts", "GET", []int{500, 500, 500, 500}, 3, 3, ErrServer},
+		{"rate limited then success", "GET", []int{429, 200}, 5, 2, nil},
+		{"not implemented is not retried", "GET", []int{501, 200}, 5, 1, ErrServer},
+		{"client errors are not retried", "GET", []int{404, 200}, 5, 1, ErrNotFound},
+		{"put is idempotent", "PUT", []int{500, 200}, 5, 2, nil},
+		{"post server error is not retried", "POST", []int{500, 200}, 5, 1, ErrServer},
+		{"patch server error is not retried", "PATCH", []int{503, 200}, 5, 1, ErrServer},
+		{"post rate limited is retried", "POST", []int{429, 429, 200}, 5, 3, nil},
+		{"single attempt", "GET", []int{503, 200}, 1, 1, ErrServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			var events []RetryEvent
+			policy := &RetryPolicy{MaxAttempts: tt.maxAttempts, RetryServerErrors: true, OnRetry: func(e RetryEvent) { events = append(events, e) }}
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1))
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses))-1])
+				w.Write([]byte(`{}`))
+			}, &ClientOptions{RetryPolicy: policy})
+			err := c.MakeVerkadaRequest(tt.method, srv.URL+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got := int(attempts.Load()); got != tt.wantAttempts {
+				t.Errorf("made %d attempts, want %d", got, tt.wantAttempts)
+			}
+			if len(events) != tt.wantAttempts-1 {
+				t.Fatalf("OnRetry called %d times, want %d", len(events), tt.wantAttempts-1)
+			}
+			for i, e := range events {
+				if e.Attempt != i+1 || e.StatusCode != tt.statuses[i] || e.Method != tt.method {
+					t.Errorf("event %d is %+v", i, e)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryAfterHonored(t *testing.T) {
+	var events []RetryEvent
+	// a BaseDelay of an hour would time out the test unless Retry-After is used instead
+	policy := &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, RespectRetryAfter: true, OnRetry: func(e RetryEvent) { events = append(events, e) }}
+	var attempts atomic.Int32
+	c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}, &ClientOptions{RetryPolicy: policy})
+	params := struct {
+		Jwt string `name:"jwt"`
+	}{"secret-jwt"}
+	if err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/alerts", params, nil, &map[string]any{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Delay != 0 {
+		t.Fatalf("got retry events %+v, want one with no delay", events)
+	}
+	if strings.Contains(events[0].URL, "secret-jwt") || !strings.Contains(events[0].URL, "jwt=REDACTED") {
+		t.Errorf("RetryEvent.URL %s is not redacted", events[0].URL)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	header := func(v string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": {v}}}
+	}
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		res    *http.Response
+		want   time.Duration
+	}{
+		{"exponential", RetryPolicy{BaseDelay: time.Second}, 3, nil, 8 * time.Second},
+		{"capped", RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 10, nil, 5 * time.Second},
+		{"retry after seconds", RetryPolicy{BaseDelay: time.Second, RespectRetryAfter: true}, 0, header("7"), 7 * time.Second},
+		{"retry after capped", RetryPolicy{MaxDelay: 2 * time.Second, RespectRetryAfter: true}, 0, header("60"), 2 * time.Second},
+		{"retry after ignored", RetryPolicy{BaseDelay: time.Second}, 1, header("60"), 2 * time.Second},
+		{"retry after invalid", RetryPolicy{BaseDelay: time.Second, RespectRetryAfter: true}, 0, header("soon"), time.Second},
+		{"retry after date in the past", RetryPolicy{BaseDelay: time.Second, RespectRetryAfter: true}, 0, header("Mon, 02 Jan 2006 15:04:05 GMT"), 0},
+		{"backoff", RetryPolicy{Backoff: func(retry int) time.Duration { return time.Duration(retry) * time.Minute }}, 2, nil, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.delay(tt.retry, tt.res); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryNetworkErrors(t *testing.T) {
+	tests := []struct {
+		method       string
+		wantAttempts int
+	}{
+		{"GET", 3},
+		{"DELETE", 3},
+		{"POST", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			var events []RetryEvent
+			policy := &RetryPolicy{MaxAttempts: 3, RetryNetworkErrors: true, OnRetry: func(e RetryEvent) { events = append(events, e) }}
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				// closes the connection without a response
+				panic(http.ErrAbortHandler)
+			}, &ClientOptions{RetryPolicy: policy})
+			err := c.MakeVerkadaRequest(tt.method, srv.URL+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if len(events) != tt.wantAttempts-1 {
+				t.Errorf("retried %d times, want %d: %v", len(events), tt.wantAttempts-1, err)
+			}
+			for _, e := range events {
+				if e.Err == nil || e.StatusCode != 0 {
+					t.Errorf("event %+v has no network error", e)
+				}
+			}
+		})
+	}
+}
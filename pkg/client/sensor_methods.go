@@ -1,6 +1,6 @@
 package client
 
-import "fmt"
+import "context"
 
 // Returns all alerts for all (or subset of) sensors in an org over a specified time range.
 //
@@ -8,13 +8,64 @@ import "fmt"
 //
 // [Verkada API Docs - Get Sensor Alerts]: https://apidocs.verkada.com/reference/getsensoralertsviewv1
 func (c *SensorClient) GetSensorAlerts(device_ids []string, options *GetSensorAlertsOptions) (*GetSensorAlertsResponse, error) {
+	return c.GetSensorAlertsContext(context.Background(), device_ids, options)
+}
+
+// Same as GetSensorAlerts, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *SensorClient) GetSensorAlertsContext(ctx context.Context, device_ids []string, options *GetSensorAlertsOptions) (*GetSensorAlertsResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Sensor.GetSensorAlerts")
+	defer span.End()
+	if options == nil {
+		options = &GetSensorAlertsOptions{}
+	}
+	options.device_ids = device_ids
+	if err := validateGetSensorAlertsOptions(options); err != nil {
+		return nil, err
+	}
+	var ret GetSensorAlertsResponse
+	url := c.client.baseURL + "/environment/v1/alerts"
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c.client.AutoPaginate {
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.sensorAlertsPages(*options), &ret.Alert_events)
+	}
+	return &ret, err
+}
+
+// Same as GetSensorAlerts, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *SensorClient) GetSensorAlertsIter(ctx context.Context, device_ids []string, options *GetSensorAlertsOptions) *Paginator[SensorAlertEvent] {
+	ctx = c.client.withOperation(ctx, "Sensor.GetSensorAlerts")
 	if options == nil {
 		options = &GetSensorAlertsOptions{}
 	}
 	options.device_ids = device_ids
+	if err := validateGetSensorAlertsOptions(options); err != nil {
+		return errPaginator[SensorAlertEvent](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.sensorAlertsPages(*options))
+}
+
+// Returns the page function shared by GetSensorAlertsContext and GetSensorAlertsIter.
+func (c *SensorClient) sensorAlertsPages(options GetSensorAlertsOptions) pageFunc[SensorAlertEvent] {
+	url := c.client.baseURL + "/environment/v1/alerts"
+	return func(ctx context.Context, token string) ([]SensorAlertEvent, string, error) {
+		options.Page_token = token
+		var ret GetSensorAlertsResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Alert_events, ret.Next_page_token, nil
+	}
+}
+
+func validateGetSensorAlertsOptions(options *GetSensorAlertsOptions) error {
 	// page_size must be between 1 and 200
 	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
 	}
 	// Notification type must be one of the following:
 	fields_validation := map[string]bool{
@@ -39,28 +90,10 @@ func (c *SensorClient) GetSensorAlerts(device_ids []string, options *GetSensorAl
 	}
 	for _, param := range options.Fields {
 		if ok := fields_validation[param]; !ok {
-			return nil, fmt.Errorf("could not validate parameter in fields: %s", param)
+			return validationErrorf("could not validate parameter in fields: %s", param)
 		}
 	}
-	var ret GetSensorAlertsResponse
-	url := c.client.baseURL + "/environment/v1/alerts"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
-	if err != nil {
-		return nil, err
-	}
-	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetSensorAlertsResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Alert_events = append(ret.Alert_events, nextRet.Alert_events...)
-			ret.Next_page_token = nextRet.Next_page_token
-		}
-	}
-	return &ret, err
+	return nil
 }
 
 // Returns all sensor readings for a particular sensor over a specified time range.
@@ -69,13 +102,64 @@ func (c *SensorClient) GetSensorAlerts(device_ids []string, options *GetSensorAl
 //
 // [Verkada API Docs - Get Sensor Data]: https://apidocs.verkada.com/reference/getsensordataviewv1
 func (c *SensorClient) GetSensorData(device_id string, options *GetSensorDataOptions) (*GetSensorDataResponse, error) {
+	return c.GetSensorDataContext(context.Background(), device_id, options)
+}
+
+// Same as GetSensorData, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *SensorClient) GetSensorDataContext(ctx context.Context, device_id string, options *GetSensorDataOptions) (*GetSensorDataResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Sensor.GetSensorData")
+	defer span.End()
+	if options == nil {
+		options = &GetSensorDataOptions{}
+	}
+	options.device_id = device_id
+	if err := validateGetSensorDataOptions(options); err != nil {
+		return nil, err
+	}
+	var ret GetSensorDataResponse
+	url := c.client.baseURL + "/environment/v1/data"
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, *options, nil, &ret, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c.client.AutoPaginate {
+		ret.Next_page_token, err = collectPages(ctx, ret.Next_page_token, c.sensorDataPages(*options), &ret.Data)
+	}
+	return &ret, err
+}
+
+// Same as GetSensorData, returning a Paginator that lazily requests one page at a time as its items are ranged over.
+// Iteration starts at options.Page_token if set, and is unaffected by Client.AutoPaginate.
+func (c *SensorClient) GetSensorDataIter(ctx context.Context, device_id string, options *GetSensorDataOptions) *Paginator[SensorReading] {
+	ctx = c.client.withOperation(ctx, "Sensor.GetSensorData")
 	if options == nil {
 		options = &GetSensorDataOptions{}
 	}
 	options.device_id = device_id
+	if err := validateGetSensorDataOptions(options); err != nil {
+		return errPaginator[SensorReading](err)
+	}
+	return newPaginator(ctx, options.Page_token, c.sensorDataPages(*options))
+}
+
+// Returns the page function shared by GetSensorDataContext and GetSensorDataIter.
+func (c *SensorClient) sensorDataPages(options GetSensorDataOptions) pageFunc[SensorReading] {
+	url := c.client.baseURL + "/environment/v1/data"
+	return func(ctx context.Context, token string) ([]SensorReading, string, error) {
+		options.Page_token = token
+		var ret GetSensorDataResponse
+		err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, options, nil, &ret, 0)
+		if err != nil {
+			return nil, "", err
+		}
+		return ret.Data, ret.Next_page_token, nil
+	}
+}
+
+func validateGetSensorDataOptions(options *GetSensorDataOptions) error {
 	// page_size must be between 1 and 200
 	if options.Page_size != nil && (*options.Page_size < 1 || *options.Page_size > 200) {
-		return nil, fmt.Errorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
+		return validationErrorf("parameter page_size (%d) is not between 1 and 200", *options.Page_size)
 	}
 	// Notification type must be one of the following:
 	fields_validation := map[string]bool{
@@ -100,26 +184,8 @@ func (c *SensorClient) GetSensorData(device_id string, options *GetSensorDataOpt
 	}
 	for _, param := range options.Fields {
 		if ok := fields_validation[param]; !ok {
-			return nil, fmt.Errorf("could not validate parameter in fields: %s", param)
+			return validationErrorf("could not validate parameter in fields: %s", param)
 		}
 	}
-	var ret GetSensorDataResponse
-	url := c.client.baseURL + "/environment/v1/data"
-	err := c.client.MakeVerkadaRequest("GET", url, *options, nil, &ret, 0)
-	if err != nil {
-		return nil, err
-	}
-	if c.client.AutoPaginate {
-		for ret.Next_page_token != "" {
-			var nextRet GetSensorDataResponse
-			options.Page_token = ret.Next_page_token
-			err = c.client.MakeVerkadaRequest("GET", url, *options, nil, &nextRet, 0)
-			if err != nil {
-				return &ret, err
-			}
-			ret.Data = append(ret.Data, nextRet.Data...)
-			ret.Next_page_token = nextRet.Next_page_token
-		}
-	}
-	return &ret, err
+	return nil
 }
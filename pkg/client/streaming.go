@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// How long before it expires a StreamingTokenSource replaces its JWT when created with a refresh window of zero.
+const DefaultStreamingRefreshWindow = time.Minute
+
+// Permissions listed by GetStreamingTokenResponse.Permission.
+const (
+	StreamingPermissionLive       = "live"
+	StreamingPermissionHistorical = "historical"
+)
+
+// A StreamingTokenSource caches the streaming JWT returned by GetStreamingToken, shared safely between goroutines.
+// The JWT is replaced shortly before its ExpiresAt, or when a request reports it was rejected with Refresh.
+//
+// The scope of the token (AccessibleCameras and Permission) is checked locally by JWTForCamera and StreamingLink, so
+// that a camera the API key cannot stream fails with a *StreamingScopeError before any link is built or footage is
+// requested. The API remains the authority: a token listing no cameras or no permissions is not checked locally, and
+// neither are the cameras of a token listing AccessibleSites, since the site of a camera is not known locally.
+//
+// Client.StreamingTokens is the source used by StreamFootage, DownloadFootage, and ExportFootage when no JWT is given.
+type StreamingTokenSource struct {
+	camera        *CameraClient
+	refreshWindow time.Duration
+
+	mu    sync.Mutex
+	token *GetStreamingTokenResponse
+	// zero if the response has no expiry, in which case the JWT is used until it is rejected
+	expires time.Time
+	fetched time.Time
+	// in-flight refresh shared by all callers, nil if none
+	call *streamingRefresh
+}
+
+type streamingRefresh struct {
+	done  chan struct{}
+	token *GetStreamingTokenResponse
+	err   error
+}
+
+// Returns a StreamingTokenSource requesting tokens with c, replacing them refreshWindow before they expire
+// (DefaultStreamingRefreshWindow if zero or less).
+func NewStreamingTokenSource(c *Client, refreshWindow time.Duration) *StreamingTokenSource {
+	if refreshWindow <= 0 {
+		refreshWindow = DefaultStreamingRefreshWindow
+	}
+	return &StreamingTokenSource{camera: c.Camera, refreshWindow: refreshWindow}
+}
+
+// Returns the current token, requesting a new one if there is none or it is about to expire.
+// However many callers need a new token at once, only one request is made; each stops waiting for it when its ctx is done.
+// The response is shared and must not be modified.
+func (s *StreamingTokenSource) Token(ctx context.Context) (*GetStreamingTokenResponse, error) {
+	s.mu.Lock()
+	if s.token != nil && (s.expires.IsZero() || time.Until(s.expires) > s.refreshWindow) {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+	call := s.refreshLocked(ctx)
+	s.mu.Unlock()
+	return call.wait(ctx)
+}
+
+// Returns the JWT of the current token.
+func (s *StreamingTokenSource) JWT(ctx context.Context) (string, error) {
+	token, err := s.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	return token.Jwt, nil
+}
+
+// Replaces a JWT that was rejected, unless it has already been replaced, and returns the new one.
+func (s *StreamingTokenSource) Refresh(ctx context.Context, stale string) (string, error) {
+	s.mu.Lock()
+	if s.token != nil && s.token.Jwt != stale {
+		jwt := s.token.Jwt
+		s.mu.Unlock()
+		return jwt, nil
+	}
+	call := s.refreshLocked(ctx)
+	s.mu.Unlock()
+	token, err := call.wait(ctx)
+	if err != nil {
+		return "", err
+	}
+	return token.Jwt, nil
+}
+
+// Returns when the current JWT expires, or the zero time if there is none or its expiry is unknown.
+func (s *StreamingTokenSource) Expires() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.expires
+}
+
+// Returns the JWT after checking locally that it grants permission (StreamingPermissionLive or
+// StreamingPermissionHistorical, or empty to check only the camera) for camera_id.
+// If it does not, the token is replaced in case the camera was added since it was issued (unless it was issued within
+// the refresh window), and a *StreamingScopeError is returned if the new token does not grant it either.
+func (s *StreamingTokenSource) JWTForCamera(ctx context.Context, camera_id string, permission string) (string, error) {
+	token, err := s.Token(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := checkStreamingScope(token, camera_id, permission); err == nil {
+		return token.Jwt, nil
+	}
+	s.mu.Lock()
+	// another caller may have replaced the token meanwhile; avoid replacing a token that was just issued
+	if s.token.Jwt == token.Jwt && time.Since(s.fetched) > s.refreshWindow {
+		call := s.refreshLocked(ctx)
+		s.mu.Unlock()
+		if token, err = call.wait(ctx); err != nil {
+			return "", err
+		}
+	} else {
+		token = s.token
+		s.mu.Unlock()
+	}
+	if err := checkStreamingScope(token, camera_id, permission); err != nil {
+		return "", err
+	}
+	return token.Jwt, nil
+}
+
+// Same as StreamFootage without a file, using the JWT of s after checking that it grants access to camera_id:
+// "historical" if options has a start time, "live" otherwise.
+func (s *StreamingTokenSource) StreamingLink(ctx context.Context, org_id string, camera_id string, options *GetFootageOptions) (*StreamFootageResponse, error) {
+	if options == nil {
+		options = &GetFootageOptions{}
+	}
+	jwt, err := s.JWTForCamera(ctx, camera_id, footagePermission(options))
+	if err != nil {
+		return nil, err
+	}
+	return s.camera.StreamFootageContext(ctx, org_id, camera_id, jwt, options, "")
+}
+
+// Returns the in-flight refresh, starting one if there is none. s.mu must be held.
+// The request keeps the values of ctx but not its cancellation, so that one caller giving up does not fail the others
+// waiting on it.
+func (s *StreamingTokenSource) refreshLocked(ctx context.Context) *streamingRefresh {
+	if s.call != nil {
+		return s.call
+	}
+	call := &streamingRefresh{done: make(chan struct{})}
+	s.call = call
+	go func() {
+		// bounded so that a hung token endpoint cannot block every future caller
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+		res, err := s.camera.GetStreamingTokenContext(ctx)
+		if err == nil && res.Jwt == "" {
+			err = fmt.Errorf("streaming token response has no jwt")
+		}
+		call.token, call.err = res, err
+		s.mu.Lock()
+		if err == nil {
+			s.token, s.fetched = res, time.Now()
+			switch {
+			case res.ExpiresAt > 1e12:
+				s.expires = time.UnixMilli(int64(res.ExpiresAt))
+			case res.ExpiresAt > 0:
+				s.expires = time.Unix(int64(res.ExpiresAt), 0)
+			case res.Expiration > 0:
+				s.expires = s.fetched.Add(time.Duration(res.Expiration) * time.Second)
+			default:
+				s.expires = time.Time{}
+			}
+			s.camera.client.logger.DebugContext(ctx, "verkada streaming token refreshed", "expires", s.expires)
+		}
+		if s.call == call {
+			s.call = nil
+		}
+		s.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// Waits for the refresh to finish, returning early with the context's error if ctx is done first.
+func (call *streamingRefresh) wait(ctx context.Context) (*GetStreamingTokenResponse, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+		return call.token, call.err
+	}
+}
+
+// Returns the permission needed to stream footage with options.
+func footagePermission(options *GetFootageOptions) string {
+	if options.Start_time != nil {
+		return StreamingPermissionHistorical
+	}
+	return StreamingPermissionLive
+}
+
+// Returns a *StreamingScopeError if token does not grant permission for camera_id. Empty lists are not checked, and
+// cameras are not checked if the token grants any site, as camera_id may belong to one of them.
+func checkStreamingScope(token *GetStreamingTokenResponse, camera_id string, permission string) error {
+	if len(token.AccessibleCameras) > 0 && len(token.AccessibleSites) == 0 && !slices.Contains(token.AccessibleCameras, camera_id) {
+		return &StreamingScopeError{Camera_id: camera_id, AccessibleCameras: token.AccessibleCameras, AccessibleSites: token.AccessibleSites, Permissions: token.Permission}
+	}
+	if permission != "" && len(token.Permission) > 0 && !slices.Contains(token.Permission, permission) {
+		return &StreamingScopeError{Camera_id: camera_id, Permission: permission, AccessibleCameras: token.AccessibleCameras, AccessibleSites: token.AccessibleSites, Permissions: token.Permission}
+	}
+	return nil
+}
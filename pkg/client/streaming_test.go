@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamingTokenSingleFlight(t *testing.T) {
+	// streaming token requests block until release is closed
+	release := make(chan struct{})
+	var requests atomic.Int32
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		<-release
+		fmt.Fprintf(w, `{"jwt": "streaming-jwt-%d", "expiresAt": %d}`, n, time.Now().Add(30*time.Minute).Unix())
+	}, nil)
+	s := c.StreamingTokens
+
+	// a caller whose ctx ends stops waiting, without failing the refresh for the others
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := s.JWT(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("waited %v for a refresh after ctx ended", elapsed)
+	}
+
+	var wg sync.WaitGroup
+	jwts := make([]string, 20)
+	errs := make([]error, len(jwts))
+	for i := range jwts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jwts[i], errs[i] = s.JWT(context.Background())
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for i := range jwts {
+		if errs[i] != nil || jwts[i] != "streaming-jwt-1" {
+			t.Errorf("caller %d got %q, %v", i, jwts[i], errs[i])
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("made %d streaming token requests, want 1", n)
+	}
+	if jwt, err := s.Refresh(context.Background(), "streaming-jwt-1"); err != nil || jwt != "streaming-jwt-2" {
+		t.Errorf("Refresh got %q, %v", jwt, err)
+	}
+	if jwt, err := s.Refresh(context.Background(), "streaming-jwt-1"); err != nil || jwt != "streaming-jwt-2" || requests.Load() != 2 {
+		t.Errorf("Refresh of a replaced JWT got %q, %v after %d requests", jwt, err, requests.Load())
+	}
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	neturl "net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// Name of the tracer and meter obtained from ClientOptions.TracerProvider and ClientOptions.MeterProvider.
+const instrumentationName = "github.com/GDRCode/verkada-api-go/pkg/client"
+
+// OpenTelemetry instruments shared by every request of a Client.
+// Both providers default to no-op implementations, so an uninstrumented Client pays almost nothing for them.
+type telemetry struct {
+	tracer trace.Tracer
+	// attributes identifying the Client, added to every span and measurement
+	attrs []attribute.KeyValue
+
+	duration       metric.Float64Histogram
+	rateLimited    metric.Int64Counter
+	retries        metric.Int64Counter
+	tokenRefreshes metric.Int64Counter
+	cacheLookups   metric.Int64Counter
+}
+
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider, region string, baseURL string) (*telemetry, error) {
+	if tp == nil {
+		tp = tracenoop.NewTracerProvider()
+	}
+	if mp == nil {
+		mp = metricnoop.NewMeterProvider()
+	}
+	// server.address is the host name alone, as in the OpenTelemetry semantic conventions
+	host := baseURL
+	if u, err := neturl.Parse(baseURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	t := &telemetry{
+		tracer: tp.Tracer(instrumentationName),
+		attrs: []attribute.KeyValue{
+			attribute.String("verkada.region", region),
+			attribute.String("server.address", host),
+		},
+	}
+	meter := mp.Meter(instrumentationName)
+	var err error
+	t.duration, err = meter.Float64Histogram("verkada.client.request.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duration of Verkada API requests, per attempt."),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30))
+	if err != nil {
+		return nil, err
+	}
+	t.rateLimited, err = meter.Int64Counter("verkada.client.rate_limited",
+		metric.WithUnit("{response}"), metric.WithDescription("Number of 429 responses from the Verkada API."))
+	if err != nil {
+		return nil, err
+	}
+	t.retries, err = meter.Int64Counter("verkada.client.retries",
+		metric.WithUnit("{retry}"), metric.WithDescription("Number of Verkada API requests retried."))
+	if err != nil {
+		return nil, err
+	}
+	t.tokenRefreshes, err = meter.Int64Counter("verkada.client.token.refreshes",
+		metric.WithUnit("{refresh}"), metric.WithDescription("Number of auth token requests, by outcome."))
+	if err != nil {
+		return nil, err
+	}
+	t.cacheLookups, err = meter.Int64Counter("verkada.client.cache.lookups",
+		metric.WithUnit("{lookup}"), metric.WithDescription("Number of response cache lookups, by result (hit, stale, or miss)."))
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// The product method a request is made for, e.g. "Camera.GetAlerts", carried by the contexts of its requests.
+type operation struct {
+	name      string
+	telemetry *telemetry
+}
+
+type operationKey struct{}
+
+// Starts the span of a product method named operation, e.g. "Camera.GetAlerts".
+// Requests made with the returned context annotate the span, and any pages fetched with it become child spans.
+func (c *Client) startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	ctx = c.withOperation(ctx, operation)
+	return c.telemetry.tracer.Start(ctx, operation, trace.WithAttributes(c.telemetry.attrs...))
+}
+
+// Marks requests made with ctx as belonging to operation without starting a span, used by Iter methods
+// whose pages are fetched long after the method returns.
+func (c *Client) withOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, &operation{name: name, telemetry: c.telemetry})
+}
+
+// Returns the operation set by startSpan or withOperation, or nil.
+func operationFromContext(ctx context.Context) *operation {
+	op, _ := ctx.Value(operationKey{}).(*operation)
+	return op
+}
+
+// Starts a child span for page n of a paginated operation, if ctx carries one; otherwise the returned span does nothing.
+func startPageSpan(ctx context.Context, n int) (context.Context, trace.Span) {
+	op := operationFromContext(ctx)
+	if op == nil {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	attrs := append([]attribute.KeyValue{attribute.Int("verkada.page", n)}, op.telemetry.attrs...)
+	return op.telemetry.tracer.Start(ctx, op.name+" page", trace.WithAttributes(attrs...))
+}
+
+// Records the outcome of one attempt of a request: its latency, and whether it was rate limited.
+func (t *telemetry) recordAttempt(ctx context.Context, op string, method string, status int, latency time.Duration) {
+	attrs := metric.WithAttributes(append([]attribute.KeyValue{
+		attribute.String("verkada.operation", op),
+		attribute.String("http.request.method", method),
+		attribute.Int("http.response.status_code", status),
+	}, t.attrs...)...)
+	t.duration.Record(ctx, latency.Seconds(), attrs)
+	if status == http.StatusTooManyRequests {
+		t.rateLimited.Add(ctx, 1, attrs)
+	}
+}
+
+// Records a retry of a request.
+func (t *telemetry) recordRetry(ctx context.Context, op string, method string) {
+	t.retries.Add(ctx, 1, metric.WithAttributes(append([]attribute.KeyValue{
+		attribute.String("verkada.operation", op),
+		attribute.String("http.request.method", method),
+	}, t.attrs...)...))
+}
+
+// Records an auth token request, passed to auth.TokenSource.OnRefresh.
+func (t *telemetry) recordTokenRefresh(ctx context.Context, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	t.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(append([]attribute.KeyValue{
+		attribute.String("verkada.outcome", outcome),
+	}, t.attrs...)...))
+}
+
+// Records a response cache lookup and sets its result on the current span.
+func (t *telemetry) recordCacheLookup(ctx context.Context, url string, result string) {
+	var op string
+	if o := operationFromContext(ctx); o != nil {
+		op = o.name
+	}
+	t.cacheLookups.Add(ctx, 1, metric.WithAttributes(append([]attribute.KeyValue{
+		attribute.String("verkada.operation", op),
+		attribute.String("url.path", endpointOf(url)),
+		attribute.String("verkada.cache.result", result),
+	}, t.attrs...)...))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("verkada.cache.result", result))
+}
+
+// Sets the final status of a request on span.
+func endRequestSpan(span trace.Span, endpoint string, status int, retries int, err error) {
+	span.SetAttributes(
+		attribute.String("url.path", endpoint),
+		attribute.Int("verkada.retries", retries),
+	)
+	if status != 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
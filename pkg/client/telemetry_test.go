@@ -0,0 +1,29 @@
+package client
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestTelemetryServerAddress(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"https://api.verkada.com", "api.verkada.com"},
+		{"https://api.au.verkada.com/", "api.au.verkada.com"},
+		{"http://127.0.0.1:8080", "127.0.0.1"},
+		{"http://[::1]:8080/prefix", "::1"},
+	}
+	for _, tt := range tests {
+		tel, err := newTelemetry(nil, nil, "prod1", tt.baseURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set := attribute.NewSet(tel.attrs...)
+		if got, _ := set.Value("server.address"); got.AsString() != tt.want {
+			t.Errorf("server.address for %s is %q, want %q", tt.baseURL, got.AsString(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Output formats of CreateTimelapse.
+const (
+	TimelapseGIF = "gif"
+	// Motion-JPEG in an AVI container.
+	TimelapseAVI = "avi"
+)
+
+// Number of thumbnails CreateTimelapse fetches at once when TimelapseOptions.Parallelism is not set.
+const DefaultTimelapseParallelism = 4
+
+// Frames per second of a timelapse when TimelapseOptions.FrameRate is not set.
+const DefaultTimelapseFrameRate = 10
+
+// Most frames a timelapse may have, since every frame is held in memory until the output is written.
+const MaxTimelapseFrames = 10000
+
+// Options for CreateTimelapse.
+type TimelapseOptions struct {
+	// Unix timestamps (seconds) of the first frame and of the end of the range, which gets a frame if it falls on the
+	// interval. Both are required.
+	Start_time *int
+	End_time   *int
+	// Seconds between frames. Required.
+	Interval int
+	// TimelapseGIF (the default) or TimelapseAVI.
+	Format string
+	// Frames per second of the output; DefaultTimelapseFrameRate if zero. GIF frame delays are rounded to 1/100 s.
+	FrameRate int
+	// "low-res" or "hi-res", as for GetThumbnailImageOptions. Thumbnail links have no resolution.
+	Resolution string
+	// Width in pixels the frames are scaled down to, keeping their aspect ratio; the thumbnails' own width if zero.
+	Width int
+	// Request each thumbnail with GetThumbnailLink and download the returned link, instead of with GetThumbnailImage.
+	UseLinks bool
+	// Number of thumbnails fetched at once; DefaultTimelapseParallelism if zero or less.
+	Parallelism int
+	// Most thumbnail requests per second, on top of any RateLimiter of the Client; unlimited if zero.
+	RequestsPerSecond float64
+	// Burn the time of each frame into its top-left corner, in Location (UTC if nil).
+	Timestamps bool
+	Location   *time.Location
+	// Leave out frames whose thumbnail is not found (404) or not available (400), e.g. while the camera was offline,
+	// instead of failing.
+	SkipMissing bool
+}
+
+// The result of CreateTimelapse.
+type TimelapseResponse struct {
+	Format string
+	// Number of frames written, and of frames left out because of SkipMissing.
+	Frames  int
+	Skipped int
+	// Size of the frames in pixels.
+	Width  int
+	Height int
+	// Number of bytes written.
+	Bytes int64
+}
+
+// Creates a timelapse of a camera from thumbnails taken every Interval seconds between Start_time and End_time,
+// and writes it to w as an animated GIF or a Motion-JPEG AVI.
+//
+// Thumbnails are fetched with GetThumbnailImage (or GetThumbnailLink, with UseLinks), at most Parallelism at once and
+// at most RequestsPerSecond, and decoded with the standard library image packages. Every frame takes the size of the
+// first one, or Width if it is smaller. GIF frames are reduced to the Plan 9 palette with Floyd-Steinberg dithering.
+// AVI frames are the thumbnails themselves unless they are scaled or timestamped, in which case they are re-encoded.
+//
+// Nothing is written to w until every thumbnail has been fetched, since both formats start with the number of frames.
+func (c *CameraClient) CreateTimelapse(camera_id string, options *TimelapseOptions, w io.Writer) (*TimelapseResponse, error) {
+	return c.CreateTimelapseContext(context.Background(), camera_id, options, w)
+}
+
+// Same as CreateTimelapse, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *CameraClient) CreateTimelapseContext(ctx context.Context, camera_id string, options *TimelapseOptions, w io.Writer) (*TimelapseResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "Camera.CreateTimelapse")
+	defer span.End()
+	if options == nil {
+		options = &TimelapseOptions{}
+	}
+	times, err := timelapseTimes(options)
+	if err != nil {
+		return nil, err
+	}
+	t := &timelapse{camera: c, camera_id: camera_id, options: options, format: options.Format, location: options.Location}
+	if t.format == "" {
+		t.format = TimelapseGIF
+	}
+	if t.location == nil {
+		t.location = time.UTC
+	}
+	if options.RequestsPerSecond > 0 {
+		t.limiter = NewRateLimiter(map[EndpointFamily]Rate{FamilyDefault: {PerSecond: options.RequestsPerSecond, Burst: 1}})
+	}
+	ret := &TimelapseResponse{Format: t.format}
+	frames, err := t.frames(ctx, times, ret)
+	if err != nil {
+		return ret, err
+	}
+	if len(frames) == 0 {
+		return ret, fmt.Errorf("no thumbnail of camera %s could be fetched for the timelapse", camera_id)
+	}
+	frameRate := options.FrameRate
+	if frameRate == 0 {
+		frameRate = DefaultTimelapseFrameRate
+	}
+	cw := &countingWriter{w: w}
+	if t.format == TimelapseAVI {
+		jpegs := make([][]byte, len(frames))
+		for i, f := range frames {
+			jpegs[i] = f.jpeg
+		}
+		err = writeAVI(cw, jpegs, ret.Width, ret.Height, frameRate)
+	} else {
+		g := &gif.GIF{Image: make([]*image.Paletted, len(frames)), Delay: make([]int, len(frames))}
+		for i, f := range frames {
+			g.Image[i], g.Delay[i] = f.paletted, max(1, (100+frameRate/2)/frameRate)
+		}
+		err = gif.EncodeAll(cw, g)
+	}
+	ret.Frames, ret.Bytes = len(frames), cw.n
+	return ret, err
+}
+
+// Validates options and returns the timestamps of the frames.
+func timelapseTimes(options *TimelapseOptions) ([]int, error) {
+	if options.Start_time == nil || options.End_time == nil {
+		return nil, validationErrorf("start_time and end_time are required to create a timelapse")
+	}
+	if *options.End_time < *options.Start_time {
+		return nil, validationErrorf("end_time must not be before start_time - received %d - %d", *options.End_time, *options.Start_time)
+	}
+	if options.Interval < 1 {
+		return nil, validationErrorf("interval must be at least 1 second - received %d", options.Interval)
+	}
+	switch options.Format {
+	case "", TimelapseGIF, TimelapseAVI:
+	default:
+		return nil, validationErrorf("could not validate format parameter: %s", options.Format)
+	}
+	if options.FrameRate < 0 || options.Width < 0 {
+		return nil, validationErrorf("frame rate and width must not be negative - received %d and %d", options.FrameRate, options.Width)
+	}
+	if err := validateThumbnailResolution(options.Resolution); err != nil {
+		return nil, err
+	}
+	n := (*options.End_time-*options.Start_time)/options.Interval + 1
+	if n > MaxTimelapseFrames {
+		return nil, validationErrorf("a timelapse is limited to %d frames - received a range of %d frames", MaxTimelapseFrames, n)
+	}
+	times := make([]int, n)
+	for i := range times {
+		times[i] = *options.Start_time + i*options.Interval
+	}
+	return times, nil
+}
+
+// The state of a CreateTimelapse call.
+type timelapse struct {
+	camera    *CameraClient
+	camera_id string
+	options   *TimelapseOptions
+	format    string
+	location  *time.Location
+	limiter   *RateLimiter
+}
+
+// A frame ready to be written: paletted for GIF, JPEG data for AVI.
+type timelapseFrame struct {
+	paletted *image.Paletted
+	jpeg     []byte
+}
+
+type timelapseResult struct {
+	frame timelapseFrame
+	err   error
+}
+
+// Fetches and converts the frames of times, in order. The first thumbnail is fetched on its own to set the size of
+// the frames, and the others concurrently.
+func (t *timelapse) frames(ctx context.Context, times []int, ret *TimelapseResponse) ([]timelapseFrame, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var frames []timelapseFrame
+	for len(times) > 0 && ret.Width == 0 {
+		timestamp := times[0]
+		times = times[1:]
+		img, format, data, err := t.fetch(ctx, timestamp)
+		if t.missing(err) {
+			ret.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		size := img.Bounds().Size()
+		ret.Width, ret.Height = size.X, size.Y
+		if t.options.Width > 0 && t.options.Width < size.X {
+			ret.Width, ret.Height = t.options.Width, max(1, size.Y*t.options.Width/size.X)
+		}
+		frame, err := t.convert(img, format, data, timestamp, ret.Width, ret.Height)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+
+	results := make([]chan timelapseResult, len(times))
+	for i := range results {
+		results[i] = make(chan timelapseResult, 1)
+	}
+	// a slot is taken before fetching a thumbnail and given back once its frame is collected
+	parallelism := t.options.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultTimelapseParallelism
+	}
+	slots := make(chan struct{}, parallelism)
+	go func() {
+		for i, timestamp := range times {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func() {
+				img, format, data, err := t.fetch(ctx, timestamp)
+				var frame timelapseFrame
+				if err == nil {
+					frame, err = t.convert(img, format, data, timestamp, ret.Width, ret.Height)
+				}
+				results[i] <- timelapseResult{frame, err}
+			}()
+		}
+	}()
+	for i := range times {
+		var r timelapseResult
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		<-slots
+		if t.missing(r.err) {
+			ret.Skipped++
+			continue
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		frames = append(frames, r.frame)
+	}
+	return frames, nil
+}
+
+// Reports whether err is a thumbnail that SkipMissing leaves out.
+func (t *timelapse) missing(err error) bool {
+	return err != nil && t.options.SkipMissing && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrBadRequest))
+}
+
+// Fetches and decodes the thumbnail at timestamp, also returning its format ("jpeg", "png", or "gif") and data.
+func (t *timelapse) fetch(ctx context.Context, timestamp int) (image.Image, string, []byte, error) {
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx, FamilyDefault); err != nil {
+			return nil, "", nil, err
+		}
+	}
+	var d *Download
+	var err error
+	if t.options.UseLinks {
+		var link *GetThumbnailLinkResponse
+		link, err = t.camera.GetThumbnailLinkContext(ctx, t.camera_id, &GetThumbnailLinkOptions{Timestamp: &timestamp})
+		if err == nil && link.Url == "" {
+			err = fmt.Errorf("thumbnail link response has no url")
+		}
+		if err == nil {
+			d, err = t.camera.client.downloadLink(ctx, link.Url, imageContentTypes)
+		}
+	} else {
+		d, err = t.camera.GetThumbnailImageBytesContext(ctx, t.camera_id, &GetThumbnailImageOptions{Timestamp: strconv.Itoa(timestamp), Resolution: t.options.Resolution})
+	}
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("thumbnail at %d: %w", timestamp, err)
+	}
+	img, format, err := image.Decode(bytes.NewReader(d.Data))
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("thumbnail at %d: %w", timestamp, err)
+	}
+	return img, format, d.Data, nil
+}
+
+// Converts a thumbnail into a frame of the given size, with its timestamp burned in if requested.
+func (t *timelapse) convert(img image.Image, format string, data []byte, timestamp int, width int, height int) (timelapseFrame, error) {
+	changed := false
+	if size := img.Bounds().Size(); size.X != width || size.Y != height {
+		img, changed = scaleImage(img, width, height), true
+	}
+	if t.options.Timestamps {
+		rgba, ok := img.(*image.RGBA)
+		if !ok {
+			rgba = image.NewRGBA(image.Rect(0, 0, width, height))
+			draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+		}
+		drawTimestamp(rgba, time.Unix(int64(timestamp), 0).In(t.location).Format("2006-01-02 15:04:05 -07:00"))
+		img, changed = rgba, true
+	}
+	if t.format == TimelapseAVI {
+		if !changed && format == "jpeg" {
+			return timelapseFrame{jpeg: data}, nil
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+			return timelapseFrame{}, fmt.Errorf("thumbnail at %d: %w", timestamp, err)
+		}
+		return timelapseFrame{jpeg: buf.Bytes()}, nil
+	}
+	paletted := image.NewPaletted(image.Rect(0, 0, width, height), palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, img.Bounds().Min)
+	return timelapseFrame{paletted: paletted}, nil
+}
+
+// Scales src to width×height, averaging the source pixels covered by each pixel when shrinking.
+func scaleImage(src image.Image, width int, height int) *image.RGBA {
+	b := src.Bounds()
+	in := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(in, in.Bounds(), src, b.Min, draw.Src)
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := y * b.Dy() / height
+		y1 := max((y+1)*b.Dy()/height, y0+1)
+		for x := range width {
+			x0 := x * b.Dx() / width
+			x1 := max((x+1)*b.Dx()/width, x0+1)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					p := in.Pix[sy*in.Stride+sx*4:]
+					for i := range sum {
+						sum[i] += int(p[i])
+					}
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			o := out.Pix[y*out.Stride+x*4:]
+			for i := range sum {
+				o[i] = uint8(sum[i] / n)
+			}
+		}
+	}
+	return out
+}
+
+// 5×7 glyphs of the characters of a timestamp, one byte per row with the leftmost pixel in bit 4.
+var timestampGlyphs = map[rune][7]byte{
+	'0': {0x0e, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0e},
+	'1': {0x04, 0x0c, 0x04, 0x04, 0x04, 0x04, 0x0e},
+	'2': {0x0e, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1f},
+	'3': {0x1f, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0e},
+	'4': {0x02, 0x06, 0x0a, 0x12, 0x1f, 0x02, 0x02},
+	'5': {0x1f, 0x10, 0x1e, 0x01, 0x01, 0x11, 0x0e},
+	'6': {0x06, 0x08, 0x10, 0x1e, 0x11, 0x11, 0x0e},
+	'7': {0x1f, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8': {0x0e, 0x11, 0x11, 0x0e, 0x11, 0x11, 0x0e},
+	'9': {0x0e, 0x11, 0x11, 0x0f, 0x01, 0x02, 0x0c},
+	'-': {0x00, 0x00, 0x00, 0x1f, 0x00, 0x00, 0x00},
+	'+': {0x00, 0x04, 0x04, 0x1f, 0x04, 0x04, 0x00},
+	':': {0x00, 0x0c, 0x0c, 0x00, 0x0c, 0x0c, 0x00},
+	' ': {},
+}
+
+// Draws text in white on a translucent black box in the top-left corner of img, scaled with the image height.
+// Characters without a glyph are drawn as spaces.
+func drawTimestamp(img *image.RGBA, text string) {
+	scale := max(1, img.Bounds().Dy()/240)
+	pad := 2 * scale
+	box := image.Rect(0, 0, len([]rune(text))*6*scale-scale+2*pad, 7*scale+2*pad).Add(img.Bounds().Min)
+	draw.Draw(img, box, image.NewUniform(color.RGBA{A: 160}), image.Point{}, draw.Over)
+	for i, r := range []rune(text) {
+		glyph := timestampGlyphs[r]
+		for row, bits := range glyph {
+			for col := range 5 {
+				if bits&(0x10>>col) == 0 {
+					continue
+				}
+				x, y := pad+(i*6+col)*scale, pad+row*scale
+				dot := image.Rect(x, y, x+scale, y+scale).Add(img.Bounds().Min)
+				draw.Draw(img, dot, image.White, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+// Counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
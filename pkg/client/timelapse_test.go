@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"net/http"
+	"testing"
+)
+
+// Splits a Motion-JPEG AVI into the frame count of its main header and the frames of its movi list, checking the
+// RIFF sizes and that the index lists every frame.
+func parseAVI(data []byte) (int, [][]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "AVI " {
+		return 0, nil, fmt.Errorf("not an AVI file")
+	}
+	if size := binary.LittleEndian.Uint32(data[4:]); int(size) != len(data)-8 {
+		return 0, nil, fmt.Errorf("RIFF size is %d for %d bytes", size, len(data)-8)
+	}
+	total := -1
+	var frames [][]byte
+	indexed := -1
+	for pos := 12; pos < len(data); {
+		if pos+8 > len(data) {
+			return 0, nil, fmt.Errorf("truncated chunk at %d", pos)
+		}
+		id, size := string(data[pos:pos+4]), int(binary.LittleEndian.Uint32(data[pos+4:]))
+		body := data[pos+8 : min(len(data), pos+8+size)]
+		if len(body) != size {
+			return 0, nil, fmt.Errorf("chunk %s at %d is truncated", id, pos)
+		}
+		switch {
+		case id == "LIST" && string(body[:4]) == "hdrl":
+			// the avih chunk comes first, with the total number of frames as its fifth field
+			total = int(binary.LittleEndian.Uint32(body[4+8+16:]))
+		case id == "LIST" && string(body[:4]) == "movi":
+			for p := 4; p < len(body); {
+				n := int(binary.LittleEndian.Uint32(body[p+4:]))
+				if string(body[p:p+4]) != "00dc" {
+					return 0, nil, fmt.Errorf("unexpected chunk %q in movi", body[p:p+4])
+				}
+				frames = append(frames, body[p+8:p+8+n])
+				p += 8 + n + n%2
+			}
+		case id == "idx1":
+			indexed = size / 16
+		}
+		pos += 8 + size + size%2
+	}
+	if indexed != len(frames) {
+		return 0, nil, fmt.Errorf("index lists %d of %d frames", indexed, len(frames))
+	}
+	return total, frames, nil
+}
+
+func TestCreateTimelapse(t *testing.T) {
+	var thumbnail bytes.Buffer
+	jpeg.Encode(&thumbnail, image.NewGray(image.Rect(0, 0, 64, 48)), nil)
+	tests := []struct {
+		name   string
+		format string
+		// checks the output and returns its frame count and size
+		decode func(data []byte) (int, image.Rectangle, error)
+	}{
+		{"gif", TimelapseGIF, func(data []byte) (int, image.Rectangle, error) {
+			g, err := gif.DecodeAll(bytes.NewReader(data))
+			if err != nil {
+				return 0, image.Rectangle{}, err
+			}
+			for i, delay := range g.Delay {
+				if delay != 20 {
+					return 0, image.Rectangle{}, fmt.Errorf("frame %d has a delay of %d", i, delay)
+				}
+			}
+			return len(g.Image), image.Rect(0, 0, g.Config.Width, g.Config.Height), nil
+		}},
+		{"avi", TimelapseAVI, func(data []byte) (int, image.Rectangle, error) {
+			total, frames, err := parseAVI(data)
+			if err != nil {
+				return 0, image.Rectangle{}, err
+			}
+			if total != len(frames) {
+				return 0, image.Rectangle{}, fmt.Errorf("header reports %d frames, movi holds %d", total, len(frames))
+			}
+			var bounds image.Rectangle
+			for i, f := range frames {
+				img, err := jpeg.Decode(bytes.NewReader(f))
+				if err != nil {
+					return 0, image.Rectangle{}, fmt.Errorf("frame %d: %w", i, err)
+				}
+				bounds = img.Bounds()
+			}
+			return len(frames), bounds, nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				// the camera was offline at 1020
+				if r.URL.Query().Get("timestamp") == "1020" {
+					http.Error(w, `{"message": "no thumbnail"}`, http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "image/jpeg")
+				w.Write(thumbnail.Bytes())
+			}, nil)
+			var buf bytes.Buffer
+			// frames at 1000 to 1060 every 10s, but 1020
+			ret, err := c.Camera.CreateTimelapse("cam-1", &TimelapseOptions{Start_time: Int(1000), End_time: Int(1060), Interval: 10, Format: tt.format, FrameRate: 5, Width: 32, SkipMissing: true}, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ret.Format != tt.format || ret.Frames != 6 || ret.Skipped != 1 || ret.Width != 32 || ret.Height != 24 || ret.Bytes != int64(buf.Len()) {
+				t.Errorf("reported %+v for %d bytes", ret, buf.Len())
+			}
+			frames, bounds, err := tt.decode(buf.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if frames != 6 || bounds != image.Rect(0, 0, 32, 24) {
+				t.Errorf("decoded %d frames of %v, want 6 of 32x24", frames, bounds)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import "net/http"
+
+// A Middleware wraps the http.RoundTripper used for every request made by a Client, including token requests.
+// Middleware can inspect or modify requests and responses, e.g. for logging, header injection, or metrics.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// An adapter to allow the use of ordinary functions as an http.RoundTripper, similar to http.HandlerFunc.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Returns a Middleware that sets the given headers on every request, replacing any existing values.
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// RoundTrippers must not modify the caller's request
+			req = req.Clone(req.Context())
+			for k, v := range header {
+				req.Header[http.CanonicalHeaderKey(k)] = v
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// Returns a copy of httpClient (or a new http.Client if nil) whose transport is wrapped by middleware.
+// The first Middleware is the outermost, so it sees each request first and each response last.
+// The caller's http.Client is never modified.
+func buildHTTPClient(httpClient *http.Client, middleware []Middleware) *http.Client {
+	var hc http.Client
+	if httpClient != nil {
+		hc = *httpClient
+	}
+	transport := hc.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			transport = middleware[i](transport)
+		}
+	}
+	hc.Transport = transport
+	return &hc
+}
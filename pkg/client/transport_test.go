@@ -0,0 +1,67 @@
+package client
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	recorder := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				record(name + " " + req.URL.Path)
+				res, err := next.RoundTrip(req)
+				record(name + " done")
+				return res, err
+			})
+		}
+	}
+	transport := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		record("transport " + req.URL.Path)
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	httpClient := &http.Client{Transport: transport}
+	c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}, &ClientOptions{HTTPClient: httpClient, Middleware: []Middleware{recorder("outer"), nil, recorder("inner")}})
+
+	if err := c.MakeVerkadaRequest("GET", srv.URL+"/cameras/v1/alerts", nil, nil, &map[string]any{}, 0); err != nil {
+		t.Fatal(err)
+	}
+	// the token is fetched on the first request, through the same middleware and transport
+	want := []string{
+		"outer /token", "inner /token", "transport /token", "inner done", "outer done",
+		"outer /cameras/v1/alerts", "inner /cameras/v1/alerts", "transport /cameras/v1/alerts", "inner done", "outer done",
+	}
+	if !slices.Equal(calls, want) {
+		t.Errorf("got calls\n%q\nwant\n%q", calls, want)
+	}
+
+	// HTTPClient returns the wrapped client, without modifying the one given
+	calls = nil
+	res, err := c.HTTPClient().Get(srv.URL + "/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if want := []string{"outer /other", "inner /other", "transport /other", "inner done", "outer done"}; !slices.Equal(calls, want) {
+		t.Errorf("got calls %q, want %q", calls, want)
+	}
+	calls = nil
+	if res, err = httpClient.Get(srv.URL + "/other"); err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if want := []string{"transport /other"}; !slices.Equal(calls, want) {
+		t.Errorf("the given http.Client made calls %q, want %q", calls, want)
+	}
+}
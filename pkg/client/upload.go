@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Largest file uploaded when UploadOptions.MaxSize is zero.
+const DefaultMaxUploadSize = 32 << 20
+
+// Returned (wrapped) when an upload is larger than UploadOptions.MaxSize.
+var ErrUploadTooLarge = errors.New("upload exceeds maximum size")
+
+// Returned (wrapped) by the body of a request that cannot be sent again, such as an upload from a stream.
+var errBodyNotReplayable = errors.New("body is not an io.Seeker")
+
+// Options for file uploads, used by MakeVerkadaRequestWithReader and the FromReader methods.
+type UploadOptions struct {
+	// Size of the file in bytes, if known. When zero, it is found by seeking if the reader is an io.Seeker (e.g. *os.File
+	// or *bytes.Reader); otherwise the body is sent with chunked transfer encoding.
+	Size int64
+	// Largest file allowed, in bytes. Zero uses DefaultMaxUploadSize and a negative value allows any size.
+	MaxSize int64
+	// Optional callback reporting the bytes of the file sent so far and its total size (-1 if unknown).
+	// It restarts from zero if the upload is retried.
+	OnProgress func(sent int64, total int64)
+}
+
+// A file uploaded as multipart/form-data by MakeVerkadaRequestWithReader.
+type Upload struct {
+	// Name of the file in the form, e.g. "plates.csv".
+	Filename string
+	// MIME type of the file, e.g. "text/csv" or "image/jpeg".
+	ContentType string
+	// Contents of the file, streamed rather than buffered. Failed uploads are only retried if it is an io.Seeker.
+	Body io.Reader
+	UploadOptions
+}
+
+// Used by methods that upload in-memory data or other streams instead of a file on disk.
+// The body is streamed as multipart/form-data with a random boundary, so its size is not limited by memory.
+// Handles auth token refresh automatically based on the Client's API key.
+// Failed requests are retried according to the Client's RetryPolicy, with retry as the number of attempts already made,
+// provided upload.Body is an io.Seeker. Otherwise a request rejected with a 401 response still refreshes the auth token,
+// but returns the *APIError of the 401 instead of being sent again.
+//
+// Exported so custom requests can be made and can also be used in case new endpoints are not reflected in the package.
+func (c *Client) MakeVerkadaRequestWithReader(method string, url string, params any, upload Upload, target any, retry int) error {
+	return c.MakeVerkadaRequestWithReaderContext(context.Background(), method, url, params, upload, target, retry)
+}
+
+// Same as MakeVerkadaRequestWithReader, with ctx controlling cancellation and deadlines of the request, any retry backoff, and any auth token refresh.
+func (c *Client) MakeVerkadaRequestWithReaderContext(ctx context.Context, method string, url string, params any, upload Upload, target any, retry int) error {
+	if upload.Body == nil {
+		return validationErrorf("upload %s has no body", upload.Filename)
+	}
+	body, err := newMultipartBody(upload)
+	if err != nil {
+		return err
+	}
+	if body.seeker == nil {
+		// a stream can only be read once, so no further attempts can be made
+		policy := c.retryPolicy
+		if policy == nil {
+			policy = DefaultRetryPolicy()
+		}
+		retry = max(retry, policy.MaxAttempts-1)
+	}
+	header := http.Header{}
+	header.Add("accept", "application/json")
+	header.Add("content-type", body.contentType)
+	res, err := c.send(ctx, method, url, params, header, body.open, retry)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+	return c.decodeResponse(method, url, res, target)
+}
+
+// The multipart/form-data body of an Upload, which can be reopened for each attempt if the file is an io.Seeker.
+type multipartBody struct {
+	upload      Upload
+	contentType string
+	// multipart headers before the file, and closing boundary after it
+	prefix []byte
+	suffix []byte
+	// size of the file, or -1 if unknown
+	size   int64
+	seeker io.Seeker
+	// offset of the file in seeker at which to restart each attempt
+	start  int64
+	opened bool
+}
+
+func newMultipartBody(upload Upload) (*multipartBody, error) {
+	b := &multipartBody{upload: upload, size: -1}
+	if upload.Size > 0 {
+		b.size = upload.Size
+	}
+	if s, ok := upload.Body.(io.Seeker); ok {
+		start, err := s.Seek(0, io.SeekCurrent)
+		if err == nil {
+			b.seeker, b.start = s, start
+			if b.size < 0 {
+				end, err := s.Seek(0, io.SeekEnd)
+				if err != nil {
+					return nil, err
+				}
+				if _, err := s.Seek(start, io.SeekStart); err != nil {
+					return nil, err
+				}
+				b.size = end - start
+			}
+		}
+	}
+	if limit := b.maxSize(); limit >= 0 && b.size > limit {
+		return nil, fmt.Errorf("%w: %s is %d bytes, limit is %d", ErrUploadTooLarge, upload.Filename, b.size, limit)
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part := textproto.MIMEHeader{}
+	part.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(upload.Filename)))
+	if upload.ContentType != "" {
+		part.Set("Content-Type", upload.ContentType)
+	}
+	if _, err := mw.CreatePart(part); err != nil {
+		return nil, err
+	}
+	b.prefix = bytes.Clone(buf.Bytes())
+	buf.Reset()
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	b.suffix = bytes.Clone(buf.Bytes())
+	b.contentType = mw.FormDataContentType()
+	return b, nil
+}
+
+// Returns the largest file size allowed, or -1 for no limit.
+func (b *multipartBody) maxSize() int64 {
+	switch {
+	case b.upload.MaxSize == 0:
+		return DefaultMaxUploadSize
+	case b.upload.MaxSize < 0:
+		return -1
+	}
+	return b.upload.MaxSize
+}
+
+// Returns a reader for one attempt at sending the body and its length (-1 if unknown). Passed to Client.send.
+func (b *multipartBody) open() (io.Reader, int64, error) {
+	if b.opened {
+		if b.seeker == nil {
+			return nil, 0, fmt.Errorf("upload of %s cannot be retried: %w", b.upload.Filename, errBodyNotReplayable)
+		}
+		if _, err := b.seeker.Seek(b.start, io.SeekStart); err != nil {
+			return nil, 0, err
+		}
+	}
+	b.opened = true
+	file := &uploadReader{r: b.upload.Body, limit: b.maxSize(), total: b.size, onProgress: b.upload.OnProgress, name: b.upload.Filename}
+	length := int64(-1)
+	if b.size >= 0 {
+		length = int64(len(b.prefix)) + b.size + int64(len(b.suffix))
+	}
+	return io.MultiReader(bytes.NewReader(b.prefix), file, bytes.NewReader(b.suffix)), length, nil
+}
+
+// Reads an uploaded file, enforcing the size limit and reporting progress.
+type uploadReader struct {
+	r          io.Reader
+	name       string
+	sent       int64
+	limit      int64
+	total      int64
+	onProgress func(sent int64, total int64)
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	u.sent += int64(n)
+	if u.limit >= 0 && u.sent > u.limit {
+		return n, fmt.Errorf("%w: %s is over %d bytes", ErrUploadTooLarge, u.name, u.limit)
+	}
+	if n > 0 && u.onProgress != nil {
+		u.onProgress(u.sent, u.total)
+	}
+	return n, err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// Opens a file on disk as an Upload.
+func openUpload(filename string, filetype string) (Upload, *os.File, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return Upload{}, nil, err
+	}
+	return Upload{Filename: filepath.Base(filename), ContentType: filetype, Body: file}, file, nil
+}
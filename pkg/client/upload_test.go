@@ -0,0 +1,177 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// A reader that is not an io.Seeker, as for uploads from a pipe or network stream.
+type streamReader struct{ io.Reader }
+
+// A request received by an upload test server.
+type receivedUpload struct {
+	boundary      string
+	contentLength int64
+	filename      string
+	contentType   string
+	data          string
+}
+
+// Returns a handler recording the multipart uploads it receives.
+func uploadRecorder(t *testing.T, received *[]receivedUpload) http.HandlerFunc {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			t.Errorf("bad content type %q: %v", r.Header.Get("Content-Type"), err)
+		}
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("not a multipart body: %v", err)
+			return
+		}
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Errorf("no part: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(part)
+		mu.Lock()
+		*received = append(*received, receivedUpload{params["boundary"], r.ContentLength, part.FileName(), part.Header.Get("Content-Type"), string(data)})
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}
+}
+
+func TestUploadStreams(t *testing.T) {
+	content := strings.Repeat("plate,name\nABC123,Lobby\n", 1000)
+	tests := []struct {
+		name       string
+		body       func() io.Reader
+		wantLength bool
+	}{
+		{"seeker", func() io.Reader { return strings.NewReader(content) }, true},
+		{"stream", func() io.Reader { return streamReader{strings.NewReader(content)} }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []receivedUpload
+			c, srv := newTestClient(t, uploadRecorder(t, &received), nil)
+			var progress []int64
+			for range 2 {
+				upload := Upload{Filename: `plates "1".csv`, ContentType: "text/csv", Body: tt.body(), UploadOptions: UploadOptions{OnProgress: func(sent int64, total int64) {
+					progress = append(progress, sent, total)
+				}}}
+				if err := c.MakeVerkadaRequestWithReader("POST", srv.URL+"/cameras/v1/analytics/lpr/license_plate_of_interest/batch", nil, upload, &map[string]any{}, 0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if len(received) != 2 {
+				t.Fatalf("received %d uploads", len(received))
+			}
+			if received[0].boundary == received[1].boundary || received[0].boundary == "" {
+				t.Errorf("boundaries %q and %q are not random", received[0].boundary, received[1].boundary)
+			}
+			for _, r := range received {
+				if r.data != content || r.filename != `plates "1".csv` || r.contentType != "text/csv" {
+					t.Errorf("received %s (%s) with %d bytes", r.filename, r.contentType, len(r.data))
+				}
+				if got := r.contentLength > 0; got != tt.wantLength {
+					t.Errorf("content length %d, want known: %v", r.contentLength, tt.wantLength)
+				}
+			}
+			if len(progress) < 2 || progress[len(progress)-2] != int64(len(content)) {
+				t.Errorf("progress ended at %v", progress[max(len(progress)-2, 0):])
+			}
+			if total := progress[len(progress)-1]; tt.wantLength && total != int64(len(content)) || !tt.wantLength && total != -1 {
+				t.Errorf("progress total %d", total)
+			}
+		})
+	}
+}
+
+func TestUploadSizeLimit(t *testing.T) {
+	content := strings.Repeat("x", 1000)
+	tests := []struct {
+		name    string
+		body    io.Reader
+		size    int64
+		maxSize int64
+		wantErr error
+		wantReq bool
+	}{
+		{"seeker under the limit", strings.NewReader(content), 0, 1000, nil, true},
+		{"seeker over the limit", strings.NewReader(content), 0, 999, ErrUploadTooLarge, false},
+		{"declared size over the limit", streamReader{strings.NewReader(content)}, 1000, 10, ErrUploadTooLarge, false},
+		{"stream over the limit", streamReader{strings.NewReader(content)}, 0, 999, ErrUploadTooLarge, true},
+		{"stream under the limit", streamReader{strings.NewReader(content)}, 0, 1000, nil, true},
+		{"no limit", streamReader{strings.NewReader(content)}, 0, -1, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				io.Copy(io.Discard, r.Body)
+				w.Write([]byte(`{}`))
+			}, nil)
+			upload := Upload{Filename: "big.csv", Body: tt.body, UploadOptions: UploadOptions{Size: tt.size, MaxSize: tt.maxSize}}
+			err := c.MakeVerkadaRequestWithReader("POST", srv.URL+"/upload", nil, upload, &map[string]any{}, 0)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+			if !tt.wantReq && requests.Load() > 0 {
+				t.Errorf("request was sent")
+			}
+		})
+	}
+}
+
+func TestUploadReauth(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    func() io.Reader
+		wantErr error
+		// requests to the upload endpoint
+		wantRequests int32
+	}{
+		{"seeker is sent again", func() io.Reader { return bytes.NewReader([]byte("a,b\n")) }, nil, 2},
+		{"stream returns the 401", func() io.Reader { return streamReader{bytes.NewReader([]byte("a,b\n"))} }, ErrUnauthorized, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			c, srv := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				if requests.Add(1) == 1 {
+					http.Error(w, `{"message": "token expired"}`, http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{}`))
+			}, nil)
+			upload := Upload{Filename: "plates.csv", Body: tt.body()}
+			err := c.MakeVerkadaRequestWithReader("POST", srv.URL+"/upload", nil, upload, &map[string]any{}, 0)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+			var apiErr *APIError
+			if tt.wantErr != nil && (!errors.As(err, &apiErr) || apiErr.Message != "token expired") {
+				t.Errorf("got %v, want the *APIError of the 401", err)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("sent %d requests, want %d", got, tt.wantRequests)
+			}
+			// the rejected token is replaced either way
+			if token, _ := c.Tokens.Token(t.Context()); token != "token-2" {
+				t.Errorf("token is %s", token)
+			}
+		})
+	}
+}
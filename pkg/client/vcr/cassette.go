@@ -0,0 +1,109 @@
+package vcr
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// Version of the cassette file format written by Recorder.
+const CassetteVersion = 1
+
+// A Cassette is the set of interactions recorded in one file, stored as indented JSON.
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// An Interaction is one recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// A recorded request. Requests are matched on Method, Path, and Query only.
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Normalized query: parameters sorted by name, as produced by Key.
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitzero"`
+}
+
+// A recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitzero"`
+}
+
+// A request or response body, kept as text when it is valid UTF-8 and as base64 otherwise (e.g. thumbnails).
+type Body struct {
+	Text   string `json:"text,omitempty"`
+	Base64 string `json:"base64,omitempty"`
+}
+
+func newBody(b []byte) Body {
+	if utf8.Valid(b) {
+		return Body{Text: string(b)}
+	}
+	return Body{Base64: base64.StdEncoding.EncodeToString(b)}
+}
+
+// Returns the decoded body.
+func (b Body) Bytes() ([]byte, error) {
+	if b.Base64 != "" {
+		return base64.StdEncoding.DecodeString(b.Base64)
+	}
+	return []byte(b.Text), nil
+}
+
+// Returns the key on which requests are matched: the method, path, and query with its parameters sorted.
+// This normalizes the query built by the client's options structs, whose parameter order follows struct fields.
+func Key(method string, path string, query url.Values) string {
+	key := method + " " + path
+	if q := query.Encode(); q != "" {
+		key += "?" + q
+	}
+	return key
+}
+
+// Returns the key of a recorded request.
+func (r Request) Key() string {
+	query, _ := url.ParseQuery(r.Query)
+	return Key(r.Method, r.Path, query)
+}
+
+// Loads a cassette from a file.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("vcr: cannot parse cassette %s: %w", path, err)
+	}
+	if c.Version != CassetteVersion {
+		return nil, fmt.Errorf("vcr: cassette %s has version %d, expected %d", path, c.Version, CassetteVersion)
+	}
+	return &c, nil
+}
+
+// Writes the cassette to a file, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
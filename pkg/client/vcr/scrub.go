@@ -0,0 +1,159 @@
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Value that replaces scrubbed secrets and personal data.
+const Redacted = "REDACTED"
+
+// A Scrubber removes secrets and personal data from interactions before they are written to a cassette.
+// Names are matched case-insensitively.
+type Scrubber struct {
+	// Headers whose values are replaced, in requests and responses.
+	Headers []string
+	// Query parameters whose values are replaced, in request URLs and in URLs within text bodies such as HLS playlists.
+	// Matching ignores their values, so replays still match.
+	QueryParams []string
+	// JSON object fields, at any depth, whose values are replaced in request and response bodies.
+	BodyFields []string
+	// Optional hook for anything else, called after the fields above have been scrubbed.
+	Func func(*Interaction)
+}
+
+// Returns a Scrubber for the API key, auth tokens, streaming JWTs, and common personal data fields of Verkada responses.
+// Append to its fields to scrub more.
+func DefaultScrubber() *Scrubber {
+	return &Scrubber{
+		Headers:     []string{"x-api-key", "x-verkada-auth", "authorization", "cookie", "set-cookie"},
+		QueryParams: []string{"jwt", "token", "api_key"},
+		BodyFields: []string{
+			"token", "jwt", "api_key",
+			"email", "email_address", "phone", "phone_number", "mobile_phone",
+			"first_name", "middle_name", "last_name", "full_name", "name_on_card",
+			"address", "date_of_birth", "profile_photo_url", "photo_url",
+		},
+	}
+}
+
+// Scrubs an interaction in place.
+func (s *Scrubber) Scrub(in *Interaction) {
+	if s == nil {
+		return
+	}
+	s.scrubHeader(in.Request.Header)
+	s.scrubHeader(in.Response.Header)
+	in.Request.Query = s.scrubQuery(in.Request.Query)
+	in.Request.Body = s.scrubBody(in.Request.Body)
+	in.Response.Body = s.scrubBody(in.Response.Body)
+	if s.Func != nil {
+		s.Func(in)
+	}
+}
+
+func (s *Scrubber) scrubHeader(h http.Header) {
+	for name := range h {
+		if contains(s.Headers, name) {
+			h[name] = []string{Redacted}
+		}
+	}
+}
+
+// Replaces scrubbed parameter values, returning the normalized query.
+func (s *Scrubber) scrubQuery(raw string) string {
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	for name := range query {
+		if contains(s.QueryParams, name) {
+			query.Set(name, Redacted)
+		}
+	}
+	return query.Encode()
+}
+
+// Replaces scrubbed fields of a JSON body and scrubbed query parameters of URLs within any text body.
+func (s *Scrubber) scrubBody(b Body) Body {
+	if b.Text == "" {
+		return b
+	}
+	b = s.scrubJSON(b)
+	b.Text = s.scrubURLs(b.Text)
+	return b
+}
+
+// Replaces scrubbed fields of a JSON body. Bodies that are not JSON are left unchanged.
+func (s *Scrubber) scrubJSON(b Body) Body {
+	if len(s.BodyFields) == 0 {
+		return b
+	}
+	dec := json.NewDecoder(strings.NewReader(b.Text))
+	dec.UseNumber()
+	var v any
+	if dec.Decode(&v) != nil {
+		return b
+	}
+	if !s.scrubValue(v) {
+		return b
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if enc.Encode(v) != nil {
+		return b
+	}
+	return Body{Text: strings.TrimSuffix(buf.String(), "\n")}
+}
+
+// Replaces the values of scrubbed query parameters in URLs within text, such as the segment URIs of a playlist
+// (segment_0.ts?jwt=...) or a link in a JSON string.
+func (s *Scrubber) scrubURLs(text string) string {
+	if len(s.QueryParams) == 0 {
+		return text
+	}
+	names := make([]string, len(s.QueryParams))
+	for i, name := range s.QueryParams {
+		names[i] = regexp.QuoteMeta(name)
+	}
+	// & is also matched as \u0026, as it appears in JSON strings encoded with HTML escaping
+	param := regexp.MustCompile(`(?i)((?:[?&]|\\u0026)(?:` + strings.Join(names, "|") + `)=)[^&\\\s"'<>#]*`)
+	return param.ReplaceAllString(text, "${1}"+Redacted)
+}
+
+// Scrubs a decoded JSON value in place, reporting whether anything changed.
+func (s *Scrubber) scrubValue(v any) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if contains(s.BodyFields, key) {
+				if child != nil {
+					v[key] = Redacted
+					changed = true
+				}
+				continue
+			}
+			changed = s.scrubValue(child) || changed
+		}
+	case []any:
+		for _, child := range v {
+			changed = s.scrubValue(child) || changed
+		}
+	}
+	return changed
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+// Package vcr records interactions with the Verkada API to a cassette file and replays them, so that tests of code built on
+// the client package are deterministic and run without network access or an API key.
+//
+// A Recorder is installed as client Middleware. In ModeRecord it passes requests through to the API and saves each
+// interaction, with secrets and personal data scrubbed, when closed. In ModeReplay it answers requests from the cassette
+// and fails any request that was not recorded.
+//
+//	rec, err := vcr.New("testdata/cameras.json", vcr.ModeReplay, nil)
+//	defer rec.Close()
+//	c, err := client.New(&client.ClientOptions{Region: "prod1", APIKey: "unused", Middleware: []client.Middleware{rec.Middleware()}})
+package vcr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"sync"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client"
+)
+
+// A Mode selects whether a Recorder records or replays.
+type Mode int
+
+const (
+	// Answer requests from the cassette, failing requests that were not recorded. The cassette must exist.
+	ModeReplay Mode = iota
+	// Send requests to the API and record them, replacing any existing cassette when the Recorder is closed.
+	ModeRecord
+	// Replay if the cassette exists, and record it otherwise.
+	ModeAuto
+)
+
+// Returned (wrapped) by replays of requests that have no unused recorded interaction.
+var ErrUnmatched = errors.New("vcr: no recorded interaction matches request")
+
+// Options for a Recorder.
+type Options struct {
+	// Scrubber applied to interactions before they are saved; DefaultScrubber() is used if nil.
+	Scrubber *Scrubber
+}
+
+// A Recorder records or replays the requests passing through its Middleware. It is safe for concurrent use.
+type Recorder struct {
+	path     string
+	mode     Mode
+	scrubber *Scrubber
+
+	mu       sync.Mutex
+	cassette *Cassette
+	// interactions already replayed, by index
+	used map[int]bool
+}
+
+// Returns a Recorder for the cassette at path.
+// In ModeReplay (or ModeAuto, if the file exists) the cassette is loaded immediately.
+func New(path string, mode Mode, options *Options) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, used: map[int]bool{}, cassette: &Cassette{Version: CassetteVersion}}
+	if options != nil {
+		r.scrubber = options.Scrubber
+	}
+	if r.scrubber == nil {
+		r.scrubber = DefaultScrubber()
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	c, err := LoadCassette(path)
+	if mode == ModeAuto && errors.Is(err, fs.ErrNotExist) {
+		r.mode = ModeRecord
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.mode, r.cassette = ModeReplay, c
+	return r, nil
+}
+
+// Returns the mode the Recorder is operating in, which is never ModeAuto.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Returns Middleware that records or replays requests, for use in client.ClientOptions.Middleware.
+// It should usually be the last Middleware so that it sees requests as they would be sent.
+func (r *Recorder) Middleware() client.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return client.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if r.mode == ModeReplay {
+				return r.replay(req)
+			}
+			return r.record(next, req)
+		})
+	}
+}
+
+// Saves the cassette when recording. Does nothing when replaying.
+func (r *Recorder) Close() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// Returns the number of recorded interactions that have not been replayed, useful for checking that a test made every
+// expected request.
+func (r *Recorder) Unused() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode != ModeReplay {
+		return 0
+	}
+	return len(r.cassette.Interactions) - len(r.used)
+}
+
+func (r *Recorder) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	in := &Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.Query().Encode(),
+			Header: req.Header.Clone(),
+			Body:   newBody(reqBody),
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       newBody(resBody),
+		},
+	}
+	// the length changes if the body is scrubbed, and is restored from the body on replay
+	in.Response.Header.Del("Content-Length")
+	r.scrubber.Scrub(in)
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+	return res, nil
+}
+
+// Answers req with the first unused interaction recorded for the same key, so that repeated requests (such as a retry
+// after a 429) replay in the order they were recorded.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	key := Key(req.Method, req.URL.Path, nil)
+	if q := r.scrubber.scrubQuery(req.URL.RawQuery); q != "" {
+		key += "?" + q
+	}
+	r.mu.Lock()
+	var in *Interaction
+	for i, candidate := range r.cassette.Interactions {
+		if !r.used[i] && candidate.Request.Key() == key {
+			r.used[i] = true
+			in = candidate
+			break
+		}
+	}
+	r.mu.Unlock()
+	if in == nil {
+		return nil, fmt.Errorf("%w: %s in %s", ErrUnmatched, key, r.path)
+	}
+	body, err := in.Response.Body.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	res := &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	if res.Header == nil {
+		res.Header = http.Header{}
+	}
+	return res, nil
+}
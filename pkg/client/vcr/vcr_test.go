@@ -0,0 +1,263 @@
+package vcr
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client"
+)
+
+const (
+	testKey      = "secret-api-key"
+	testToken    = "secret-token"
+	testJWT      = "secret-jwt"
+	testEmail    = "ada@example.com"
+	testPlaylist = "#EXTM3U\n#EXTINF:2.0,\nsegment_0.ts?jwt=" + testJWT + "&camera_id=cam-1\n#EXTINF:2.0,\nhttps://cdn.example.com/segment_1.ts?camera_id=cam-1&jwt=" + testJWT + "\n#EXT-X-ENDLIST\n"
+)
+
+// Starts a server standing in for the API: a token endpoint, a users endpoint with personal data, and a playlist.
+func apiServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if r.Header.Get("x-api-key") != testKey {
+				http.Error(w, `{"message": "bad key"}`, http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"token": testToken})
+		case "/core/v1/user":
+			if r.Header.Get("x-verkada-auth") != testToken {
+				http.Error(w, `{"message": "bad token"}`, http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"user_id": r.URL.Query().Get("user_id"), "email": testEmail, "first_name": "Ada"})
+		case "/stream/cameras/v1/footage/stream/stream.m3u8":
+			if r.URL.Query().Get("jwt") != testJWT {
+				http.Error(w, "bad jwt", http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+			io.WriteString(w, testPlaylist)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Makes the requests of the round trip test: a user through a Client, and a playlist with a plain GET.
+func exercise(t *testing.T, rec *Recorder, baseURL string, key string) (*client.GetUserResponse, string) {
+	t.Helper()
+	c, err := client.New(&client.ClientOptions{BaseURL: baseURL, APIKey: key, Middleware: []client.Middleware{rec.Middleware()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := c.Core.GetUser(&client.GetUserOptions{User_id: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient := &http.Client{Transport: rec.Middleware()(http.DefaultTransport)}
+	res, err := httpClient.Get(baseURL + "/stream/cameras/v1/footage/stream/stream.m3u8?camera_id=cam-1&jwt=" + testJWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	playlist, _ := io.ReadAll(res.Body)
+	return user, string(playlist)
+}
+
+func TestRecordReplay(t *testing.T) {
+	srv := apiServer(t)
+	path := filepath.Join(t.TempDir(), "cassettes", "users.json")
+
+	rec, err := New(path, ModeAuto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("ModeAuto without a cassette is mode %d", rec.Mode())
+	}
+	recorded, recordedPlaylist := exercise(t, rec, srv.URL, testKey)
+	if recorded.Email != testEmail || recordedPlaylist != testPlaylist {
+		t.Fatalf("recording changed the responses: %+v, %q", recorded, recordedPlaylist)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// replayed without the server or the API key, and with the secrets scrubbed
+	srv.Close()
+	rec, err = New(path, ModeAuto, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeReplay {
+		t.Fatalf("ModeAuto with a cassette is mode %d", rec.Mode())
+	}
+	replayed, replayedPlaylist := exercise(t, rec, srv.URL, "another-key")
+	if replayed.User_id != "user-1" || replayed.First_name != Redacted || replayed.Email != Redacted {
+		t.Errorf("replayed %+v", replayed)
+	}
+	wantPlaylist := strings.ReplaceAll(testPlaylist, testJWT, Redacted)
+	if replayedPlaylist != wantPlaylist {
+		t.Errorf("replayed playlist\n%s\nwant\n%s", replayedPlaylist, wantPlaylist)
+	}
+	if n := rec.Unused(); n != 0 {
+		t.Errorf("%d interactions were not replayed", n)
+	}
+}
+
+func TestCassetteScrubbed(t *testing.T) {
+	srv := apiServer(t)
+	path := filepath.Join(t.TempDir(), "users.json")
+	rec, err := New(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exercise(t, rec, srv.URL, testKey)
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{testKey, testToken, testJWT, testEmail, "Ada"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, b)
+		}
+	}
+	c, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Interactions) != 3 {
+		t.Fatalf("recorded %d interactions, want the token, user, and playlist requests", len(c.Interactions))
+	}
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	cassette := &Cassette{Version: CassetteVersion, Interactions: []*Interaction{{
+		Request:  Request{Method: "GET", Path: "/core/v1/user", Query: "user_id=user-1"},
+		Response: Response{StatusCode: 200, Body: Body{Text: `{"user_id": "user-1"}`}},
+	}}}
+	if err := cassette.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		requests []string
+		// error of the last request
+		wantErr error
+	}{
+		{"recorded", []string{"GET /core/v1/user?user_id=user-1"}, nil},
+		{"query in another order", []string{"GET /core/v1/user?user_id=user-1&"}, nil},
+		{"other path", []string{"GET /core/v1/users?user_id=user-1"}, ErrUnmatched},
+		{"other query", []string{"GET /core/v1/user?user_id=user-2"}, ErrUnmatched},
+		{"other method", []string{"DELETE /core/v1/user?user_id=user-1"}, ErrUnmatched},
+		{"repeated more often than recorded", []string{"GET /core/v1/user?user_id=user-1", "GET /core/v1/user?user_id=user-1"}, ErrUnmatched},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := New(path, ModeReplay, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			httpClient := &http.Client{Transport: rec.Middleware()(http.DefaultTransport)}
+			for i, r := range tt.requests {
+				method, target, _ := strings.Cut(r, " ")
+				req, _ := http.NewRequest(method, "http://127.0.0.1:1"+target, nil)
+				res, err := httpClient.Do(req)
+				if i < len(tt.requests)-1 {
+					if err != nil {
+						t.Fatal(err)
+					}
+					res.Body.Close()
+					continue
+				}
+				if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				if err == nil {
+					body, _ := io.ReadAll(res.Body)
+					res.Body.Close()
+					if res.StatusCode != 200 || string(body) != `{"user_id": "user-1"}` {
+						t.Errorf("replayed %d %s", res.StatusCode, body)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("got %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestScrubber(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Interaction
+		want Interaction
+	}{
+		{
+			"headers and query",
+			Interaction{Request: Request{Query: "camera_id=cam-1&jwt=abc", Header: http.Header{"X-Api-Key": {"key"}, "Accept": {"application/json"}}}},
+			Interaction{Request: Request{Query: "camera_id=cam-1&jwt=REDACTED", Header: http.Header{"X-Api-Key": {"REDACTED"}, "Accept": {"application/json"}}}},
+		},
+		{
+			"nested JSON fields",
+			Interaction{Response: Response{Body: Body{Text: `{"users":[{"user_id":"u1","email":"a@example.com","phone":null}],"token":"t"}`}}},
+			Interaction{Response: Response{Body: Body{Text: `{"token":"REDACTED","users":[{"email":"REDACTED","phone":null,"user_id":"u1"}]}`}}},
+		},
+		{
+			"JSON without scrubbed fields is kept as is",
+			Interaction{Response: Response{Body: Body{Text: `{"b": 1, "a": 2}`}}},
+			Interaction{Response: Response{Body: Body{Text: `{"b": 1, "a": 2}`}}},
+		},
+		{
+			"playlist",
+			Interaction{Response: Response{Body: Body{Text: "#EXTM3U\nsegment_0.ts?jwt=abc.def-ghi\nsegment_1.ts?camera_id=c&JWT=abc#frag\n"}}},
+			Interaction{Response: Response{Body: Body{Text: "#EXTM3U\nsegment_0.ts?jwt=REDACTED\nsegment_1.ts?camera_id=c&JWT=REDACTED#frag\n"}}},
+		},
+		{
+			"link in a JSON string",
+			Interaction{Response: Response{Body: Body{Text: `{"url": "https://api.verkada.com/stream.m3u8?camera_id=c\u0026jwt=abc"}`}}},
+			Interaction{Response: Response{Body: Body{Text: `{"url": "https://api.verkada.com/stream.m3u8?camera_id=c\u0026jwt=REDACTED"}`}}},
+		},
+		{
+			"parameter names are not matched inside other names",
+			Interaction{Response: Response{Body: Body{Text: "segment_0.ts?page_token=abc&xjwt=def"}}},
+			Interaction{Response: Response{Body: Body{Text: "segment_0.ts?page_token=abc&xjwt=def"}}},
+		},
+		{
+			"binary bodies are left alone",
+			Interaction{Response: Response{Body: Body{Base64: "AAEC"}}},
+			Interaction{Response: Response{Body: Body{Base64: "AAEC"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.in
+			DefaultScrubber().Scrub(&in)
+			got, _ := json.Marshal(in)
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) {
+				t.Errorf("got  %s\nwant %s", got, want)
+			}
+		})
+	}
+}
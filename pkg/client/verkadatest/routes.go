@@ -0,0 +1,484 @@
+package verkadatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client"
+)
+
+// How a list endpoint paginates.
+type paging struct {
+	// query parameters for the page token and page size
+	tokenParam string
+	sizeParam  string
+	// response field holding the next page token
+	nextField string
+	kind      pageKind
+}
+
+type pageKind int
+
+const (
+	// no pagination, every item is returned
+	unpaged pageKind = iota
+	stringPages
+	// integer tokens where 0 means no further pages
+	intPages
+	// the token is in the next_page_token field of the last item, as for guest visits
+	itemPages
+)
+
+var (
+	pageTokenPaging = paging{tokenParam: "page_token", sizeParam: "page_size", nextField: "next_page_token", kind: stringPages}
+	nextTokenPaging = paging{tokenParam: "page_token", sizeParam: "page_size", nextField: "next_token", kind: stringPages}
+	intTokenPaging  = paging{tokenParam: "page_token", sizeParam: "page_size", nextField: "next_page_token", kind: intPages}
+	visitPaging     = paging{tokenParam: "page_token", sizeParam: "page_size", nextField: "next_page_token", kind: itemPages}
+	cursorPaging    = paging{tokenParam: "cursor", sizeParam: "limit", nextField: "cursor", kind: stringPages}
+	noPaging        = paging{}
+)
+
+// Registers every endpoint called by the client package.
+func (s *Server) routes(mux *http.ServeMux) {
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.HandleFunc(pattern, s.wrap(false, h))
+	}
+	stream := func(pattern string, h http.HandlerFunc) {
+		mux.HandleFunc(pattern, s.wrap(true, s.requireStreamingJWT(h)))
+	}
+	mux.HandleFunc("POST /token", s.wrap(true, s.handleToken))
+	mux.HandleFunc("GET /cameras/v1/footage/token", s.wrap(true, s.handleStreamingToken))
+	// target of thumbnail links, which need no authentication like the signed URLs returned by the real API
+	mux.HandleFunc("GET /verkadatest/thumbnails", s.wrap(true, s.file("image/jpeg", thumbnail)))
+
+	// Camera
+	handle("GET /cameras/v1/devices", s.list(Cameras, client.GetCameraDevicesResponse{}, "cameras", pageTokenPaging))
+	handle("GET /cameras/v1/alerts", s.list(CameraAlerts, client.GetAlertsResponse{}, "notifications", pageTokenPaging))
+	handle("GET /cameras/v1/analytics/object_counts", s.list(ObjectCounts, client.GetObjectCountsResponse{}, "object_counts", pageTokenPaging))
+	handle("GET /cameras/v1/analytics/lpr/images", s.list(SeenPlates, client.GetSeenPlatesResponse{}, "detections", intTokenPaging, "license_plate"))
+	handle("GET /cameras/v1/analytics/lpr/timestamps", s.list(PlateTimestamps, client.GetLicensePlateTSResponse{}, "detections", intTokenPaging))
+	handle("GET /cameras/v1/analytics/lpr/license_plate_of_interest", s.list(LicensePlatesOfInterest, client.GetAllLPOIResponse{}, "license_plate_of_interest", pageTokenPaging))
+	handle("POST /cameras/v1/analytics/lpr/license_plate_of_interest", s.create(LicensePlatesOfInterest, client.CreateLPOIResponse{}))
+	handle("PATCH /cameras/v1/analytics/lpr/license_plate_of_interest", s.update(LicensePlatesOfInterest, client.UpdateLPOIResponse{}, "license_plate"))
+	handle("DELETE /cameras/v1/analytics/lpr/license_plate_of_interest", s.remove(LicensePlatesOfInterest, client.DeleteLPOIResponse{}, "license_plate"))
+	handle("POST /cameras/v1/analytics/lpr/license_plate_of_interest/batch", s.static())
+	handle("DELETE /cameras/v1/analytics/lpr/license_plate_of_interest/batch", s.static())
+	handle("GET /cameras/v1/people/person_of_interest", s.list(PersonsOfInterest, client.GetAllPOIResponse{}, "persons_of_interest", nextTokenPaging))
+	handle("POST /cameras/v1/people/person_of_interest", s.create(PersonsOfInterest, client.POIProfile{}))
+	handle("PATCH /cameras/v1/people/person_of_interest", s.update(PersonsOfInterest, client.POIProfile{}, "person_id"))
+	handle("DELETE /cameras/v1/people/person_of_interest", s.remove(PersonsOfInterest, client.POIProfile{}, "person_id"))
+	handle("GET /cameras/v1/analytics/dashboard_occupancy_trends", s.static())
+	handle("GET /cameras/v1/analytics/max_object_counts", s.static())
+	handle("GET /cameras/v1/analytics/occupancy_trends", s.static())
+	handle("POST /cameras/v1/analytics/object_position_mqtt", s.static())
+	handle("POST /v2/analytics/operational_dashboard/{dashboard_id}/widget_trends/query", s.static())
+	handle("GET /cameras/v1/audio/status", s.static())
+	handle("POST /cameras/v1/audio/status", s.static())
+	handle("GET /cameras/v1/cloud_backup/settings", s.static())
+	handle("POST /cameras/v1/cloud_backup/settings", s.static())
+	handle("GET /cameras/v1/occupancy_trend_enabled", s.static())
+	handle("GET /cameras/v1/footage/link", s.static())
+	handle("GET /cameras/v1/footage/thumbnails/link", s.handleThumbnailLink)
+	handle("GET /cameras/v1/footage/thumbnails", s.file("image/jpeg", thumbnail))
+	handle("GET /cameras/v1/footage/thumbnails/latest", s.file("image/jpeg", thumbnail))
+	stream("GET /stream/cameras/v1/footage/stream/stream.m3u8", s.handlePlaylist)
+	stream("GET /stream/cameras/v1/footage/stream/{segment}", s.file("video/mp2t", segment))
+
+	// Helix
+	handle("GET /cameras/v1/video_tagging/event_type", s.list(HelixEventTypes, client.GetHelixEventTypesResponse{}, "event_types", noPaging, "event_type_uid", "name"))
+	handle("POST /cameras/v1/video_tagging/event_type", s.create(HelixEventTypes, client.CreateHelixEventTypeResponse{}))
+	handle("PATCH /cameras/v1/video_tagging/event_type", s.update(HelixEventTypes, client.UpdateHelixEventTypeResponse{}, "event_type_uid"))
+	handle("DELETE /cameras/v1/video_tagging/event_type", s.remove(HelixEventTypes, client.DeleteHelixEventTypeResponse{}, "event_type_uid"))
+	handle("GET /cameras/v1/video_tagging/event", s.static())
+	handle("POST /cameras/v1/video_tagging/event", s.static())
+	handle("PATCH /cameras/v1/video_tagging/event", s.static())
+	handle("DELETE /cameras/v1/video_tagging/event", s.static())
+
+	// Core
+	handle("GET /core/v1/audit_log", s.list(AuditLogs, client.GetAuditLogsResponse{}, "audit_logs", pageTokenPaging))
+	handle("GET /core/v1/user", s.get(Users, client.GetUserResponse{}, "user_id", "external_id"))
+	handle("POST /core/v1/user", s.create(Users, client.CreateUserResponse{}))
+	handle("PUT /core/v1/user", s.update(Users, client.UpdateUserResponse{}, "user_id", "external_id"))
+	handle("DELETE /core/v1/user", s.remove(Users, client.DeleteUserResponse{}, "user_id", "external_id"))
+
+	// Access
+	handle("GET /access/v1/access_groups", s.list(AccessGroups, client.GetAllAccessGroupsResponse{}, "access_groups", noPaging))
+	handle("GET /access/v1/access_groups/group", s.get(AccessGroups, client.AccessGroup{}, "group_id"))
+	handle("POST /access/v1/access_groups/group", s.create(AccessGroups, client.AccessGroup{}))
+	handle("DELETE /access/v1/access_groups/group", s.remove(AccessGroups, client.DeleteAccessGroupResponse{}, "group_id"))
+	handle("PUT /access/v1/access_groups/group/user", s.static())
+	handle("DELETE /access/v1/access_groups/group/user", s.static())
+	handle("GET /access/v1/access_users", s.list(AccessUsers, client.GetAllAccessUsersResponse{}, "access_members", noPaging))
+	handle("GET /access/v1/access_users/user", s.get(AccessUsers, client.AccessInformationObject{}, "user_id", "external_id", "email", "employee_id"))
+	for _, path := range []string{"ble/activate", "ble/deactivate", "end_date", "entry_code", "remote_unlock/activate", "remote_unlock/deactivate", "start_date"} {
+		handle("PUT /access/v1/access_users/user/"+path, s.static())
+	}
+	handle("DELETE /access/v1/access_users/user/entry_code", s.static())
+	handle("POST /access/v1/access_users/user/pass/invite", s.static())
+	handle("GET /access/v1/access_users/user/profile_photo", s.file("image/jpeg", thumbnail))
+	handle("PUT /access/v1/access_users/user/profile_photo", s.static())
+	handle("DELETE /access/v1/access_users/user/profile_photo", s.static())
+	for _, path := range []string{"card", "license_plate", "mfa_code"} {
+		handle("POST /access/v1/credentials/"+path, s.static())
+		handle("DELETE /access/v1/credentials/"+path, s.static())
+	}
+	for _, path := range []string{"card/activate", "card/deactivate", "license_plate/activate", "license_plate/deactivate"} {
+		handle("PUT /access/v1/credentials/"+path, s.static())
+	}
+	handle("GET /access/v1/door/access_level", s.list(AccessLevels, client.GetAllAccessLevelsResponse{}, "access_levels", noPaging))
+	handle("POST /access/v1/door/access_level", s.create(AccessLevels, client.AccessLevel{}))
+	handle("GET /access/v1/door/access_level/{access_level_id}", s.get(AccessLevels, client.AccessLevel{}, "access_level_id"))
+	handle("PUT /access/v1/door/access_level/{access_level_id}", s.update(AccessLevels, client.AccessLevel{}, "access_level_id"))
+	handle("DELETE /access/v1/door/access_level/{access_level_id}", s.remove(AccessLevels, client.DeleteAccessLevelResponse{}, "access_level_id"))
+	handle("POST /access/v1/door/access_level/{access_level_id}/access_schedule_event", s.static())
+	handle("GET /access/v1/door/access_level/{access_level_id}/access_schedule_event/{event_id}", s.static())
+	handle("PUT /access/v1/door/access_level/{access_level_id}/access_schedule_event/{event_id}", s.static())
+	handle("DELETE /access/v1/door/access_level/{access_level_id}/access_schedule_event/{event_id}", s.static())
+	handle("POST /access/v1/door/admin_unlock", s.static())
+	handle("POST /access/v1/door/user_unlock", s.static())
+	handle("GET /access/v1/doors", s.list(Doors, client.GetDoorsResponse{}, "doors", noPaging))
+	handle("GET /access/v1/door/exception_calendar", s.list(DoorExceptionCalendars, client.GetAllDoorExceptionCalendarsResponse{}, "door_exception_calendars", noPaging))
+	handle("POST /access/v1/door/exception_calendar", s.create(DoorExceptionCalendars, client.DoorExceptionCalendar{}))
+	handle("GET /access/v1/door/exception_calendar/{door_exception_calendar_id}", s.get(DoorExceptionCalendars, client.DoorExceptionCalendar{}, "door_exception_calendar_id"))
+	handle("PUT /access/v1/door/exception_calendar/{door_exception_calendar_id}", s.update(DoorExceptionCalendars, client.DoorExceptionCalendar{}, "door_exception_calendar_id"))
+	handle("DELETE /access/v1/door/exception_calendar/{door_exception_calendar_id}", s.remove(DoorExceptionCalendars, client.DeleteDoorExceptionCalendarResponse{}, "door_exception_calendar_id"))
+	handle("POST /access/v1/door/exception_calendar/{door_exception_calendar_id}/exception", s.static())
+	handle("GET /access/v1/door/exception_calendar/{door_exception_calendar_id}/exception/{exception_id}", s.static())
+	handle("PUT /access/v1/door/exception_calendar/{door_exception_calendar_id}/exception/{exception_id}", s.static())
+	handle("DELETE /access/v1/door/exception_calendar/{door_exception_calendar_id}/exception/{exception_id}", s.static())
+	handle("GET /access/v1/scenarios", s.list(AccessScenarios, client.GetAllAccessScenariosResponse{}, "scenarios", noPaging))
+	handle("POST /access/v1/scenarios/{scenario_id}/activate", s.static())
+	handle("POST /access/v1/scenarios/{scenario_id}/deactivate", s.static())
+	handle("GET /events/v1/access", s.list(AccessEvents, client.GetAccessEventsResponse{}, "events", pageTokenPaging))
+
+	// Sensor
+	handle("GET /environment/v1/alerts", s.list(SensorAlerts, client.GetSensorAlertsResponse{}, "alert_events", pageTokenPaging))
+	handle("GET /environment/v1/data", s.list(SensorData, client.GetSensorDataResponse{}, "data", pageTokenPaging))
+
+	// Guest
+	handle("GET /guest/v1/sites", s.list(GuestSites, client.GetGuestSitesResponse{}, "guest_sites", noPaging))
+	handle("GET /guest/v1/visits", s.list(GuestVisits, client.GetGuestVisitsResponse{}, "visits", visitPaging, "site_id"))
+	handle("GET /v2/guest/guest_types", s.list(GuestTypes, client.GetGuestTypesResponse{}, "items", cursorPaging))
+	handle("GET /v2/guest/hosts", s.list(GuestHosts, client.GetHostsResponse{}, "items", cursorPaging, "email"))
+	handle("POST /guest/v1/deny_list", s.static())
+	handle("DELETE /guest/v1/deny_list", s.static())
+
+	// Classic alarms
+	handle("GET /alarms/v1/devices", s.list(AlarmDevices, client.GetAlarmDevicesResponse{}, "devices", noPaging, "site_id"))
+	handle("GET /alarms/v1/sites", s.list(AlarmSites, client.GetAlarmSitesResponse{}, "sites", noPaging))
+
+	// Viewing stations
+	handle("GET /viewing_station/v1/devices", s.list(ViewingStations, client.GetVXDevicesResponse{}, "devices", noPaging))
+}
+
+// Serves a page of a collection as resp, with the items under itemsKey.
+// Items are filtered to those whose field equals the query parameter of the same name, for each of filters present in the request.
+func (s *Server) list(c Collection, resp any, itemsKey string, p paging, filters ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		s.mu.Lock()
+		var items []any
+		for _, item := range s.collection(c).items {
+			if matches(item, query, filters) {
+				items = append(items, item)
+			}
+		}
+		pageSize := s.pageSize
+		s.mu.Unlock()
+
+		out := map[string]any{}
+		if p.kind != unpaged {
+			if n, err := strconv.Atoi(query.Get(p.sizeParam)); err == nil && n > 0 {
+				pageSize = n
+			}
+			start, err := parseToken(query.Get(p.tokenParam))
+			if err != nil || start > len(items) {
+				writeError(w, http.StatusBadRequest, "invalid "+p.tokenParam)
+				return
+			}
+			end := min(start+pageSize, len(items))
+			next := ""
+			if end < len(items) {
+				next = strconv.Itoa(end)
+			}
+			items = items[start:end]
+			switch p.kind {
+			case stringPages:
+				out[p.nextField] = next
+			case intPages:
+				out[p.nextField], _ = strconv.Atoi(next)
+			case itemPages:
+				if len(items) > 0 {
+					if last, ok := items[len(items)-1].(map[string]any); ok {
+						items = append(items[:len(items)-1:len(items)-1], withField(last, p.nextField, next))
+					}
+				}
+			}
+		}
+		if items == nil {
+			items = []any{}
+		}
+		out[itemsKey] = items
+		s.respond(w, http.StatusOK, out, resp)
+	}
+}
+
+// Serves the item identified by the first of idParams present in the path or query.
+func (s *Server) get(c Collection, resp any, idParams ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		i := s.lookupLocked(c, r, idParams)
+		var item any
+		if i >= 0 {
+			item = s.collection(c).items[i]
+		}
+		s.mu.Unlock()
+		if i < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", c))
+			return
+		}
+		s.respond(w, http.StatusOK, item, resp)
+	}
+}
+
+// Adds the JSON request body, merged with any query parameters, to a collection and serves it as resp.
+func (s *Server) create(c Collection, resp any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, err := requestObject(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.mu.Lock()
+		if field := idFields[c]; field != "" && obj[field] != nil && s.findLocked(c, field, fmt.Sprint(obj[field])) >= 0 {
+			s.mu.Unlock()
+			writeError(w, http.StatusConflict, fmt.Sprintf("%s %v already exists", c, obj[field]))
+			return
+		}
+		item := s.addLocked(c, obj)
+		s.mu.Unlock()
+		s.respond(w, http.StatusOK, item, resp)
+	}
+}
+
+// Merges the JSON request body into the item identified by the first of idParams present in the path or query, and serves it as resp.
+func (s *Server) update(c Collection, resp any, idParams ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		obj, err := requestObject(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.mu.Lock()
+		i := s.lookupLocked(c, r, idParams)
+		var item any
+		if i >= 0 {
+			existing, _ := s.collection(c).items[i].(map[string]any)
+			merged := map[string]any{}
+			for k, v := range existing {
+				merged[k] = v
+			}
+			for k, v := range obj {
+				merged[k] = v
+			}
+			s.collection(c).items[i] = merged
+			item = merged
+		}
+		s.mu.Unlock()
+		if i < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", c))
+			return
+		}
+		s.respond(w, http.StatusOK, item, resp)
+	}
+}
+
+// Removes the item identified by the first of idParams present in the path or query, and serves it as resp.
+func (s *Server) remove(c Collection, resp any, idParams ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		i := s.lookupLocked(c, r, idParams)
+		var item any
+		if i >= 0 {
+			col := s.collection(c)
+			item = col.items[i]
+			col.items = append(col.items[:i:i], col.items[i+1:]...)
+		}
+		s.mu.Unlock()
+		if i < 0 {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", c))
+			return
+		}
+		s.respond(w, http.StatusOK, item, resp)
+	}
+}
+
+// Serves an empty JSON object, which decodes into any of the client package's response types.
+func (s *Server) static() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, []byte("{}"))
+	}
+}
+
+// GET /cameras/v1/footage/thumbnails/link, returning a link to the thumbnail served by the Server.
+func (s *Server) handleThumbnailLink(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	timestamp, _ := strconv.Atoi(query.Get("timestamp"))
+	expiry, err := strconv.Atoi(query.Get("expiry"))
+	if err != nil {
+		expiry = 86400
+	}
+	link := s.URL + "/verkadatest/thumbnails?" + url.Values{"camera_id": {query.Get("camera_id")}, "timestamp": {strconv.Itoa(timestamp)}}.Encode()
+	body, _ := json.Marshal(client.GetThumbnailLinkResponse{Expiry: expiry, Timestamp: timestamp, Url: link})
+	writeJSON(w, http.StatusOK, body)
+}
+
+// Serves a fixed file body.
+func (s *Server) file(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+	}
+}
+
+// Writes v as JSON after passing it through the response type resp, so that the body only contains fields the
+// client package knows about, as its strict decoding requires.
+func (s *Server) respond(w http.ResponseWriter, status int, v any, resp any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	shaped := reflect.New(reflect.TypeOf(resp))
+	if err := json.Unmarshal(b, shaped.Interface()); err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("verkadatest: stored item does not match %T: %v", resp, err))
+		return
+	}
+	b, err = json.Marshal(shaped.Interface())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, status, b)
+}
+
+// Returns the index of the item identified by the first of idParams present as a path value or query parameter, or -1. s.mu must be held.
+func (s *Server) lookupLocked(c Collection, r *http.Request, idParams []string) int {
+	for _, param := range idParams {
+		value := r.PathValue(param)
+		if value == "" {
+			value = r.URL.Query().Get(param)
+		}
+		if value != "" {
+			return s.findLocked(c, param, value)
+		}
+	}
+	return -1
+}
+
+// Decodes the JSON object in the request body, if any, adding query parameters that are not already set.
+func requestObject(r *http.Request) (map[string]any, error) {
+	obj := map[string]any{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&obj); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %v", err)
+		}
+	}
+	for k, v := range r.URL.Query() {
+		if _, ok := obj[k]; !ok && len(v) > 0 {
+			obj[k] = v[0]
+		}
+	}
+	return obj, nil
+}
+
+// Reports whether item matches every filter present in query.
+func matches(item any, query map[string][]string, filters []string) bool {
+	for _, f := range filters {
+		want, ok := query[f]
+		if !ok || len(want) == 0 || want[0] == "" {
+			continue
+		}
+		obj, isObj := item.(map[string]any)
+		if !isObj || fmt.Sprint(obj[f]) != want[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// Page tokens are the offset of the page's first item; an empty token is the first page.
+func parseToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(token)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	return n, nil
+}
+
+// Returns a copy of obj with field set to value.
+func withField(obj map[string]any, field string, value any) map[string]any {
+	out := make(map[string]any, len(obj)+1)
+	for k, v := range obj {
+		out[k] = v
+	}
+	out[field] = value
+	return out
+}
+
+// A small baseline JPEG (a gray gradient), served for thumbnails and profile photos.
+var thumbnail = func() []byte {
+	img := image.NewGray(image.Rect(0, 0, 64, 36))
+	for y := 0; y < 36; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(64 + 2*x + y)})
+		}
+	}
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, nil)
+	return buf.Bytes()
+}()
+
+// GET /stream/cameras/v1/footage/stream/stream.m3u8, listing the segments on the CDN if ServeSegmentsFromCDN is enabled.
+func (s *Server) handlePlaylist(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	onCDN := s.segmentsOnCDN
+	s.mu.Unlock()
+	body := playlist
+	if onCDN {
+		body = bytes.ReplaceAll(body, []byte("\nsegment_"), []byte("\n"+s.CDNURL+"/cdn/segment_"))
+		body = bytes.ReplaceAll(body, []byte(".ts\n"), []byte(".ts?sig="+cdnSignature+"\n"))
+	}
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Write(body)
+}
+
+// GET /cdn/{segment} on the CDN address, which checks the signature of the link instead of any credential.
+func (s *Server) handleCDNSegment(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("sig") != cdnSignature {
+		writeError(w, http.StatusForbidden, "invalid signature")
+		return
+	}
+	w.Header().Set("Content-Type", "video/mp2t")
+	w.Write(segment)
+}
+
+// Signature of the segment links listed on the CDN.
+const cdnSignature = "verkadatest-signature"
+
+// Minimal HLS playlist served for the footage stream endpoint, listing two segments served by the segment endpoint.
+var playlist = []byte("#EXTM3U\n#EXT-X-VERSION:3\n#EXT-X-TARGETDURATION:2\n#EXT-X-MEDIA-SEQUENCE:0\n#EXTINF:2.0,\nsegment_0.ts\n#EXTINF:2.0,\nsegment_1.ts\n#EXT-X-ENDLIST\n")
+
+// An MPEG-TS segment of a single null packet, served for every segment of the playlist.
+var segment = append([]byte{0x47, 0x1f, 0xff, 0x10}, bytes.Repeat([]byte{0xff}, 184)...)
@@ -0,0 +1,405 @@
+// Package verkadatest provides an in-process fake of the Verkada API for testing code built on the client package.
+//
+// A Server answers /token and the endpoints called by the client package, keeping its data in memory.
+// List endpoints are served from collections that tests seed with Seed and inspect with Items or Decode,
+// paginated the same way as the real API. Create, update, and delete endpoints for the main resources
+// (users, access groups, access levels, license plates and persons of interest, etc.) modify those collections,
+// and the remaining endpoints return empty responses. Footage stream endpoints authenticate with the jwt query
+// parameter, as the Streaming API does, accepting the JWTs issued by /cameras/v1/footage/token, and their segments can
+// be moved to a second address standing in for a CDN with ServeSegmentsFromCDN. Faults such as
+// 429s, 5xx responses, malformed JSON, or slow responses can be injected with InjectFault.
+//
+//	srv := verkadatest.NewServer()
+//	defer srv.Close()
+//	srv.Seed(verkadatest.Cameras, client.CameraDevice{Camera_id: "cam-1", Name: "Lobby"})
+//	c, err := srv.NewClient(nil)
+//	res, err := c.Camera.GetCameraDevices(nil)
+package verkadatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client"
+)
+
+// Default number of items per page when a request does not specify a page size.
+const DefaultPageSize = 100
+
+// A Server is a fake Verkada API listening on a local address. It is safe for concurrent use.
+type Server struct {
+	// Base URL of the server, for use as client.ClientOptions.BaseURL.
+	URL string
+	// Base URL of a second address standing in for a CDN, which serves footage segments once ServeSegmentsFromCDN
+	// is enabled.
+	CDNURL string
+
+	srv *httptest.Server
+	cdn *httptest.Server
+	// closed by Close to end the Delay of faults
+	closed chan struct{}
+
+	mu            sync.Mutex
+	apiKey        string
+	pageSize      int
+	collections   map[Collection]*collection
+	tokens        map[string]bool
+	tokenRequests int
+	faults        []*Fault
+	requests      []Request
+	nextID        int
+
+	// streaming JWTs issued by /cameras/v1/footage/token that have not been expired
+	streamingTokens        map[string]bool
+	streamingTokenRequests int
+	streamingScope         client.GetStreamingTokenResponse
+	segmentsOnCDN          bool
+}
+
+// A request received by the Server, as returned by Requests.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// A Fault makes matching requests fail instead of being handled normally, or only delays them.
+// Faults are checked in the order they were injected, before authentication.
+type Fault struct {
+	// HTTP method to match, or empty for any method.
+	Method string
+	// URL path to match exactly (e.g. "/cameras/v1/devices"), or empty for any path, including /token.
+	Path string
+	// Status code of the response; 0 means 200, which is only useful with Malformed.
+	StatusCode int
+	// Response body; a Verkada-style error body is used if empty.
+	Body string
+	// Value of the Retry-After header, if any. 429 faults default to "0" so that retries in tests are immediate.
+	RetryAfter string
+	// Respond with truncated JSON instead of Body.
+	Malformed bool
+	// Number of matching requests to fail before the fault is removed; 0 means until ClearFaults is called.
+	Times int
+	// Time to wait before responding, cut short if the request is cancelled or the Server is closed, e.g. to test
+	// timeouts. A fault with a Delay and no StatusCode, Body, or Malformed responds normally once it has waited.
+	Delay time.Duration
+}
+
+// Starts and returns a new Server. Callers should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		pageSize:    DefaultPageSize,
+		collections: map[Collection]*collection{},
+		tokens:      map[string]bool{},
+		closed:      make(chan struct{}),
+
+		streamingTokens: map[string]bool{},
+		streamingScope: client.GetStreamingTokenResponse{
+			AccessibleCameras: []string{},
+			AccessibleSites:   []string{},
+			Permission:        []string{client.StreamingPermissionLive, client.StreamingPermissionHistorical},
+		},
+	}
+	mux := http.NewServeMux()
+	s.routes(mux)
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+	cdn := http.NewServeMux()
+	cdn.HandleFunc("GET /cdn/{segment}", s.wrap(true, s.handleCDNSegment))
+	s.cdn = httptest.NewServer(cdn)
+	s.CDNURL = s.cdn.URL
+	return s
+}
+
+// Shuts down the server and blocks until all outstanding requests have completed.
+func (s *Server) Close() {
+	close(s.closed)
+	s.srv.Close()
+	s.cdn.Close()
+}
+
+// Returns a Client configured to use the Server. Any fields of options are kept except BaseURL.
+// If not set, APIKey defaults to "verkadatest", HTTPClient to one that connects to the Server,
+// and RetryPolicy to DefaultRetryPolicy with millisecond delays so that tests with injected faults stay fast.
+func (s *Server) NewClient(options *client.ClientOptions) (*client.Client, error) {
+	var opts client.ClientOptions
+	if options != nil {
+		opts = *options
+	}
+	opts.BaseURL = s.URL
+	if opts.APIKey == "" {
+		opts.APIKey = "verkadatest"
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = s.srv.Client()
+	}
+	if opts.RetryPolicy == nil {
+		opts.RetryPolicy = client.DefaultRetryPolicy()
+		opts.RetryPolicy.BaseDelay = time.Millisecond
+		opts.RetryPolicy.MaxDelay = 10 * time.Millisecond
+	}
+	return client.New(&opts)
+}
+
+// Restricts /token to the given API key. By default any non-empty key is accepted.
+func (s *Server) SetAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.apiKey = key
+}
+
+// Sets the number of items per page used when a request does not specify a page size.
+func (s *Server) SetPageSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = n
+}
+
+// Adds a fault that makes matching requests fail.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// Removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Invalidates every token issued so far, so that the next request with one is rejected with a 401.
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.tokens)
+}
+
+// Returns the number of requests made to /token.
+func (s *Server) TokenRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokenRequests
+}
+
+// Invalidates every streaming JWT issued so far, so that the next stream request with one is rejected with a 401,
+// as happens once a real streaming JWT expires.
+func (s *Server) ExpireStreamingTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.streamingTokens)
+}
+
+// Sets the scope reported by streaming tokens issued from now on and enforced by the stream endpoints.
+// By default tokens list no cameras or sites, which the Server treats as every camera, and both permissions.
+// A camera is in the scope of a site if it is seeded in Cameras with that site_id.
+func (s *Server) SetStreamingScope(cameras []string, sites []string, permissions []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streamingScope.AccessibleCameras = append([]string{}, cameras...)
+	s.streamingScope.AccessibleSites = append([]string{}, sites...)
+	s.streamingScope.Permission = append([]string{}, permissions...)
+}
+
+// Makes the footage stream playlist list its segments as signed links on CDNURL instead of relative to the playlist,
+// as the Streaming API may list segments on a CDN. Requests to the CDN need no token or JWT, only the signature of
+// the link; they are recorded by Requests under paths such as /cdn/segment_0.ts, and can fail with InjectFault.
+func (s *Server) ServeSegmentsFromCDN(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.segmentsOnCDN = enabled
+}
+
+// Returns the number of requests made to /cameras/v1/footage/token.
+func (s *Server) StreamingTokenRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streamingTokenRequests
+}
+
+// Returns every request received so far, in order, including those to /token.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Wraps a route handler with request recording, fault injection, and (unless public) token authentication.
+func (s *Server) wrap(public bool, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+		fault := s.takeFaultLocked(r)
+		authorized := public || s.tokens[r.Header.Get("x-verkada-auth")]
+		s.mu.Unlock()
+
+		if fault != nil && fault.Delay > 0 {
+			select {
+			case <-time.After(fault.Delay):
+			case <-r.Context().Done():
+				return
+			case <-s.closed:
+				return
+			}
+			if fault.StatusCode == 0 && fault.Body == "" && !fault.Malformed {
+				fault = nil
+			}
+		}
+		switch {
+		case fault != nil:
+			writeFault(w, fault)
+		case !authorized:
+			writeError(w, http.StatusUnauthorized, "invalid or expired token")
+		default:
+			h(w, r)
+		}
+	}
+}
+
+// Returns the first fault matching r, if any, consuming one of its Times. s.mu must be held.
+func (s *Server) takeFaultLocked(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || (f.Path != "" && f.Path != r.URL.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func writeFault(w http.ResponseWriter, f *Fault) {
+	status := f.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	retryAfter := f.RetryAfter
+	if retryAfter == "" && status == http.StatusTooManyRequests {
+		retryAfter = "0"
+	}
+	if retryAfter != "" {
+		w.Header().Set("Retry-After", retryAfter)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case f.Malformed:
+		w.WriteHeader(status)
+		io.WriteString(w, `{"malformed": [`)
+	case f.Body != "":
+		w.WriteHeader(status)
+		io.WriteString(w, f.Body)
+	default:
+		writeError(w, status, http.StatusText(status))
+	}
+}
+
+// Wraps a Streaming API handler, which authenticates with the jwt query parameter instead of an API token.
+func (s *Server) requireStreamingJWT(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		permission := client.StreamingPermissionLive
+		if query.Has("start_time") {
+			permission = client.StreamingPermissionHistorical
+		}
+		s.mu.Lock()
+		authorized := s.streamingTokens[query.Get("jwt")]
+		inScope := s.cameraInStreamingScopeLocked(query.Get("camera_id"))
+		permissions := s.streamingScope.Permission
+		s.mu.Unlock()
+		switch {
+		case !authorized:
+			writeError(w, http.StatusUnauthorized, "invalid or expired streaming token")
+		case query.Has("camera_id") && (!inScope || !slices.Contains(permissions, permission)):
+			writeError(w, http.StatusForbidden, "camera is outside the scope of the streaming token")
+		default:
+			h(w, r)
+		}
+	}
+}
+
+// Reports whether the streaming scope grants camera_id, either by listing it or by listing the site_id of the seeded
+// camera. A scope listing no cameras or sites grants every camera. s.mu must be held.
+func (s *Server) cameraInStreamingScopeLocked(camera_id string) bool {
+	cameras, sites := s.streamingScope.AccessibleCameras, s.streamingScope.AccessibleSites
+	if (len(cameras) == 0 && len(sites) == 0) || slices.Contains(cameras, camera_id) {
+		return true
+	}
+	for _, item := range s.collection(Cameras).items {
+		if obj, ok := item.(map[string]any); ok && obj["camera_id"] == camera_id {
+			site, _ := obj["site_id"].(string)
+			return slices.Contains(sites, site)
+		}
+	}
+	return false
+}
+
+// Writes an error body in the format used by the Verkada API.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-request-id", "verkadatest-"+strconv.Itoa(status))
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"id": "verkadatest", "message": message, "data": nil})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// POST /token
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("x-api-key")
+	s.mu.Lock()
+	s.tokenRequests++
+	ok := key != "" && (s.apiKey == "" || key == s.apiKey)
+	token := fmt.Sprintf("verkadatest-token-%d", s.tokenRequests)
+	if ok {
+		s.tokens[token] = true
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+	body, _ := json.Marshal(map[string]string{"token": token})
+	writeJSON(w, http.StatusOK, body)
+}
+
+// GET /cameras/v1/footage/token
+func (s *Server) handleStreamingToken(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("x-api-key") == "" {
+		writeError(w, http.StatusUnauthorized, "invalid API key")
+		return
+	}
+	s.mu.Lock()
+	s.streamingTokenRequests++
+	token := s.streamingScope
+	token.Jwt = fmt.Sprintf("verkadatest-streaming-jwt-%d", s.streamingTokenRequests)
+	s.streamingTokens[token.Jwt] = true
+	s.mu.Unlock()
+	token.Expiration = 1800
+	token.ExpiresAt = int(time.Now().Add(30 * time.Minute).Unix())
+	body, _ := json.Marshal(token)
+	writeJSON(w, http.StatusOK, body)
+}
@@ -0,0 +1,749 @@
+package verkadatest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/GDRCode/verkada-api-go/pkg/client"
+	"github.com/GDRCode/verkada-api-go/pkg/client/verkadatest"
+)
+
+func newServer(t *testing.T) *verkadatest.Server {
+	t.Helper()
+	srv := verkadatest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Returns a Client built with client.New rather than Server.NewClient, as code under test would.
+func newClient(t *testing.T, srv *verkadatest.Server, options client.ClientOptions) *client.Client {
+	t.Helper()
+	options.BaseURL = srv.URL
+	if options.APIKey == "" {
+		options.APIKey = "test-key"
+	}
+	if options.RetryPolicy == nil {
+		options.RetryPolicy = &client.RetryPolicy{MaxAttempts: 3, RetryServerErrors: true, RespectRetryAfter: true}
+	}
+	c, err := client.New(&options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// Counts the requests received by srv for path.
+func requestsTo(srv *verkadatest.Server, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func seedCameras(srv *verkadatest.Server, n int) {
+	for i := range n {
+		srv.Seed(verkadatest.Cameras, client.CameraDevice{Camera_id: fmt.Sprintf("cam-%03d", i), Name: fmt.Sprintf("Camera %d", i)})
+	}
+}
+
+func TestToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverKey  string
+		key        string
+		wantStatus int
+	}{
+		{"any key", "", "anything", http.StatusOK},
+		{"matching key", "secret", "secret", http.StatusOK},
+		{"wrong key", "secret", "other", http.StatusUnauthorized},
+		{"no key", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			srv.SetAPIKey(tt.serverKey)
+			req, _ := http.NewRequest("POST", srv.URL+"/token", nil)
+			req.Header.Set("x-api-key", tt.key)
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			var body struct {
+				Token string `json:"token"`
+			}
+			json.NewDecoder(res.Body).Decode(&body)
+			if res.StatusCode != tt.wantStatus || (tt.wantStatus == http.StatusOK) != (body.Token != "") {
+				t.Errorf("got %d with token %q, want %d", res.StatusCode, body.Token, tt.wantStatus)
+			}
+			if srv.TokenRequests() != 1 {
+				t.Errorf("counted %d token requests", srv.TokenRequests())
+			}
+		})
+	}
+}
+
+func TestTokenRequired(t *testing.T) {
+	srv := newServer(t)
+	for _, token := range []string{"", "made-up"} {
+		req, _ := http.NewRequest("GET", srv.URL+"/cameras/v1/devices", nil)
+		if token != "" {
+			req.Header.Set("x-verkada-auth", token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("token %q got %d, want 401", token, res.StatusCode)
+		}
+	}
+}
+
+func TestClientTokenFlow(t *testing.T) {
+	srv := newServer(t)
+	srv.SetAPIKey("test-key")
+	seedCameras(srv, 1)
+	c := newClient(t, srv, client.ClientOptions{})
+	if srv.TokenRequests() != 1 {
+		t.Fatalf("New made %d token requests, want 1", srv.TokenRequests())
+	}
+	if _, err := c.Camera.GetCameraDevices(nil); err != nil {
+		t.Fatal(err)
+	}
+	// an expired token is rejected with a 401, and the client requests a new one and retries
+	srv.ExpireTokens()
+	res, err := c.Camera.GetCameraDevices(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Cameras) != 1 || srv.TokenRequests() != 2 || requestsTo(srv, "/cameras/v1/devices") != 3 {
+		t.Errorf("got %d cameras after %d token requests and %d device requests", len(res.Cameras), srv.TokenRequests(), requestsTo(srv, "/cameras/v1/devices"))
+	}
+
+	_, err = client.New(&client.ClientOptions{BaseURL: srv.URL, APIKey: "wrong-key"})
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("New with the wrong key returned %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		cameras      int
+		pageSize     int
+		autoPaginate bool
+		want         int
+		wantRequests int
+	}{
+		{"single page", 10, 100, false, 10, 1},
+		{"first page only", 250, 100, false, 100, 1},
+		{"auto paginate", 250, 100, true, 250, 3},
+		{"exact pages", 200, 100, true, 200, 2},
+		{"empty", 0, 100, true, 0, 1},
+		{"small pages", 7, 2, true, 7, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			srv.SetPageSize(tt.pageSize)
+			seedCameras(srv, tt.cameras)
+			c := newClient(t, srv, client.ClientOptions{AutoPaginate: tt.autoPaginate})
+			res, err := c.Camera.GetCameraDevices(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Cameras) != tt.want || requestsTo(srv, "/cameras/v1/devices") != tt.wantRequests {
+				t.Errorf("got %d cameras in %d requests, want %d in %d", len(res.Cameras), requestsTo(srv, "/cameras/v1/devices"), tt.want, tt.wantRequests)
+			}
+			for i, cam := range res.Cameras {
+				if want := fmt.Sprintf("cam-%03d", i); cam.Camera_id != want {
+					t.Fatalf("camera %d is %s, want %s", i, cam.Camera_id, want)
+				}
+			}
+			if tt.want < tt.cameras && res.Next_page_token == "" {
+				t.Errorf("no next page token")
+			}
+		})
+	}
+}
+
+func TestPaginationStyles(t *testing.T) {
+	srv := newServer(t)
+	srv.SetPageSize(2)
+	for i := range 5 {
+		srv.Seed(verkadatest.SeenPlates, client.PlateDetection{License_plate: "ABC123", Timestamp: i})
+		srv.Seed(verkadatest.GuestTypes, client.GuestType{Guest_type_id: fmt.Sprint(i)})
+		srv.Seed(verkadatest.GuestVisits, client.GuestVisit{Visit_id: fmt.Sprint(i), Site_id: "site-1"})
+	}
+	srv.Seed(verkadatest.GuestVisits, client.GuestVisit{Visit_id: "other", Site_id: "site-2"})
+	c := newClient(t, srv, client.ClientOptions{DecodeMode: client.DecodeLenient})
+	ctx := context.Background()
+
+	// integer page tokens
+	var plates []int
+	for p, err := range c.Camera.GetSeenPlatesIter(ctx, "cam-1", &client.GetSeenPlatesOptions{License_plate: "ABC123"}).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		plates = append(plates, p.Timestamp)
+	}
+	// cursors
+	var types []string
+	for gt, err := range c.Guest.GetGuestTypesIter(ctx, "site-1", nil).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, gt.Guest_type_id)
+	}
+	// tokens on the last item of each page, filtered by site
+	var visits []string
+	for v, err := range c.Guest.GetGuestVisitsIter(ctx, "site-1", client.Int(0), client.Int(3600), nil).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		visits = append(visits, v.Visit_id)
+	}
+	if !slices.Equal(plates, []int{0, 1, 2, 3, 4}) || !slices.Equal(types, []string{"0", "1", "2", "3", "4"}) || !slices.Equal(visits, []string{"0", "1", "2", "3", "4"}) {
+		t.Errorf("got plates %v, guest types %v, visits %v", plates, types, visits)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		fault  verkadatest.Fault
+		// GET /cameras/v1/devices unless method is POST, which creates a user
+		wantErr      error
+		wantMalform  bool
+		wantRequests int
+	}{
+		{"rate limited once", "GET", verkadatest.Fault{Path: "/cameras/v1/devices", StatusCode: 429, Times: 1}, nil, false, 2},
+		{"rate limited throughout", "GET", verkadatest.Fault{Path: "/cameras/v1/devices", StatusCode: 429}, client.ErrRateLimited, false, 3},
+		{"server error once", "GET", verkadatest.Fault{Path: "/cameras/v1/devices", StatusCode: 503, Times: 2}, nil, false, 3},
+		{"server error throughout", "GET", verkadatest.Fault{Path: "/cameras/v1/devices", StatusCode: 500}, client.ErrServer, false, 3},
+		{"server error on a write is not retried", "POST", verkadatest.Fault{Path: "/core/v1/user", StatusCode: 500, Times: 1}, client.ErrServer, false, 1},
+		{"custom body", "GET", verkadatest.Fault{Path: "/cameras/v1/devices", StatusCode: 404, Body: `{"message": "gone"}`}, client.ErrNotFound, false, 1},
+		{"malformed JSON", "GET", verkadatest.Fault{Path: "/cameras/v1/devices", Malformed: true, Times: 1}, nil, true, 1},
+		{"other method is unaffected", "GET", verkadatest.Fault{Method: "POST", StatusCode: 500}, nil, false, 1},
+		{"other path is unaffected", "GET", verkadatest.Fault{Path: "/core/v1/user", StatusCode: 500}, nil, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			seedCameras(srv, 3)
+			c := newClient(t, srv, client.ClientOptions{})
+			srv.InjectFault(tt.fault)
+			path, err := "/cameras/v1/devices", error(nil)
+			if tt.method == "POST" {
+				path = "/core/v1/user"
+				_, err = c.Core.CreateUser(&client.CreateUserBody{Email: "a@example.com"})
+			} else {
+				var res *client.GetCameraDevicesResponse
+				res, err = c.Camera.GetCameraDevices(nil)
+				if err == nil && len(res.Cameras) != 3 {
+					t.Errorf("got %d cameras", len(res.Cameras))
+				}
+			}
+			switch {
+			case tt.wantMalform:
+				var syntaxErr *json.SyntaxError
+				if err == nil || errors.As(err, new(*client.APIError)) || (!errors.As(err, &syntaxErr) && !errors.Is(err, io.ErrUnexpectedEOF) && !strings.Contains(err.Error(), "unexpected end of JSON")) {
+					t.Errorf("got %v, want a JSON decoding error", err)
+				}
+			case !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil):
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+			if got := requestsTo(srv, path); got != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestFaultsClear(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv, client.ClientOptions{RetryPolicy: &client.RetryPolicy{MaxAttempts: 1}})
+	srv.InjectFault(verkadatest.Fault{StatusCode: 502})
+	if _, err := c.Camera.GetCameraDevices(nil); !errors.Is(err, client.ErrServer) {
+		t.Fatalf("got %v", err)
+	}
+	srv.ClearFaults()
+	if _, err := c.Camera.GetCameraDevices(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollections(t *testing.T) {
+	srv := newServer(t)
+	c := newClient(t, srv, client.ClientOptions{DecodeMode: client.DecodeLenient})
+	created, err := c.Core.CreateUser(&client.CreateUserBody{Email: "a@example.com", First_name: "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.User_id == "" {
+		t.Fatal("created user has no user_id")
+	}
+	got, err := c.Core.GetUser(&client.GetUserOptions{User_id: created.User_id})
+	if err != nil || got.Email != "a@example.com" {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+	var users []client.GetUserResponse
+	if err := srv.Decode(verkadatest.Users, &users); err != nil || len(users) != 1 || users[0].First_name != "Ada" {
+		t.Errorf("collection holds %+v, %v", users, err)
+	}
+	if _, err := c.Core.DeleteUser(&client.DeleteUserOptions{User_id: created.User_id}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Core.GetUser(&client.GetUserOptions{User_id: created.User_id}); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("got %v after delete, want ErrNotFound", err)
+	}
+}
+
+func TestDoorUnlock(t *testing.T) {
+	tests := []struct {
+		name   string
+		unlock func(c *client.Client) error
+		path   string
+		body   string
+	}{
+		{"admin", func(c *client.Client) error {
+			_, err := c.Access.AdminUnlockDoor("door-1")
+			return err
+		}, "/access/v1/door/admin_unlock", `{"door_id":"door-1"}`},
+		{"user", func(c *client.Client) error {
+			_, err := c.Access.UserUnlockDoor("door-1", &client.UserUnlockDoorOptions{User_id: "user-1"})
+			return err
+		}, "/access/v1/door/user_unlock", `{"door_id":"door-1","user_id":"user-1"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			c := newClient(t, srv, client.ClientOptions{DecodeMode: client.DecodeLenient})
+			if err := tt.unlock(c); err != nil {
+				t.Fatal(err)
+			}
+			requests := srv.Requests()
+			last := requests[len(requests)-1]
+			if last.Method != "POST" || last.Path != tt.path || strings.TrimSpace(string(last.Body)) != tt.body {
+				t.Errorf("sent %s %s %s, want POST %s %s", last.Method, last.Path, last.Body, tt.path, tt.body)
+			}
+		})
+	}
+}
+
+func TestStreamingScope(t *testing.T) {
+	tests := []struct {
+		name string
+		// cameras the token is scoped to after it is issued, as if one was added meanwhile
+		rescoped []string
+		// refresh window of the token source; that of the Client's StreamingTokens if zero
+		window    time.Duration
+		request   func(s *client.StreamingTokenSource, c *client.Client) error
+		wantErr   *client.StreamingScopeError
+		wantToken int
+	}{
+		{"camera in scope", nil, 0, func(s *client.StreamingTokenSource, c *client.Client) error {
+			_, err := s.JWTForCamera(context.Background(), "cam-1", client.StreamingPermissionLive)
+			return err
+		}, nil, 1},
+		{"camera out of scope", nil, 0, func(s *client.StreamingTokenSource, c *client.Client) error {
+			_, err := s.JWTForCamera(context.Background(), "cam-2", client.StreamingPermissionLive)
+			return err
+		}, &client.StreamingScopeError{Camera_id: "cam-2"}, 1},
+		{"permission out of scope", nil, 0, func(s *client.StreamingTokenSource, c *client.Client) error {
+			_, err := s.JWTForCamera(context.Background(), "cam-1", client.StreamingPermissionHistorical)
+			return err
+		}, &client.StreamingScopeError{Camera_id: "cam-1", Permission: client.StreamingPermissionHistorical}, 1},
+		{"link out of scope", nil, 0, func(s *client.StreamingTokenSource, c *client.Client) error {
+			_, err := s.StreamingLink(context.Background(), "org-1", "cam-2", nil)
+			return err
+		}, &client.StreamingScopeError{Camera_id: "cam-2"}, 1},
+		{"footage out of scope", nil, 0, func(s *client.StreamingTokenSource, c *client.Client) error {
+			_, err := c.Camera.DownloadFootage("org-1", "cam-2", &client.DownloadFootageOptions{Start_time: client.Int(1000), End_time: client.Int(1060)}, io.Discard)
+			return err
+		}, &client.StreamingScopeError{Camera_id: "cam-2"}, 1},
+		// a token issued within the refresh window is not replaced, so the camera stays out of scope
+		{"camera added within the refresh window", []string{"cam-1", "cam-2"}, 0, func(s *client.StreamingTokenSource, c *client.Client) error {
+			_, err := s.JWTForCamera(context.Background(), "cam-2", client.StreamingPermissionLive)
+			return err
+		}, &client.StreamingScopeError{Camera_id: "cam-2"}, 1},
+		{"camera added after the refresh window", []string{"cam-1", "cam-2"}, time.Nanosecond, func(s *client.StreamingTokenSource, c *client.Client) error {
+			_, err := s.JWTForCamera(context.Background(), "cam-2", client.StreamingPermissionLive)
+			return err
+		}, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			srv.SetStreamingScope([]string{"cam-1"}, nil, []string{client.StreamingPermissionLive})
+			c := newClient(t, srv, client.ClientOptions{})
+			s := c.StreamingTokens
+			if tt.window != 0 {
+				s = client.NewStreamingTokenSource(c, tt.window)
+			}
+			if _, err := s.Token(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if tt.rescoped != nil {
+				srv.SetStreamingScope(tt.rescoped, nil, []string{client.StreamingPermissionLive})
+			}
+			err := tt.request(s, c)
+			var scopeErr *client.StreamingScopeError
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("got %v", err)
+			case tt.wantErr != nil && !errors.As(err, &scopeErr):
+				t.Fatalf("got %v, want a *StreamingScopeError", err)
+			case tt.wantErr != nil:
+				if scopeErr.Camera_id != tt.wantErr.Camera_id || scopeErr.Permission != tt.wantErr.Permission || !slices.Equal(scopeErr.AccessibleCameras, []string{"cam-1"}) {
+					t.Errorf("got %+v, want camera %s and permission %q", scopeErr, tt.wantErr.Camera_id, tt.wantErr.Permission)
+				}
+				if !errors.Is(err, client.ErrForbidden) {
+					t.Errorf("%v does not match ErrForbidden", err)
+				}
+			}
+			if n := srv.StreamingTokenRequests(); n != tt.wantToken {
+				t.Errorf("requested %d streaming tokens, want %d", n, tt.wantToken)
+			}
+			// scope errors are found locally, before anything is streamed
+			if tt.wantErr != nil {
+				for _, r := range srv.Requests() {
+					if strings.HasPrefix(r.Path, "/stream/") {
+						t.Errorf("sent a request to %s", r.Path)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestStreamingScopeSites(t *testing.T) {
+	srv := newServer(t)
+	srv.SetStreamingScope([]string{"cam-1"}, []string{"site-1"}, []string{client.StreamingPermissionLive})
+	srv.Seed(verkadatest.Cameras,
+		client.CameraDevice{Camera_id: "cam-1", Site_id: "site-2"},
+		client.CameraDevice{Camera_id: "cam-2", Site_id: "site-1"},
+		client.CameraDevice{Camera_id: "cam-3", Site_id: "site-2"},
+	)
+	c := newClient(t, srv, client.ClientOptions{})
+	tests := []struct {
+		camera_id string
+		wantErr   error
+	}{
+		{"cam-1", nil},
+		// granted only through its site
+		{"cam-2", nil},
+		// not found locally, since its site is unknown to the client, but rejected by the API
+		{"cam-3", client.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.camera_id, func(t *testing.T) {
+			if _, err := c.StreamingTokens.JWTForCamera(context.Background(), tt.camera_id, client.StreamingPermissionLive); err != nil {
+				t.Fatalf("JWTForCamera: %v", err)
+			}
+			_, err := c.Camera.StreamFootageToWriter("org-1", tt.camera_id, "", nil, io.Discard)
+			var scopeErr *client.StreamingScopeError
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) || errors.As(err, &scopeErr) {
+				t.Errorf("got %v, want %v from the API", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDownloadFootageFromCDN(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault verkadatest.Fault
+		// requests for the segment that fails, including retries
+		wantRequests int
+		wantErr      error
+	}{
+		{"retried after a 503", verkadatest.Fault{Path: "/cdn/segment_1.ts", StatusCode: http.StatusServiceUnavailable, Times: 1}, 2, nil},
+		{"retried after a 429", verkadatest.Fault{Path: "/cdn/segment_1.ts", StatusCode: http.StatusTooManyRequests, Times: 2}, 3, nil},
+		{"not retried after a 404", verkadatest.Fault{Path: "/cdn/segment_1.ts", StatusCode: http.StatusNotFound, Times: 1}, 1, client.ErrNotFound},
+		{"gives up after MaxAttempts", verkadatest.Fault{Path: "/cdn/segment_1.ts", StatusCode: http.StatusBadGateway}, 3, client.ErrServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			srv.ServeSegmentsFromCDN(true)
+			srv.InjectFault(tt.fault)
+			c := newClient(t, srv, client.ClientOptions{})
+			var buf bytes.Buffer
+			res, err := c.Camera.DownloadFootage("org-1", "cam-1", &client.DownloadFootageOptions{Start_time: client.Int(1000), End_time: client.Int(1060), Parallelism: 1}, &buf)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "verkadatest-signature") {
+				t.Errorf("error %q includes the signature", err)
+			}
+			if tt.wantErr == nil && (res.Segments != 2 || buf.Len() != 2*188) {
+				t.Errorf("downloaded %d segments, %d bytes", res.Segments, buf.Len())
+			}
+			if n := requestsTo(srv, "/cdn/segment_1.ts"); n != tt.wantRequests {
+				t.Errorf("requested the CDN segment %d times, want %d", n, tt.wantRequests)
+			}
+			for _, r := range srv.Requests() {
+				if strings.HasPrefix(r.Path, "/cdn/") && (r.Header.Get("x-verkada-auth") != "" || r.Header.Get("x-api-key") != "" || r.Query.Has("jwt")) {
+					t.Errorf("CDN request %s was sent credentials", r.Path)
+				}
+			}
+		})
+	}
+}
+
+// Returns a ClientPool with an org per name, each using key if non-empty and else a key of its own.
+func newPool(t *testing.T, srv *verkadatest.Server, key string, rates map[client.EndpointFamily]client.Rate, names ...string) *client.ClientPool {
+	t.Helper()
+	var profiles []client.OrgProfile
+	for _, name := range names {
+		profile := client.OrgProfile{Name: name, APIKey: key, BaseURL: srv.URL, RateLimits: rates}
+		if key == "" {
+			profile.APIKey = name + "-key"
+		}
+		profiles = append(profiles, profile)
+	}
+	pool, err := client.NewClientPool(profiles, &client.ClientOptions{RetryPolicy: &client.RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestFanOutParallelism(t *testing.T) {
+	srv := newServer(t)
+	seedCameras(srv, 3)
+	pool := newPool(t, srv, "", nil, "a", "b", "c", "d", "e", "f")
+	var running, most atomic.Int32
+	results := client.FanOut(context.Background(), pool, 2, func(ctx context.Context, org string, c *client.Client) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for m := most.Load(); n > m && !most.CompareAndSwap(m, n); m = most.Load() {
+		}
+		res, err := c.Camera.GetCameraDevicesContext(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		time.Sleep(10 * time.Millisecond)
+		return len(res.Cameras), nil
+	})
+	if err := results.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := most.Load(); n != 2 {
+		t.Errorf("ran %d calls at once, want 2", n)
+	}
+	if got := slices.Collect(maps.Keys(results.Values())); len(got) != 6 {
+		t.Errorf("got values for %v", got)
+	}
+	for _, result := range results {
+		if result.Value != 3 {
+			t.Errorf("org %s listed %d cameras", result.Org, result.Value)
+		}
+	}
+	// each org authenticates with its own key
+	if n := requestsTo(srv, "/token"); n != 6 {
+		t.Errorf("made %d token requests, want 6", n)
+	}
+}
+
+func TestFanOutErrors(t *testing.T) {
+	srv := newServer(t)
+	srv.SetAPIKey("a-key")
+	srv.InjectFault(verkadatest.Fault{Path: "/cameras/v1/devices", StatusCode: http.StatusInternalServerError, Times: 1})
+	pool := newPool(t, srv, "", nil, "a", "b")
+	// a fails on the injected 500, and b on its key being rejected
+	results := client.FanOut(context.Background(), pool, 1, func(ctx context.Context, org string, c *client.Client) (*client.GetCameraDevicesResponse, error) {
+		return c.Camera.GetCameraDevicesContext(ctx, nil)
+	})
+	if len(results) != 2 || results[0].Org != "a" || results[1].Org != "b" {
+		t.Fatalf("got results %+v", results)
+	}
+	if !errors.Is(results[0].Err, client.ErrServer) || !errors.Is(results[1].Err, client.ErrUnauthorized) {
+		t.Errorf("got errors %v and %v", results[0].Err, results[1].Err)
+	}
+	var orgErr *client.OrgError
+	err := results.Err()
+	if !errors.As(err, &orgErr) || !errors.Is(err, client.ErrServer) || !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("got %v, want both errors as *OrgError", err)
+	}
+	// a succeeds once the fault is spent, while b keeps failing
+	results = client.FanOut(context.Background(), pool, 1, func(ctx context.Context, org string, c *client.Client) (*client.GetCameraDevicesResponse, error) {
+		return c.Camera.GetCameraDevicesContext(ctx, nil)
+	})
+	if values := results.Values(); len(values) != 1 || values["a"] == nil {
+		t.Errorf("got values %v", values)
+	}
+}
+
+func TestFanOutCancel(t *testing.T) {
+	srv := newServer(t)
+	pool := newPool(t, srv, "", nil, "a", "b", "c", "d")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	results := client.FanOut(ctx, pool, 1, func(ctx context.Context, org string, c *client.Client) (*client.GetCameraDevicesResponse, error) {
+		calls.Add(1)
+		defer cancel()
+		return c.Camera.GetCameraDevicesContext(ctx, nil)
+	})
+	if n := calls.Load(); n != 1 {
+		t.Errorf("started %d calls after ctx was cancelled, want 1", n)
+	}
+	if results[0].Err != nil {
+		t.Errorf("org a failed: %v", results[0].Err)
+	}
+	for _, result := range results[1:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("org %s got %v, want context.Canceled", result.Org, result.Err)
+		}
+	}
+	if n := requestsTo(srv, "/cameras/v1/devices"); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
+
+func TestClientPoolSharedRateLimiter(t *testing.T) {
+	srv := newServer(t)
+	rates := map[client.EndpointFamily]client.Rate{client.FamilyCameras: {PerSecond: 1, Burst: 1}}
+	shared := newPool(t, srv, "shared-key", rates, "a", "b")
+	own := newPool(t, srv, "", rates, "c")
+	for _, c := range []*client.Client{must(shared.Client("a")), must(own.Client("c"))} {
+		if _, err := c.Camera.GetCameraDevices(nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// b has to wait for the request a made with the same key, about a second, while c's own quota is unaffected
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := must(shared.Client("b")).Camera.GetCameraDevicesContext(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("org b got %v, want context.DeadlineExceeded", err)
+	}
+	if n := requestsTo(srv, "/cameras/v1/devices"); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+}
+
+func must(c *client.Client, ok bool) *client.Client {
+	if !ok {
+		panic("verkadatest: org not in pool")
+	}
+	return c
+}
+
+func TestLoadOrgProfiles(t *testing.T) {
+	srv := newServer(t)
+	seedCameras(srv, 1)
+	t.Setenv("HQ_API_KEY", "hq-key")
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "branch-key")
+	if err := os.WriteFile(keyFile, []byte("branch-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "orgs.json")
+	content := fmt.Sprintf(`[
+		{"name": "hq", "api_key_env": "HQ_API_KEY", "base_url": %q, "rate_limits": {"cameras": {"per_second": 10, "burst": 20}}},
+		{"name": "branch", "api_key_file": %q, "base_url": %q}
+	]`, srv.URL, keyFile, srv.URL)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := client.LoadOrgProfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hqRates := map[client.EndpointFamily]client.Rate{client.FamilyCameras: {PerSecond: 10, Burst: 20}}
+	if len(profiles) != 2 || profiles[0].Name != "hq" || profiles[0].APIKeyEnv != "HQ_API_KEY" || !maps.Equal(profiles[0].RateLimits, hqRates) ||
+		profiles[1].Name != "branch" || profiles[1].APIKeyFile != keyFile || profiles[1].BaseURL != srv.URL {
+		t.Fatalf("got %+v", profiles)
+	}
+	pool, err := client.NewClientPool(profiles, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if orgs := pool.Orgs(); !slices.Equal(orgs, []string{"branch", "hq"}) {
+		t.Errorf("got orgs %v", orgs)
+	}
+	srv.SetAPIKey("hq-key")
+	if _, err := must(pool.Client("hq")).Camera.GetCameraDevices(nil); err != nil {
+		t.Errorf("hq: %v", err)
+	}
+	srv.SetAPIKey("branch-key")
+	if _, err := must(pool.Client("branch")).Camera.GetCameraDevices(nil); err != nil {
+		t.Errorf("branch: %v", err)
+	}
+
+	for name, content := range map[string]string{
+		"malformed":       `[{"name": "hq"`,
+		"no name":         `[{"api_key": "k"}]`,
+		"duplicate names": `[{"name": "hq", "api_key": "k"}, {"name": "hq", "api_key": "k"}]`,
+		"no key":          `[{"name": "hq"}]`,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		profiles, err := client.LoadOrgProfiles(path)
+		if err == nil {
+			_, err = client.NewClientPool(profiles, nil)
+		}
+		if err == nil {
+			t.Errorf("%s: got no error", name)
+		}
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault verkadatest.Fault
+		// expire the Client's auth token first, so that the request waits for a new one
+		expire       bool
+		wantRequests int
+	}{
+		{"slow response", verkadatest.Fault{Path: "/cameras/v1/devices", Delay: time.Minute}, false, 1},
+		{"retry backoff", verkadatest.Fault{Path: "/cameras/v1/devices", StatusCode: http.StatusServiceUnavailable, RetryAfter: "60"}, false, 1},
+		{"token refresh", verkadatest.Fault{Path: "/token", Delay: time.Minute}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer(t)
+			c := newClient(t, srv, client.ClientOptions{})
+			if tt.expire {
+				srv.ExpireTokens()
+			}
+			srv.InjectFault(tt.fault)
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			start := time.Now()
+			_, err := c.Camera.GetCameraDevicesContext(ctx, nil)
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got %v, want context.Canceled", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("returned %v after ctx was cancelled", elapsed)
+			}
+			if n := requestsTo(srv, "/cameras/v1/devices"); n != tt.wantRequests {
+				t.Errorf("sent %d requests, want %d", n, tt.wantRequests)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package verkadatest
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// A Collection names a set of items kept by a Server and served by one or more endpoints.
+type Collection string
+
+// Collections served by the Server, with the item type used to seed each one and the endpoints that use it.
+const (
+	Cameras                 Collection = "cameras"                    // client.CameraDevice: GET /cameras/v1/devices
+	CameraAlerts            Collection = "camera_alerts"              // client.Notification: GET /cameras/v1/alerts
+	ObjectCounts            Collection = "object_counts"              // client.ObjectCount: GET /cameras/v1/analytics/object_counts
+	SeenPlates              Collection = "seen_plates"                // client.PlateDetection: GET /cameras/v1/analytics/lpr/images
+	PlateTimestamps         Collection = "plate_timestamps"           // int: GET /cameras/v1/analytics/lpr/timestamps
+	LicensePlatesOfInterest Collection = "license_plates_of_interest" // client.LicensePlateOfInterest: /cameras/v1/analytics/lpr/license_plate_of_interest
+	PersonsOfInterest       Collection = "persons_of_interest"        // client.POIProfile: /cameras/v1/people/person_of_interest
+	HelixEventTypes         Collection = "helix_event_types"          // /cameras/v1/video_tagging/event_type
+	AuditLogs               Collection = "audit_logs"                 // client.AuditLog: GET /core/v1/audit_log
+	Users                   Collection = "users"                      // client.GetUserResponse: /core/v1/user
+	AccessGroups            Collection = "access_groups"              // client.AccessGroup: /access/v1/access_groups
+	AccessUsers             Collection = "access_users"               // client.AccessUser: /access/v1/access_users
+	AccessLevels            Collection = "access_levels"              // client.AccessLevel: /access/v1/door/access_level
+	DoorExceptionCalendars  Collection = "door_exception_calendars"   // client.DoorExceptionCalendar: /access/v1/door/exception_calendar
+	Doors                   Collection = "doors"                      // client.Door: GET /access/v1/doors
+	AccessEvents            Collection = "access_events"              // client.Events: GET /events/v1/access
+	AccessScenarios         Collection = "access_scenarios"           // client.AccessScenario: GET /access/v1/scenarios
+	SensorAlerts            Collection = "sensor_alerts"              // client.SensorAlertEvent: GET /environment/v1/alerts
+	SensorData              Collection = "sensor_data"                // client.SensorReading: GET /environment/v1/data
+	GuestSites              Collection = "guest_sites"                // GET /guest/v1/sites
+	GuestVisits             Collection = "guest_visits"               // client.GuestVisit: GET /guest/v1/visits
+	GuestTypes              Collection = "guest_types"                // client.GuestType: GET /v2/guest/guest_types
+	GuestHosts              Collection = "guest_hosts"                // client.Host: GET /v2/guest/hosts
+	AlarmDevices            Collection = "alarm_devices"              // GET /alarms/v1/devices
+	AlarmSites              Collection = "alarm_sites"                // GET /alarms/v1/sites
+	ViewingStations         Collection = "viewing_stations"           // GET /viewing_station/v1/devices
+)
+
+// JSON field identifying the items of each collection, used for lookups and assigned on creation if missing.
+// Collections without one can only be listed.
+var idFields = map[Collection]string{
+	Cameras:                 "camera_id",
+	LicensePlatesOfInterest: "license_plate",
+	PersonsOfInterest:       "person_id",
+	HelixEventTypes:         "event_type_uid",
+	Users:                   "user_id",
+	AccessGroups:            "group_id",
+	AccessUsers:             "user_id",
+	AccessLevels:            "access_level_id",
+	DoorExceptionCalendars:  "door_exception_calendar_id",
+	Doors:                   "door_id",
+	AccessEvents:            "event_id",
+	AccessScenarios:         "scenario_id",
+	SensorAlerts:            "alert_event_id",
+	GuestSites:              "site_id",
+	GuestVisits:             "visit_id",
+	GuestTypes:              "guest_type_id",
+	GuestHosts:              "host_id",
+	AlarmDevices:            "device_id",
+	AlarmSites:              "site_id",
+	ViewingStations:         "device_id",
+}
+
+// Items are kept as decoded JSON (usually map[string]any) so that any struct, map, or value can be seeded.
+type collection struct {
+	items []any
+}
+
+// Adds items to a collection. Items can be values of the client package's response types, maps, or anything
+// else that encodes to the JSON the endpoint should return. Items without an ID are assigned one.
+// Panics if an item cannot be encoded.
+func (s *Server) Seed(c Collection, items ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range items {
+		v, err := toJSONValue(item)
+		if err != nil {
+			panic(fmt.Sprintf("verkadatest: cannot seed %s with %T: %v", c, item, err))
+		}
+		s.addLocked(c, v)
+	}
+}
+
+// Returns a copy of the items in a collection, decoded from JSON.
+func (s *Server) Items(c Collection) []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := make([]any, 0, len(s.collection(c).items))
+	for _, item := range s.collection(c).items {
+		v, _ := toJSONValue(item)
+		items = append(items, v)
+	}
+	return items
+}
+
+// Decodes the items in a collection into v, which should be a pointer to a slice, e.g. *[]client.CameraDevice.
+func (s *Server) Decode(c Collection, v any) error {
+	s.mu.Lock()
+	b, err := json.Marshal(s.collection(c).items)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Removes every item from a collection.
+func (s *Server) Reset(c Collection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collection(c).items = nil
+}
+
+// Returns the collection, creating it if needed. s.mu must be held.
+func (s *Server) collection(c Collection) *collection {
+	col, ok := s.collections[c]
+	if !ok {
+		col = &collection{}
+		s.collections[c] = col
+	}
+	return col
+}
+
+// Appends v to a collection, assigning an ID if the collection has an ID field and v lacks one. s.mu must be held.
+func (s *Server) addLocked(c Collection, v any) any {
+	if obj, ok := v.(map[string]any); ok {
+		if field := idFields[c]; field != "" {
+			if id, _ := obj[field].(string); id == "" {
+				s.nextID++
+				obj[field] = fmt.Sprintf("%s-%d", c, s.nextID)
+			}
+		}
+	}
+	col := s.collection(c)
+	col.items = append(col.items, v)
+	return v
+}
+
+// Returns the index of the first item whose field equals value, or -1. s.mu must be held.
+func (s *Server) findLocked(c Collection, field string, value string) int {
+	for i, item := range s.collection(c).items {
+		if obj, ok := item.(map[string]any); ok && fmt.Sprint(obj[field]) == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// Round trips v through JSON so that stored items never alias values owned by tests or handlers.
+func toJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
@@ -1,13 +1,22 @@
 package client
 
+import "context"
+
 // Gets information about the all the Viewing Stations in an organization.
 //
 // [Verkada API Docs - Get Viewing Station Devices]
 //
 // [Verkada API Docs - Get Viewing Station Devices]: https://apidocs.verkada.com/reference/getviewingstationdevicesviewv1
 func (c *VXClient) GetVXDevices() (*GetVXDevicesResponse, error) {
+	return c.GetVXDevicesContext(context.Background())
+}
+
+// Same as GetVXDevices, with ctx controlling cancellation and deadlines of the underlying requests.
+func (c *VXClient) GetVXDevicesContext(ctx context.Context) (*GetVXDevicesResponse, error) {
+	ctx, span := c.client.startSpan(ctx, "VX.GetVXDevices")
+	defer span.End()
 	var ret GetVXDevicesResponse
 	url := c.client.baseURL + "/viewing_station/v1/devices"
-	err := c.client.MakeVerkadaRequest("GET", url, nil, nil, &ret, 0)
+	err := c.client.MakeVerkadaRequestContext(ctx, "GET", url, nil, nil, &ret, 0)
 	return &ret, err
 }